@@ -0,0 +1,124 @@
+// Package assistant runs the price-check chat: it sends conversations to a language model
+// and executes the tools the model calls against market prices and the user's inventory.
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/TyronOdame/CS-OPN/backend/llm"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxToolRounds bounds how many times one reply may go back to the model with tool results
+const maxToolRounds = 4
+
+// systemPrompt sets up the model for every conversation
+const systemPrompt = `You are the CS:OPN price-check assistant. CS:OPN is a CS2 case opening simulator
+played with Case Bucks; one Case Buck is worth one US dollar of market value.
+Use the price_check tool for market prices and the get_inventory tool for anything about the
+user's own items, and answer only with figures the tools returned. Market items are named like
+"StatTrak™ AK-47 | Redline (Field-Tested)". Keep answers short.`
+
+// Assistant answers chat messages with a language model and the price and inventory tools
+type Assistant struct {
+	client llm.Client
+	prices pricing.Provider
+	db     *gorm.DB
+}
+
+// New creates an assistant
+func New(client llm.Client, prices pricing.Provider, db *gorm.DB) *Assistant {
+	return &Assistant{client: client, prices: prices, db: db}
+}
+
+// Model names the language model client answering the chat
+func (a *Assistant) Model() string {
+	return a.client.Name()
+}
+
+// Reply continues a conversation that ends with the user's message. It returns the messages
+// it added (tool calls, tool results and the final answer) and the tokens they consumed.
+func (a *Assistant) Reply(userID uuid.UUID, history []llm.Message) ([]llm.Message, llm.Usage, error) {
+	conversation := append([]llm.Message{{Role: llm.RoleSystem, Content: systemPrompt}}, history...)
+	added := []llm.Message{}
+	var usage llm.Usage
+
+	for round := 0; ; round++ {
+		request := llm.Request{Messages: conversation, Tools: tools}
+		// out of rounds: make the model answer with what it has
+		if round == maxToolRounds {
+			request.Tools = nil
+		}
+
+		response, err := a.client.Complete(request)
+		if err != nil {
+			return nil, usage, err
+		}
+		usage.PromptTokens += response.Usage.PromptTokens
+		usage.CompletionTokens += response.Usage.CompletionTokens
+
+		message := response.Message
+		message.Role = llm.RoleAssistant
+		conversation = append(conversation, message)
+		added = append(added, message)
+		if len(message.ToolCalls) == 0 || request.Tools == nil {
+			return added, usage, nil
+		}
+
+		for _, call := range message.ToolCalls {
+			result := llm.Message{Role: llm.RoleTool, ToolCallID: call.ID, Content: a.runTool(userID, call)}
+			conversation = append(conversation, result)
+			added = append(added, result)
+		}
+	}
+}
+
+// runTool executes a tool call and returns its JSON result; failures are reported
+// to the model as {"error": ...} so it can tell the user
+func (a *Assistant) runTool(userID uuid.UUID, call llm.ToolCall) string {
+	var result interface{}
+	var err error
+	switch call.Name {
+	case toolPriceCheck:
+		var args priceCheckArgs
+		if err = json.Unmarshal([]byte(call.Arguments), &args); err == nil {
+			result, err = a.priceCheck(args)
+		}
+	case toolInventory:
+		var args inventoryArgs
+		if err = json.Unmarshal([]byte(call.Arguments), &args); err == nil {
+			result, err = a.inventory(userID, args)
+		}
+	default:
+		err = fmt.Errorf("unknown tool %s", call.Name)
+	}
+
+	if err != nil {
+		var toolErr *toolError
+		if !errors.As(err, &toolErr) {
+			log.Printf("⚠️  Assistant tool %s failed: %v", call.Name, err)
+			err = &toolError{"that lookup failed, please try again later"}
+		}
+		result = map[string]string{"error": err.Error()}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return `{"error": "the tool result could not be encoded"}`
+	}
+	return string(data)
+}
+
+// toolError is a failure worth showing to the model (and so the user) as is
+type toolError struct {
+	message string
+}
+
+func (e *toolError) Error() string {
+	return e.message
+}
@@ -0,0 +1,205 @@
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TyronOdame/CS-OPN/backend/llm"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+	"github.com/google/uuid"
+)
+
+// toolLoopClient asks for a price check whenever it is offered tools and records the requests
+type toolLoopClient struct {
+	requests []llm.Request
+}
+
+func (c *toolLoopClient) Name() string {
+	return "loop"
+}
+
+func (c *toolLoopClient) Complete(req llm.Request) (*llm.Response, error) {
+	c.requests = append(c.requests, req)
+	if req.Tools == nil {
+		return &llm.Response{Message: llm.Message{Content: "done"}, Usage: llm.Usage{PromptTokens: 1}}, nil
+	}
+	return &llm.Response{
+		Message: llm.Message{ToolCalls: []llm.ToolCall{{
+			ID:        fmt.Sprintf("call_%d", len(c.requests)),
+			Name:      toolPriceCheck,
+			Arguments: `{"skin_name": "AK-47 | Redline", "condition": "Field-Tested"}`,
+		}}},
+		Usage: llm.Usage{PromptTokens: 1, CompletionTokens: 1},
+	}, nil
+}
+
+// erroringClient fails every completion
+type erroringClient struct{}
+
+func (erroringClient) Name() string {
+	return "erroring"
+}
+
+func (erroringClient) Complete(llm.Request) (*llm.Response, error) {
+	return nil, errors.New("model unavailable")
+}
+
+func TestReplyRunsPriceCheckRoundTrip(t *testing.T) {
+	prices := pricing.NewMockProvider()
+	a := New(llm.NewStubClient(), prices, nil)
+
+	added, usage, err := a.Reply(uuid.New(), []llm.Message{
+		{Role: llm.RoleUser, Content: "What is AWP | Asiimov (Field-Tested) worth?"},
+	})
+	if err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+	if len(added) != 3 {
+		t.Fatalf("Reply added %d messages, want a tool call, its result and the answer: %+v", len(added), added)
+	}
+
+	call, result, answer := added[0], added[1], added[2]
+	if call.Role != llm.RoleAssistant || len(call.ToolCalls) != 1 || call.ToolCalls[0].Name != toolPriceCheck {
+		t.Fatalf("first message = %+v, want a price_check call", call)
+	}
+	if result.Role != llm.RoleTool || result.ToolCallID != call.ToolCalls[0].ID {
+		t.Fatalf("second message = %+v, want the result of %s", result, call.ToolCalls[0].ID)
+	}
+
+	var checked priceCheckResult
+	if err := json.Unmarshal([]byte(result.Content), &checked); err != nil {
+		t.Fatalf("tool result %q: %v", result.Content, err)
+	}
+	quote, _ := prices.Quote("AWP | Asiimov (Field-Tested)")
+	if checked.MarketHashName != "AWP | Asiimov (Field-Tested)" || checked.PriceUSD != quote.Suggested {
+		t.Fatalf("tool result = %+v, want the mock price %.2f", checked, quote.Suggested)
+	}
+	if answer.Role != llm.RoleAssistant || answer.Content != checked.Summary {
+		t.Fatalf("answer = %+v, want the tool summary %q", answer, checked.Summary)
+	}
+	if usage.PromptTokens == 0 || usage.CompletionTokens == 0 {
+		t.Fatalf("usage = %+v, want both rounds counted", usage)
+	}
+}
+
+func TestReplyAnswersWithoutToolsWhenNotNeeded(t *testing.T) {
+	a := New(llm.NewStubClient(), pricing.NewMockProvider(), nil)
+
+	added, _, err := a.Reply(uuid.New(), []llm.Message{{Role: llm.RoleUser, Content: "hello"}})
+	if err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+	if len(added) != 1 || len(added[0].ToolCalls) != 0 || added[0].Content == "" {
+		t.Fatalf("Reply = %+v, want a single plain answer", added)
+	}
+}
+
+func TestReplyStopsOfferingToolsAfterMaxRounds(t *testing.T) {
+	client := &toolLoopClient{}
+	a := New(client, pricing.NewMockProvider(), nil)
+
+	added, usage, err := a.Reply(uuid.New(), []llm.Message{{Role: llm.RoleUser, Content: "price everything"}})
+	if err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+
+	if len(client.requests) != maxToolRounds+1 {
+		t.Fatalf("model was asked %d times, want %d", len(client.requests), maxToolRounds+1)
+	}
+	for i, req := range client.requests[:maxToolRounds] {
+		if len(req.Tools) == 0 {
+			t.Fatalf("round %d offered no tools", i)
+		}
+	}
+	if client.requests[maxToolRounds].Tools != nil {
+		t.Fatal("the last round still offered tools")
+	}
+
+	// every round but the last added a call and its result
+	if len(added) != 2*maxToolRounds+1 || added[len(added)-1].Content != "done" {
+		t.Fatalf("Reply added %d messages ending in %+v, want %d ending in the answer", len(added), added[len(added)-1], 2*maxToolRounds+1)
+	}
+	if usage.PromptTokens != maxToolRounds+1 || usage.CompletionTokens != maxToolRounds {
+		t.Fatalf("usage = %+v, want every round counted", usage)
+	}
+}
+
+func TestReplyReturnsClientErrors(t *testing.T) {
+	a := New(erroringClient{}, pricing.NewMockProvider(), nil)
+	if _, _, err := a.Reply(uuid.New(), []llm.Message{{Role: llm.RoleUser, Content: "hi"}}); err == nil {
+		t.Fatal("Reply hid the client error")
+	}
+}
+
+func TestRunToolReportsToolErrorsAndMasksInternalOnes(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  pricing.Provider
+		call      llm.ToolCall
+		wantError string
+	}{
+		{
+			name:      "missing skin name",
+			provider:  pricing.NewMockProvider(),
+			call:      llm.ToolCall{Name: toolPriceCheck, Arguments: `{"skin_name": " "}`},
+			wantError: "skin_name is required",
+		},
+		{
+			name:      "invalid condition",
+			provider:  pricing.NewMockProvider(),
+			call:      llm.ToolCall{Name: toolPriceCheck, Arguments: `{"skin_name": "AK-47 | Redline", "condition": "Mint"}`},
+			wantError: `"Mint" is not a wear condition`,
+		},
+		{
+			name:      "no market price",
+			provider:  &failingProvider{err: pricing.ErrNotFound},
+			call:      llm.ToolCall{Name: toolPriceCheck, Arguments: `{"skin_name": "AK-47 | Redline"}`},
+			wantError: "there is no market price for AK-47 | Redline",
+		},
+		{
+			name:      "breaker open",
+			provider:  &failingProvider{err: pricing.ErrUnavailable},
+			call:      llm.ToolCall{Name: toolPriceCheck, Arguments: `{"skin_name": "AK-47 | Redline"}`},
+			wantError: "market prices are unavailable right now",
+		},
+		{
+			name:      "provider failure is masked",
+			provider:  &failingProvider{err: errors.New("dial tcp 10.0.0.7:443: connection refused")},
+			call:      llm.ToolCall{Name: toolPriceCheck, Arguments: `{"skin_name": "AK-47 | Redline"}`},
+			wantError: "that lookup failed, please try again later",
+		},
+		{
+			name:      "invalid arguments are masked",
+			provider:  pricing.NewMockProvider(),
+			call:      llm.ToolCall{Name: toolPriceCheck, Arguments: `{"skin_name": 47`},
+			wantError: "that lookup failed, please try again later",
+		},
+		{
+			name:      "unknown tool is masked",
+			provider:  pricing.NewMockProvider(),
+			call:      llm.ToolCall{Name: "drop_database", Arguments: `{}`},
+			wantError: "that lookup failed, please try again later",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(llm.NewStubClient(), tt.provider, nil)
+			content := a.runTool(uuid.New(), tt.call)
+
+			var result map[string]string
+			if err := json.Unmarshal([]byte(content), &result); err != nil {
+				t.Fatalf("runTool returned %q: %v", content, err)
+			}
+			if result["error"] != tt.wantError {
+				t.Fatalf("error = %q, want %q", result["error"], tt.wantError)
+			}
+			if strings.Contains(content, "10.0.0.7") {
+				t.Fatalf("internal error details leaked to the model: %s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package assistant
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/TyronOdame/CS-OPN/backend/llm"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tool names offered to the model
+const (
+	toolPriceCheck = "price_check"
+	toolInventory  = "get_inventory"
+)
+
+// maxInventoryResults caps how many items one inventory lookup returns to the model
+const maxInventoryResults = 20
+
+// tools are the functions the model may call
+var tools = []llm.Tool{
+	{
+		Name:        toolPriceCheck,
+		Description: "Look up the current market price in USD of a CS2 skin.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"skin_name": map[string]interface{}{
+					"type":        "string",
+					"description": `Skin name without wear, e.g. "AK-47 | Redline" or "★ Karambit | Fade"`,
+				},
+				"condition": map[string]interface{}{
+					"type":        "string",
+					"enum":        models.Conditions,
+					"description": "Wear condition; omit it to price the best condition on the market",
+				},
+				"stattrak": map[string]interface{}{"type": "boolean"},
+				"souvenir": map[string]interface{}{"type": "boolean"},
+			},
+			"required": []string{"skin_name"},
+		},
+	},
+	{
+		Name:        toolInventory,
+		Description: "List the user's unsold items with their sell value in Case Bucks and their market price.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": `Words of the skin name to filter by, e.g. "AK-47 Redline"; omit to list everything`,
+				},
+			},
+		},
+	},
+}
+
+// priceCheckArgs are the arguments of the price_check tool
+type priceCheckArgs struct {
+	SkinName  string `json:"skin_name"`
+	Condition string `json:"condition"`
+	StatTrak  bool   `json:"stattrak"`
+	Souvenir  bool   `json:"souvenir"`
+}
+
+// priceCheckResult is returned to the model for a price_check call
+type priceCheckResult struct {
+	MarketHashName string  `json:"market_hash_name"`
+	PriceUSD       float64 `json:"price_usd"`
+	MinUSD         float64 `json:"min_usd,omitempty"`
+	MaxUSD         float64 `json:"max_usd,omitempty"`
+	Provider       string  `json:"provider"`
+	Stale          bool    `json:"stale,omitempty"`
+	Summary        string  `json:"summary"`
+}
+
+// priceCheck quotes a skin from the price provider
+func (a *Assistant) priceCheck(args priceCheckArgs) (*priceCheckResult, error) {
+	if strings.TrimSpace(args.SkinName) == "" {
+		return nil, &toolError{"skin_name is required"}
+	}
+	if args.Condition != "" && !models.IsValidCondition(args.Condition) {
+		return nil, &toolError{fmt.Sprintf("%q is not a wear condition", args.Condition)}
+	}
+
+	quote, err := pricing.QuoteSkin(a.prices, args.SkinName, args.Condition, args.StatTrak, args.Souvenir)
+	if errors.Is(err, pricing.ErrNotFound) {
+		return nil, &toolError{fmt.Sprintf("there is no market price for %s", args.SkinName)}
+	}
+	if errors.Is(err, pricing.ErrUnavailable) {
+		return nil, &toolError{"market prices are unavailable right now"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("%s sells for about $%.2f on the market.", quote.MarketHashName, quote.Suggested)
+	if quote.Stale {
+		summary += " (This price is cached; the market could not be reached.)"
+	}
+	return &priceCheckResult{
+		MarketHashName: quote.MarketHashName,
+		PriceUSD:       quote.Suggested,
+		MinUSD:         quote.Min,
+		MaxUSD:         quote.Max,
+		Provider:       quote.Provider,
+		Stale:          quote.Stale,
+		Summary:        summary,
+	}, nil
+}
+
+// inventoryArgs are the arguments of the get_inventory tool
+type inventoryArgs struct {
+	Query string `json:"query"`
+}
+
+// inventoryItem is one of the user's items as returned to the model
+type inventoryItem struct {
+	ID             uuid.UUID    `json:"id"`
+	Name           string       `json:"name"`
+	Condition      string       `json:"condition"`
+	Float          float64      `json:"float"`
+	SellValue      models.Money `json:"sell_value_case_bucks"`
+	MarketPriceUSD *float64     `json:"market_price_usd,omitempty"`
+}
+
+// inventoryResult is returned to the model for a get_inventory call
+type inventoryResult struct {
+	Items   []inventoryItem `json:"items"`
+	More    int             `json:"more,omitempty"` // matching items left out
+	Summary string          `json:"summary"`
+}
+
+// inventory lists the user's unsold items matching the query with their sell value and market price
+func (a *Assistant) inventory(userID uuid.UUID, args inventoryArgs) (*inventoryResult, error) {
+	matching := parseItemQuery(args.Query).filter(a.db.Model(&models.Inventory{}).
+		Joins("JOIN skins ON skins.id = inventories.skin_id").
+		Where("inventories.user_id = ? AND inventories.is_sold = ?", userID, false)).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := matching.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var items []models.Inventory
+	err := matching.Preload("Skin").
+		Order("inventories.value DESC").
+		Limit(maxInventoryResults).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &inventoryResult{Items: []inventoryItem{}, More: int(total) - len(items)}
+	lines := []string{}
+	pricesDown := false
+	for i := range items {
+		item := &items[i]
+		entry := inventoryItem{
+			ID:        item.ID,
+			Name:      item.DisplayName(),
+			Condition: item.GetCondition(),
+			Float:     item.Float,
+			SellValue: item.Value,
+		}
+		line := fmt.Sprintf("Your %s (%s, float %.4f) sells here for %s Case Bucks", entry.Name, entry.Condition, entry.Float, item.Value)
+
+		// once the provider fails (or its breaker is open) the other quotes would fail too
+		if !pricesDown {
+			quote, err := a.prices.Quote(pricing.MarketHashName(item.Skin.Name, entry.Condition, item.IsStatTrak, item.IsSouvenir))
+			switch {
+			case err == nil:
+				price := quote.Suggested
+				entry.MarketPriceUSD = &price
+				line += fmt.Sprintf("; the market price is about $%.2f", price)
+			case !errors.Is(err, pricing.ErrNotFound):
+				pricesDown = true
+			}
+		}
+		result.Items = append(result.Items, entry)
+		lines = append(lines, line+".")
+	}
+
+	switch {
+	case len(result.Items) == 0 && args.Query != "":
+		result.Summary = "You don't have any unsold items matching that."
+	case len(result.Items) == 0:
+		result.Summary = "Your inventory is empty."
+	default:
+		if result.More > 0 {
+			lines = append(lines, fmt.Sprintf("...and %d more items.", result.More))
+		}
+		if pricesDown {
+			lines = append(lines, "Market prices are unavailable right now.")
+		}
+		result.Summary = strings.Join(lines, "\n")
+	}
+	return result, nil
+}
+
+// itemQuery is an inventory query split into words
+type itemQuery struct {
+	words    []string // every word of the query
+	rest     []string // the words other than the variant prefixes
+	statTrak bool     // the query mentions StatTrak
+	souvenir bool     // the query mentions Souvenir
+}
+
+// parseItemQuery splits a query into words like nameWords does for item names
+func parseItemQuery(query string) itemQuery {
+	q := itemQuery{words: nameWords(query)}
+	for _, word := range q.words {
+		switch word {
+		case "stattrak":
+			q.statTrak = true
+		case "souvenir":
+			q.souvenir = true
+		default:
+			q.rest = append(q.rest, word)
+		}
+	}
+	return q
+}
+
+// skinNameWords splits skins.name in SQL the way nameWords does in Go
+const skinNameWords = `array_remove(regexp_split_to_array(lower(skins.name), '[^[:alnum:]-]+'), '')`
+
+// filter narrows an inventory query joined with skins to the items whose name fits the query:
+// either every word of the skin name appears in the query (a whole question like "what is my
+// AK-47 Redline worth", where the variant prefix is optional) or every word of the query
+// appears in the item's name ("redline", "stattrak redline"). An empty query keeps everything.
+func (q itemQuery) filter(db *gorm.DB) *gorm.DB {
+	if len(q.words) == 0 {
+		return db
+	}
+
+	queryInName := []string{}
+	// words never contain spaces, so they travel as one space-separated parameter
+	args := []interface{}{strings.Join(q.words, " ")}
+	if len(q.rest) > 0 {
+		queryInName = append(queryInName, skinNameWords+" @> string_to_array(?, ' ')")
+		args = append(args, strings.Join(q.rest, " "))
+	}
+	if q.statTrak {
+		queryInName = append(queryInName, "inventories.is_stat_trak")
+	}
+	if q.souvenir {
+		queryInName = append(queryInName, "inventories.is_souvenir")
+	}
+	return db.Where("("+skinNameWords+" <@ string_to_array(?, ' ') OR ("+strings.Join(queryInName, " AND ")+"))", args...)
+}
+
+// nameWords lowercases text and splits it into words, keeping hyphens and digits ("ak-47", "08")
+func nameWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
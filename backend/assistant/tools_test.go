@@ -0,0 +1,252 @@
+package assistant
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/llm"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// failingProvider answers every quote with the same error
+type failingProvider struct {
+	err   error
+	calls int
+}
+
+func (p *failingProvider) Name() string {
+	return "failing"
+}
+
+func (p *failingProvider) Quote(marketHashName string) (*pricing.Quote, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func TestParseItemQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  itemQuery
+	}{
+		{"", itemQuery{words: []string{}}},
+		{"  ?! ", itemQuery{words: []string{}}},
+		{"redline", itemQuery{words: []string{"redline"}, rest: []string{"redline"}}},
+		{
+			"What is my AK-47 Redline worth?",
+			itemQuery{
+				words: []string{"what", "is", "my", "ak-47", "redline", "worth"},
+				rest:  []string{"what", "is", "my", "ak-47", "redline", "worth"},
+			},
+		},
+		{
+			"StatTrak™ M4A1-S | Hyper Beast",
+			itemQuery{
+				words:    []string{"stattrak", "m4a1-s", "hyper", "beast"},
+				rest:     []string{"m4a1-s", "hyper", "beast"},
+				statTrak: true,
+			},
+		},
+		{"souvenir", itemQuery{words: []string{"souvenir"}, souvenir: true}},
+		{"★ Karambit | Doppler (Phase 2)", itemQuery{
+			words: []string{"karambit", "doppler", "phase", "2"},
+			rest:  []string{"karambit", "doppler", "phase", "2"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := parseItemQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseItemQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// The inventory tests need a disposable Postgres database, e.g.
+// TEST_DATABASE_URL="host=localhost user=postgres password=postgres dbname=csopn_test sslmode=disable"
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+// requireTestDB connects to the test database and migrates it, or skips the test
+func requireTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	testDBOnce.Do(func() {
+		database.DB, testDBErr = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if testDBErr != nil {
+			return
+		}
+		_, testDBErr = database.MigrateUp()
+	})
+	if testDBErr != nil {
+		t.Fatalf("test database setup failed: %v", testDBErr)
+	}
+	return database.DB
+}
+
+// testItem is an unsold item to give the test user
+type testItem struct {
+	skinName string
+	statTrak bool
+	value    models.Money
+}
+
+// createInventory creates a user owning the given items and returns the user's ID
+func createInventory(t *testing.T, db *gorm.DB, items []testItem) uuid.UUID {
+	t.Helper()
+	suffix := uuid.NewString()[:8]
+	user := models.User{
+		Email:    "assistant-" + suffix + "@example.com",
+		Username: "assistant-" + suffix,
+		Password: "not-a-real-hash",
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		for i, item := range items {
+			skin := models.Skin{
+				Slug:       "assistant-" + suffix + "-" + string(rune('a'+i)),
+				Name:       item.skinName,
+				WeaponType: "Rifle",
+				Rarity:     "Classified",
+				MaxFloat:   1,
+				IsActive:   true,
+			}
+			if err := tx.Create(&skin).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.Inventory{
+				UserID:       user.ID,
+				SkinID:       skin.ID,
+				Float:        0.2,
+				AcquiredFrom: "test",
+				Value:        item.value,
+				IsStatTrak:   item.statTrak,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to create inventory: %v", err)
+	}
+	return user.ID
+}
+
+func itemNames(result *inventoryResult) []string {
+	names := []string{}
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestInventoryMatchesQuery(t *testing.T) {
+	db := requireTestDB(t)
+	userID := createInventory(t, db, []testItem{
+		{skinName: "AK-47 | Redline", value: 400},
+		{skinName: "AK-47 | Redline", statTrak: true, value: 300},
+		{skinName: "AWP | Asiimov", value: 200},
+		{skinName: "★ Karambit | Fade", value: 100},
+	})
+	a := New(llm.NewStubClient(), pricing.NewMockProvider(), db)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"AK-47 | Redline", "StatTrak™ AK-47 | Redline", "AWP | Asiimov", "★ Karambit | Fade"}},
+		{"what is my AK-47 Redline worth", []string{"AK-47 | Redline", "StatTrak™ AK-47 | Redline"}},
+		{"What is my StatTrak AK-47 | Redline worth?", []string{"AK-47 | Redline", "StatTrak™ AK-47 | Redline"}},
+		{"redline", []string{"AK-47 | Redline", "StatTrak™ AK-47 | Redline"}},
+		{"StatTrak Redline", []string{"StatTrak™ AK-47 | Redline"}},
+		{"stattrak", []string{"StatTrak™ AK-47 | Redline"}},
+		{"karambit", []string{"★ Karambit | Fade"}},
+		{"REDLINE ASIIMOV", []string{}},
+		{"what is my AK-47 worth", []string{}},
+		{"Howl", []string{}},
+	}
+	for _, tt := range tests {
+		result, err := a.inventory(userID, inventoryArgs{Query: tt.query})
+		if err != nil {
+			t.Fatalf("inventory(%q): %v", tt.query, err)
+		}
+		if got := itemNames(result); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("inventory(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestInventoryLimitsResults(t *testing.T) {
+	db := requireTestDB(t)
+	items := []testItem{}
+	for i := 0; i < maxInventoryResults+3; i++ {
+		items = append(items, testItem{skinName: "P250 | Sand Dune", value: models.Money(i + 1)})
+	}
+	userID := createInventory(t, db, items)
+	a := New(llm.NewStubClient(), pricing.NewMockProvider(), db)
+
+	result, err := a.inventory(userID, inventoryArgs{Query: "sand dune"})
+	if err != nil {
+		t.Fatalf("inventory: %v", err)
+	}
+	if len(result.Items) != maxInventoryResults || result.More != 3 {
+		t.Fatalf("inventory returned %d items and %d more, want %d and 3", len(result.Items), result.More, maxInventoryResults)
+	}
+	if result.Items[0].SellValue != models.Money(maxInventoryResults+3) {
+		t.Errorf("first item is worth %s, want the most valuable first", result.Items[0].SellValue)
+	}
+	if !strings.Contains(result.Summary, "...and 3 more items.") {
+		t.Errorf("summary %q does not mention the items left out", result.Summary)
+	}
+}
+
+func TestInventoryStopsQuotingWhenPricesAreDown(t *testing.T) {
+	db := requireTestDB(t)
+	userID := createInventory(t, db, []testItem{
+		{skinName: "AK-47 | Redline", value: 300},
+		{skinName: "AWP | Asiimov", value: 200},
+		{skinName: "★ Karambit | Fade", value: 100},
+	})
+	prices := &failingProvider{err: pricing.ErrUnavailable}
+	a := New(llm.NewStubClient(), prices, db)
+
+	result, err := a.inventory(userID, inventoryArgs{})
+	if err != nil {
+		t.Fatalf("inventory: %v", err)
+	}
+	if len(result.Items) != 3 || prices.calls != 1 {
+		t.Fatalf("inventory returned %d items after %d quotes, want 3 items after 1", len(result.Items), prices.calls)
+	}
+	for _, item := range result.Items {
+		if item.MarketPriceUSD != nil {
+			t.Errorf("%s has a market price", item.Name)
+		}
+	}
+	if !strings.Contains(result.Summary, "Market prices are unavailable right now.") {
+		t.Errorf("summary %q does not say prices are unavailable", result.Summary)
+	}
+
+	// a missing listing is an answer, not an outage
+	notFound := &failingProvider{err: pricing.ErrNotFound}
+	a = New(llm.NewStubClient(), notFound, db)
+	if _, err := a.inventory(userID, inventoryArgs{}); err != nil || notFound.calls != 3 {
+		t.Fatalf("inventory with unlisted items = %v after %d quotes, want every item quoted", err, notFound.calls)
+	}
+}
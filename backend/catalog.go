@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/TyronOdame/CS-OPN/backend/catalog"
+	"github.com/TyronOdame/CS-OPN/backend/database"
+)
+
+// runCatalogCommand imports a catalog file into the database or exports the database to one.
+// Usage: backend catalog import [-dry-run] <file> | export <file>
+func runCatalogCommand(cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: catalog import [-dry-run] <file> | export <file>")
+	}
+
+	switch args[0] {
+	case "import":
+		flags := flag.NewFlagSet("catalog import", flag.ContinueOnError)
+		dryRun := flags.Bool("dry-run", false, "print the changes without applying them")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: catalog import [-dry-run] <file>")
+		}
+
+		// validate the file before connecting so typos are reported right away
+		file, err := catalog.Load(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		if err := connectDatabase(cfg); err != nil {
+			return err
+		}
+
+		diff, err := catalog.Import(database.DB, file, *dryRun)
+		if err != nil {
+			return err
+		}
+		fmt.Print(diff.String())
+		if *dryRun {
+			log.Println("🔍 Dry run: nothing was changed")
+		} else {
+			log.Printf("📦 Imported %s", flags.Arg(0))
+		}
+		return nil
+
+	case "export":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: catalog export <file>")
+		}
+		if err := connectDatabase(cfg); err != nil {
+			return err
+		}
+
+		exported, err := catalog.Export(database.DB)
+		if err != nil {
+			return err
+		}
+		if err := catalog.Write(args[1], exported); err != nil {
+			return err
+		}
+		log.Printf("📦 Exported %d skins and %d cases to %s", len(exported.Skins), len(exported.Cases), args[1])
+		return nil
+	}
+
+	return fmt.Errorf("unknown catalog command %q", args[0])
+}
@@ -0,0 +1,347 @@
+// Package catalog reads, validates, imports and exports the skin and case catalog
+// as YAML or JSON files keyed by stable slugs.
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/goccy/go-yaml"
+)
+
+// Catalog is the file format for the skins and cases on offer and the odds of every case
+type Catalog struct {
+	Rarities []Rarity `json:"rarities,omitempty"`
+	Skins    []Skin   `json:"skins"`
+	Cases    []Case   `json:"cases"`
+}
+
+// Rarity sets the drop weight every skin of the rarity gets in a case unless the content sets its own
+type Rarity struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Skin is a skin of the catalog. Amounts are in Case Bucks.
+// Prices are keyed by wear condition; without them they are derived from min_value/max_value.
+// Skins already in the database may leave out image_url and prices to keep their current values,
+// which sync-images and the price recompute maintain.
+type Skin struct {
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+	WeaponType  string             `json:"weapon_type"`
+	Rarity      string             `json:"rarity"`
+	MinFloat    *float64           `json:"min_float,omitempty"`
+	MaxFloat    *float64           `json:"max_float,omitempty"`
+	ImageURL    string             `json:"image_url"`
+	MinValue    float64            `json:"min_value,omitempty"`
+	MaxValue    float64            `json:"max_value,omitempty"`
+	Prices      map[string]float64 `json:"prices,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Active      *bool              `json:"active,omitempty"`
+}
+
+// Case is a case of the catalog and its drop table. Like skins, cases already in the database
+// may leave out image_url to keep the current one.
+type Case struct {
+	Slug            string                `json:"slug"`
+	Name            string                `json:"name"`
+	Price           float64               `json:"price"`
+	ImageURL        string                `json:"image_url"`
+	Description     string                `json:"description,omitempty"`
+	Active          *bool                 `json:"active,omitempty"`
+	StatTrakChance  *float64              `json:"stattrak_chance,omitempty"`
+	SouvenirPackage bool                  `json:"souvenir_package,omitempty"`
+	BulkDiscounts   []models.BulkDiscount `json:"bulk_discounts,omitempty"`
+	Contents        []Content             `json:"contents"`
+}
+
+// Content is a skin of a case. Weights are relative; each case is normalized so its chances sum to 1.
+type Content struct {
+	Skin   string  `json:"skin"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// Load reads a catalog file; the format follows the extension (.yaml, .yml or .json)
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, formatOf(path))
+}
+
+// Parse decodes a catalog, rejecting unknown fields, and validates it
+func Parse(data []byte, format string) (*Catalog, error) {
+	var catalog Catalog
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&catalog); err != nil {
+			return nil, fmt.Errorf("invalid catalog JSON: %w", err)
+		}
+	case "yaml":
+		if err := yaml.UnmarshalWithOptions(data, &catalog, yaml.DisallowUnknownField()); err != nil {
+			return nil, fmt.Errorf("invalid catalog YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown catalog format %q (use yaml or json)", format)
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// Marshal encodes a catalog as YAML or JSON
+func Marshal(catalog *Catalog, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(catalog, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case "yaml":
+		return yaml.MarshalWithOptions(catalog, yaml.Indent(2), yaml.IndentSequence(true))
+	}
+	return nil, fmt.Errorf("unknown catalog format %q (use yaml or json)", format)
+}
+
+// Write saves a catalog to a file in the format of its extension
+func Write(path string, catalog *Catalog) error {
+	data, err := Marshal(catalog, formatOf(path))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// formatOf returns the catalog format of a file name
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return strings.TrimPrefix(filepath.Ext(path), ".")
+}
+
+// Validate checks every skin, case and drop table of the catalog and reports all problems at once.
+// Contents may name skins that are not in the file; the importer resolves those from the database.
+func (c *Catalog) Validate() error {
+	var problems []error
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	rarityWeights := map[string]float64{}
+	for _, rarity := range c.Rarities {
+		if !models.IsValidRarity(rarity.Name) {
+			fail("rarities: unknown rarity %q", rarity.Name)
+		}
+		if rarity.Weight <= 0 {
+			fail("rarities: weight of %s must be greater than 0", rarity.Name)
+		}
+		rarityWeights[rarity.Name] = rarity.Weight
+	}
+
+	skinSlugs := map[string]bool{}
+	for i, skin := range c.Skins {
+		where := fmt.Sprintf("skins[%d] (%s)", i, skin.Slug)
+		if err := models.ValidateSlug(skin.Slug); err != nil {
+			fail("%s: %v", where, err)
+		}
+		if skinSlugs[skin.Slug] {
+			fail("%s: duplicate slug", where)
+		}
+		skinSlugs[skin.Slug] = true
+
+		if skin.Name == "" || skin.WeaponType == "" {
+			fail("%s: name and weapon_type are required", where)
+		}
+		if !models.IsValidRarity(skin.Rarity) {
+			fail("%s: unknown rarity %q", where, skin.Rarity)
+		}
+		if err := models.ValidateFloatRange(skin.floatRange()); err != nil {
+			fail("%s: %v", where, err)
+		}
+
+		if len(skin.Prices) > 0 {
+			priced := false
+			for condition, price := range skin.Prices {
+				if !models.IsValidCondition(condition) {
+					fail("%s: unknown condition %q", where, condition)
+				}
+				if price < 0 {
+					fail("%s: price of %s cannot be negative", where, condition)
+				}
+				priced = priced || price > 0
+			}
+			if !priced {
+				fail("%s: at least one condition needs a price", where)
+			}
+		} else if (skin.MinValue != 0 || skin.MaxValue != 0) &&
+			(skin.MaxValue <= 0 || skin.MinValue < 0 || skin.MinValue > skin.MaxValue) {
+			fail("%s: needs 0 <= min_value <= max_value with max_value > 0", where)
+		}
+	}
+
+	caseSlugs := map[string]bool{}
+	for i, item := range c.Cases {
+		where := fmt.Sprintf("cases[%d] (%s)", i, item.Slug)
+		if err := models.ValidateSlug(item.Slug); err != nil {
+			fail("%s: %v", where, err)
+		}
+		if caseSlugs[item.Slug] {
+			fail("%s: duplicate slug", where)
+		}
+		caseSlugs[item.Slug] = true
+
+		if item.Name == "" {
+			fail("%s: name is required", where)
+		}
+		if item.Price <= 0 {
+			fail("%s: price must be greater than 0", where)
+		}
+		if item.StatTrakChance != nil {
+			if err := models.ValidateStatTrakChance(*item.StatTrakChance); err != nil {
+				fail("%s: %v", where, err)
+			}
+		}
+		if err := models.ValidateBulkDiscounts(item.BulkDiscounts); err != nil {
+			fail("%s: %v", where, err)
+		}
+
+		if len(item.Contents) == 0 {
+			fail("%s: contents cannot be empty", where)
+		}
+		seen := map[string]bool{}
+		for _, content := range item.Contents {
+			if seen[content.Skin] {
+				fail("%s: skin %s appears more than once", where, content.Skin)
+			}
+			seen[content.Skin] = true
+			if content.Weight < 0 {
+				fail("%s: weight of %s cannot be negative", where, content.Skin)
+			}
+			if content.Weight == 0 {
+				skin := c.skin(content.Skin)
+				if skin == nil {
+					fail("%s: %s needs a weight because it is not defined in this file", where, content.Skin)
+				} else if rarityWeights[skin.Rarity] == 0 {
+					fail("%s: %s needs a weight or a weight for rarity %s", where, content.Skin, skin.Rarity)
+				}
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// skin returns the skin of the file with the slug, if any
+func (c *Catalog) skin(slug string) *Skin {
+	for i := range c.Skins {
+		if c.Skins[i].Slug == slug {
+			return &c.Skins[i]
+		}
+	}
+	return nil
+}
+
+// dropChances turns a case's weights into chances that sum to 1, keyed by skin slug
+func (c *Catalog) dropChances(item Case) map[string]float64 {
+	rarityWeights := map[string]float64{}
+	for _, rarity := range c.Rarities {
+		rarityWeights[rarity.Name] = rarity.Weight
+	}
+
+	weights := make(map[string]float64, len(item.Contents))
+	var total float64
+	for _, content := range item.Contents {
+		weight := content.Weight
+		if weight == 0 {
+			weight = rarityWeights[c.skin(content.Skin).Rarity]
+		}
+		weights[content.Skin] = weight
+		total += weight
+	}
+
+	for slug := range weights {
+		weights[slug] /= total
+	}
+	return weights
+}
+
+// floatRange returns the skin's float cap, defaulting to the full 0-1 range
+func (s *Skin) floatRange() (float64, float64) {
+	minFloat, maxFloat := 0.0, 1.0
+	if s.MinFloat != nil {
+		minFloat = *s.MinFloat
+	}
+	if s.MaxFloat != nil {
+		maxFloat = *s.MaxFloat
+	}
+	return minFloat, maxFloat
+}
+
+// hasPrices reports whether the catalog skin sets its prices (per condition or as a value range)
+func (s *Skin) hasPrices() bool {
+	return len(s.Prices) > 0 || s.MaxValue > 0
+}
+
+// apply copies the catalog skin onto a skin row, deriving condition prices and the value range.
+// An empty image_url or missing prices leave the row's current values alone.
+func (s *Skin) apply(skin *models.Skin) {
+	skin.Slug = s.Slug
+	skin.Name = s.Name
+	skin.WeaponType = s.WeaponType
+	skin.Rarity = s.Rarity
+	skin.MinFloat, skin.MaxFloat = s.floatRange()
+	if s.ImageURL != "" {
+		skin.ImageURL = s.ImageURL
+	}
+	skin.Description = s.Description
+	skin.IsActive = s.Active == nil || *s.Active
+	if !s.hasPrices() {
+		return
+	}
+
+	skin.MinValue = models.MoneyFromFloat(s.MinValue)
+	skin.MaxValue = models.MoneyFromFloat(s.MaxValue)
+	if len(s.Prices) > 0 {
+		for _, condition := range models.Conditions {
+			skin.SetConditionPrice(condition, models.MoneyFromFloat(s.Prices[condition]))
+		}
+	} else {
+		skin.DeriveConditionPrices(skin.MinValue, skin.MaxValue)
+	}
+	skin.SyncValueRange()
+}
+
+// apply copies the catalog case onto a case row; an empty image_url leaves the current one alone
+func (c *Case) apply(caseItem *models.Case) {
+	caseItem.Slug = c.Slug
+	caseItem.Name = c.Name
+	caseItem.Price = models.MoneyFromFloat(c.Price)
+	if c.ImageURL != "" {
+		caseItem.ImageURL = c.ImageURL
+	}
+	caseItem.Description = c.Description
+	caseItem.IsActive = c.Active == nil || *c.Active
+	caseItem.StatTrakChance = models.DefaultStatTrakChance
+	if c.StatTrakChance != nil {
+		caseItem.StatTrakChance = *c.StatTrakChance
+	}
+	caseItem.IsSouvenirPackage = c.SouvenirPackage
+	caseItem.BulkDiscounts = c.BulkDiscounts
+}
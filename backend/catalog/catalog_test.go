@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+)
+
+const testCatalog = `
+skins:
+  - slug: ak-47-redline
+    name: "AK-47 | Redline"
+    weapon_type: Rifle
+    rarity: Classified
+cases:
+  - slug: test-case
+    name: Test Case
+    price: 2.5
+    contents:
+      - skin: ak-47-redline
+        weight: 1
+`
+
+func TestParseAllowsOmittedImageAndPrices(t *testing.T) {
+	catalog, err := Parse([]byte(testCatalog), "yaml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if catalog.Skins[0].hasPrices() {
+		t.Fatal("skin without prices reports hasPrices")
+	}
+}
+
+func TestParseRejectsBadValueRange(t *testing.T) {
+	data := strings.Replace(testCatalog, "rarity: Classified", "rarity: Classified\n    min_value: 5\n    max_value: 2", 1)
+	if _, err := Parse([]byte(data), "yaml"); err == nil || !strings.Contains(err.Error(), "min_value") {
+		t.Fatalf("Parse error = %v, want a min_value problem", err)
+	}
+}
+
+func TestSkinApplyKeepsOmittedImageAndPrices(t *testing.T) {
+	catalog, err := Parse([]byte(testCatalog), "yaml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	row := models.Skin{ImageURL: "https://cdn.example/redline.png"}
+	row.SetConditionPrice(models.ConditionFieldTested, models.MoneyFromFloat(12))
+	row.SyncValueRange()
+
+	catalog.Skins[0].apply(&row)
+	if row.ImageURL != "https://cdn.example/redline.png" {
+		t.Errorf("ImageURL = %q, want the current URL", row.ImageURL)
+	}
+	if got := row.PriceForCondition(models.ConditionFieldTested); got != models.MoneyFromFloat(12) {
+		t.Errorf("Field-Tested price = %v, want 12", got)
+	}
+	if row.Name != "AK-47 | Redline" {
+		t.Errorf("Name = %q, want the catalog name", row.Name)
+	}
+}
+
+func TestSkinApplyOverwritesGivenImageAndPrices(t *testing.T) {
+	entry := Skin{
+		Slug:     "ak-47-redline",
+		ImageURL: "https://cdn.example/new.png",
+		Prices:   map[string]float64{models.ConditionFieldTested: 20},
+	}
+	row := models.Skin{ImageURL: "https://cdn.example/old.png"}
+	row.SetConditionPrice(models.ConditionFieldTested, models.MoneyFromFloat(12))
+
+	entry.apply(&row)
+	if row.ImageURL != entry.ImageURL {
+		t.Errorf("ImageURL = %q, want %q", row.ImageURL, entry.ImageURL)
+	}
+	if got := row.PriceForCondition(models.ConditionFieldTested); got != models.MoneyFromFloat(20) {
+		t.Errorf("Field-Tested price = %v, want 20", got)
+	}
+}
+
+func TestCaseApplyKeepsOmittedImage(t *testing.T) {
+	row := models.Case{ImageURL: "https://cdn.example/case.png"}
+	(&Case{Slug: "test-case", Name: "Test Case", Price: 2.5}).apply(&row)
+	if row.ImageURL != "https://cdn.example/case.png" {
+		t.Errorf("ImageURL = %q, want the current URL", row.ImageURL)
+	}
+}
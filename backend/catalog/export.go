@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export dumps every skin and case in the database, with each case's active drop table,
+// in the catalog format. Drop weights are the stored chances, so re-importing changes nothing.
+func Export(db *gorm.DB) (*Catalog, error) {
+	var skins []models.Skin
+	if err := db.Order("name ASC").Find(&skins).Error; err != nil {
+		return nil, fmt.Errorf("failed to load skins: %w", err)
+	}
+	var cases []models.Case
+	if err := db.Order("name ASC").Find(&cases).Error; err != nil {
+		return nil, fmt.Errorf("failed to load cases: %w", err)
+	}
+
+	catalog := &Catalog{
+		Skins: make([]Skin, 0, len(skins)),
+		Cases: make([]Case, 0, len(cases)),
+	}
+
+	slugs := make(map[uuid.UUID]string, len(skins))
+	for i := range skins {
+		skin := &skins[i]
+		slugs[skin.ID] = skin.Slug
+		catalog.Skins = append(catalog.Skins, exportSkin(skin))
+	}
+
+	for i := range cases {
+		caseItem := &cases[i]
+
+		var contents []models.CaseContent
+		err := db.Joins("JOIN drop_tables ON drop_tables.id = case_contents.drop_table_id").
+			Where("drop_tables.case_id = ? AND drop_tables.retired_at IS NULL", caseItem.ID).
+			Find(&contents).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load contents of case %s: %w", caseItem.Slug, err)
+		}
+
+		exported := exportCase(caseItem)
+		// most common skins first, like the drop odds shown to players
+		sort.Slice(contents, func(a, b int) bool {
+			if contents[a].DropChance != contents[b].DropChance {
+				return contents[a].DropChance > contents[b].DropChance
+			}
+			return slugs[contents[a].SkinID] < slugs[contents[b].SkinID]
+		})
+		for _, content := range contents {
+			exported.Contents = append(exported.Contents, Content{Skin: slugs[content.SkinID], Weight: content.DropChance})
+		}
+		catalog.Cases = append(catalog.Cases, exported)
+	}
+
+	return catalog, nil
+}
+
+// exportSkin converts a skin row to the catalog format
+func exportSkin(skin *models.Skin) Skin {
+	minFloat, maxFloat := skin.MinFloat, skin.MaxFloat
+	exported := Skin{
+		Slug:        skin.Slug,
+		Name:        skin.Name,
+		WeaponType:  skin.WeaponType,
+		Rarity:      skin.Rarity,
+		MinFloat:    &minFloat,
+		MaxFloat:    &maxFloat,
+		ImageURL:    skin.ImageURL,
+		Description: skin.Description,
+	}
+
+	if skin.HasConditionPrices() {
+		exported.Prices = map[string]float64{}
+		for condition, price := range skin.ConditionPrices() {
+			if price > 0 {
+				exported.Prices[condition] = price.Float64()
+			}
+		}
+	} else {
+		exported.MinValue = skin.MinValue.Float64()
+		exported.MaxValue = skin.MaxValue.Float64()
+	}
+
+	if !skin.IsActive {
+		inactive := false
+		exported.Active = &inactive
+	}
+	return exported
+}
+
+// exportCase converts a case row to the catalog format, without its contents
+func exportCase(caseItem *models.Case) Case {
+	statTrakChance := caseItem.StatTrakChance
+	exported := Case{
+		Slug:            caseItem.Slug,
+		Name:            caseItem.Name,
+		Price:           caseItem.Price.Float64(),
+		ImageURL:        caseItem.ImageURL,
+		Description:     caseItem.Description,
+		StatTrakChance:  &statTrakChance,
+		SouvenirPackage: caseItem.IsSouvenirPackage,
+		BulkDiscounts:   caseItem.BulkDiscounts,
+		Contents:        []Content{},
+	}
+
+	if !caseItem.IsActive {
+		inactive := false
+		exported.Active = &inactive
+	}
+	return exported
+}
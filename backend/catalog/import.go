@@ -0,0 +1,318 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// importNotes is recorded on drop table versions published by the importer
+const importNotes = "Imported from catalog"
+
+// chanceTolerance is how close two drop chances must be to count as unchanged
+const chanceTolerance = 1e-9
+
+// errDryRun rolls back the import transaction once the diff has been computed
+var errDryRun = errors.New("dry run")
+
+// FieldChange is one field whose value differs between the database and the catalog
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Change is a skin or case the import creates or updates, or a drop table it publishes
+type Change struct {
+	Kind   string        `json:"kind"`   // skin, case or drop_table
+	Slug   string        `json:"slug"`   // slug of the skin or case
+	Action string        `json:"action"` // create, update or publish
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Diff lists everything an import changed (or would change on a dry run)
+type Diff struct {
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// HasChanges reports whether the import changed anything
+func (d *Diff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// String formats the diff one change per line, with changed fields indented below
+func (d *Diff) String() string {
+	var builder strings.Builder
+	for _, change := range d.Changes {
+		fmt.Fprintf(&builder, "%-7s %-10s %s\n", change.Action, change.Kind, change.Slug)
+		for _, field := range change.Fields {
+			fmt.Fprintf(&builder, "          %s: %q -> %q\n", field.Field, field.Old, field.New)
+		}
+	}
+	fmt.Fprintf(&builder, "%d changes, %d unchanged\n", len(d.Changes), d.Unchanged)
+	return builder.String()
+}
+
+// fieldDiff collects the fields that differ between two versions of a row
+type fieldDiff []FieldChange
+
+// add records the field when its old and new values format differently
+func (f *fieldDiff) add(field string, oldValue, newValue interface{}) {
+	oldText, newText := fmt.Sprint(oldValue), fmt.Sprint(newValue)
+	if oldText != newText {
+		*f = append(*f, FieldChange{Field: field, Old: oldText, New: newText})
+	}
+}
+
+// Import upserts the catalog's skins and cases by slug and publishes a new drop table version
+// for every case whose odds changed. Rows missing from the catalog are left alone, so importing
+// the same file twice changes nothing, and so are image URLs and prices the file leaves out.
+// With dryRun the diff is computed and everything is rolled back.
+func Import(db *gorm.DB, catalog *Catalog, dryRun bool) (*Diff, error) {
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{Changes: []Change{}}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		skinIDs, err := importSkins(tx, catalog, diff)
+		if err != nil {
+			return err
+		}
+		if err := importCases(tx, catalog, skinIDs, diff); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// importSkins upserts the catalog's skins and returns the ID of every skin in the database by slug
+func importSkins(tx *gorm.DB, catalog *Catalog, diff *Diff) (map[string]uuid.UUID, error) {
+	var existing []models.Skin
+	if err := tx.Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to load skins: %w", err)
+	}
+	skinIDs := make(map[string]uuid.UUID, len(existing))
+	bySlug := make(map[string]models.Skin, len(existing))
+	for _, skin := range existing {
+		skinIDs[skin.Slug] = skin.ID
+		bySlug[skin.Slug] = skin
+	}
+
+	for i := range catalog.Skins {
+		entry := &catalog.Skins[i]
+		current, found := bySlug[entry.Slug]
+
+		if !found && (entry.ImageURL == "" || !entry.hasPrices()) {
+			return nil, fmt.Errorf("skin %s is new, so it needs image_url and prices", entry.Slug)
+		}
+
+		desired := current
+		entry.apply(&desired)
+
+		if !found {
+			if err := tx.Create(&desired).Error; err != nil {
+				return nil, fmt.Errorf("failed to create skin %s: %w", entry.Slug, err)
+			}
+			// is_active has a database default, so an inactive skin is written after the insert
+			if !desired.IsActive {
+				if err := tx.Model(&desired).Update("is_active", false).Error; err != nil {
+					return nil, fmt.Errorf("failed to deactivate skin %s: %w", entry.Slug, err)
+				}
+			}
+			skinIDs[entry.Slug] = desired.ID
+			diff.Changes = append(diff.Changes, Change{Kind: "skin", Slug: entry.Slug, Action: "create"})
+			continue
+		}
+
+		var fields fieldDiff
+		fields.add("name", current.Name, desired.Name)
+		fields.add("weapon_type", current.WeaponType, desired.WeaponType)
+		fields.add("rarity", current.Rarity, desired.Rarity)
+		fields.add("min_float", current.MinFloat, desired.MinFloat)
+		fields.add("max_float", current.MaxFloat, desired.MaxFloat)
+		fields.add("image_url", current.ImageURL, desired.ImageURL)
+		fields.add("description", current.Description, desired.Description)
+		fields.add("is_active", current.IsActive, desired.IsActive)
+		for _, condition := range models.Conditions {
+			fields.add("price."+condition, current.ConditionPrices()[condition], desired.ConditionPrices()[condition])
+		}
+		fields.add("min_value", current.MinValue, desired.MinValue)
+		fields.add("max_value", current.MaxValue, desired.MaxValue)
+
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		if err := tx.Save(&desired).Error; err != nil {
+			return nil, fmt.Errorf("failed to update skin %s: %w", entry.Slug, err)
+		}
+		diff.Changes = append(diff.Changes, Change{Kind: "skin", Slug: entry.Slug, Action: "update", Fields: fields})
+	}
+
+	return skinIDs, nil
+}
+
+// importCases upserts the catalog's cases and publishes their drop tables when the odds changed
+func importCases(tx *gorm.DB, catalog *Catalog, skinIDs map[string]uuid.UUID, diff *Diff) error {
+	slugsByID := make(map[uuid.UUID]string, len(skinIDs))
+	for slug, id := range skinIDs {
+		slugsByID[id] = slug
+	}
+
+	for i := range catalog.Cases {
+		entry := &catalog.Cases[i]
+
+		// resolve the contents before touching the case so a missing skin fails the whole import
+		chances := catalog.dropChances(*entry)
+		for slug := range chances {
+			if _, ok := skinIDs[slug]; !ok {
+				return fmt.Errorf("case %s: unknown skin %s", entry.Slug, slug)
+			}
+		}
+
+		var current models.Case
+		err := database.ForUpdate(tx).Where("slug = ?", entry.Slug).First(&current).Error
+		found := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load case %s: %w", entry.Slug, err)
+		}
+
+		if !found && entry.ImageURL == "" {
+			return fmt.Errorf("case %s is new, so it needs an image_url", entry.Slug)
+		}
+
+		desired := current
+		entry.apply(&desired)
+
+		if !found {
+			if err := tx.Create(&desired).Error; err != nil {
+				return fmt.Errorf("failed to create case %s: %w", entry.Slug, err)
+			}
+			// columns with database defaults are written after the insert when they hold zero values
+			if err := tx.Model(&desired).Updates(map[string]interface{}{
+				"is_active":        desired.IsActive,
+				"stat_trak_chance": desired.StatTrakChance,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to create case %s: %w", entry.Slug, err)
+			}
+			diff.Changes = append(diff.Changes, Change{Kind: "case", Slug: entry.Slug, Action: "create"})
+		} else {
+			var fields fieldDiff
+			fields.add("name", current.Name, desired.Name)
+			fields.add("price", current.Price, desired.Price)
+			fields.add("image_url", current.ImageURL, desired.ImageURL)
+			fields.add("description", current.Description, desired.Description)
+			fields.add("is_active", current.IsActive, desired.IsActive)
+			fields.add("stattrak_chance", current.StatTrakChance, desired.StatTrakChance)
+			fields.add("is_souvenir_package", current.IsSouvenirPackage, desired.IsSouvenirPackage)
+			fields.add("bulk_discounts", bulkDiscountsText(current.BulkDiscounts), bulkDiscountsText(desired.BulkDiscounts))
+
+			if len(fields) == 0 {
+				diff.Unchanged++
+			} else {
+				if err := tx.Save(&desired).Error; err != nil {
+					return fmt.Errorf("failed to update case %s: %w", entry.Slug, err)
+				}
+				diff.Changes = append(diff.Changes, Change{Kind: "case", Slug: entry.Slug, Action: "update", Fields: fields})
+			}
+		}
+
+		changed, err := publishIfChanged(tx, desired.ID, chances, skinIDs, slugsByID)
+		if err != nil {
+			return fmt.Errorf("failed to publish drop table of %s: %w", entry.Slug, err)
+		}
+		if changed != nil {
+			diff.Changes = append(diff.Changes, Change{Kind: "drop_table", Slug: entry.Slug, Action: "publish", Fields: changed})
+		}
+	}
+	return nil
+}
+
+// publishIfChanged compares the case's active drop table with the catalog's chances and publishes
+// a new version when they differ. It returns the per-skin chance changes, or nil if nothing changed.
+func publishIfChanged(tx *gorm.DB, caseID uuid.UUID, chances map[string]float64, skinIDs map[string]uuid.UUID, slugsByID map[uuid.UUID]string) (fieldDiff, error) {
+	var active []models.CaseContent
+	err := tx.Joins("JOIN drop_tables ON drop_tables.id = case_contents.drop_table_id").
+		Where("drop_tables.case_id = ? AND drop_tables.retired_at IS NULL", caseID).
+		Find(&active).Error
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]float64, len(active))
+	for _, content := range active {
+		current[slugsByID[content.SkinID]] = content.DropChance
+	}
+
+	slugs := make([]string, 0, len(chances)+len(current))
+	for slug := range chances {
+		slugs = append(slugs, slug)
+	}
+	for slug := range current {
+		if _, ok := chances[slug]; !ok {
+			slugs = append(slugs, slug)
+		}
+	}
+	sort.Strings(slugs)
+
+	var fields fieldDiff
+	for _, slug := range slugs {
+		oldChance, hadSkin := current[slug]
+		newChance, hasSkin := chances[slug]
+		if hadSkin && hasSkin && math.Abs(oldChance-newChance) <= chanceTolerance {
+			continue
+		}
+		fields = append(fields, FieldChange{Field: slug, Old: chanceText(oldChance, hadSkin), New: chanceText(newChance, hasSkin)})
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	contents := make([]models.CaseContent, 0, len(chances))
+	for _, slug := range slugs {
+		if chance, ok := chances[slug]; ok {
+			contents = append(contents, models.CaseContent{SkinID: skinIDs[slug], DropChance: chance})
+		}
+	}
+	if err := models.ValidateCaseContents(contents); err != nil {
+		return nil, err
+	}
+	if _, err := database.PublishDropTable(tx, caseID, contents, importNotes, nil); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// chanceText formats a drop chance for the diff, or "-" when the skin is not in the drop table
+func chanceText(chance float64, present bool) string {
+	if !present {
+		return "-"
+	}
+	return fmt.Sprintf("%.6f", chance)
+}
+
+// bulkDiscountsText formats bulk discount tiers so an empty list and no list compare equal
+func bulkDiscountsText(tiers []models.BulkDiscount) string {
+	parts := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		parts = append(parts, fmt.Sprintf("%d+: %g%%", tier.MinQuantity, tier.PercentOff))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+)
+
+// command is a subcommand of the backend binary
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(cfg *Config, args []string) error
+}
+
+// commands lists every subcommand in the order they are shown in the usage text
+var commands = []command{
+	{"serve", "serve", "run the HTTP API (default)", runServeCommand},
+	{"migrate", "migrate up | down [-steps N] | status | create <name>", "manage schema migrations", runMigrateCommand},
+	{"seed", "seed", "import the sample catalog into an empty database", runSeedCommand},
+	{"catalog", "catalog import [-dry-run] <file> | export <file>", "import or export skins, cases and drop tables as YAML/JSON", runCatalogCommand},
+	{"sync-images", "sync-images [-file catalog.json]", "backfill case and skin image URLs and list unmatched names", runSyncImagesCommand},
+	{"prices", "prices snapshot [-recompute] | recompute [-window 168h]", "record market prices and reprice skins from them", runPricesCommand},
+	{"reconcile", "reconcile [-repair]", "check stored balances against the ledger", runReconcileCommand},
+	{"user", "user promote [-role admin] <email> | grant-casebucks [-reason text] <email> <amount>", "manage user accounts", runUserCommand},
+}
+
+// findCommand looks up a subcommand by name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// needsConfig reports whether a command invocation needs the environment configuration.
+// Creating migration files only writes files, so it works without DB_PASSWORD or JWT_SECRET.
+func needsConfig(name string, args []string) bool {
+	return !(name == "migrate" && len(args) > 0 && args[0] == "create")
+}
+
+// printUsage writes the list of subcommands to stderr
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: backend <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(os.Stderr, "  %-12s   %s\n", "", cmd.usage)
+	}
+}
+
+// connectDatabase opens the PostgreSQL connection and makes sure the schema is current
+func connectDatabase(cfg *Config) error {
+	err := database.ConnectDB(
+		cfg.DBHost,
+		cfg.DBPort,
+		cfg.DBUser,
+		cfg.DBPassword,
+		cfg.DBName,
+	)
+	if err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+
+	// schema changes only happen through the migrate command (or MIGRATE_ON_START)
+	if err := ensureSchema(cfg.MigrateOnStart); err != nil {
+		return fmt.Errorf("schema check failed: %w", err)
+	}
+	return nil
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/TyronOdame/CS-OPN/backend/ratelimit"
 	"github.com/joho/godotenv"
 )
 
@@ -17,6 +21,83 @@ type Config struct {
 	ServerPort  string
 	JWTSecret   string
 	frontendURL string
+
+	// Apply pending migrations at boot instead of refusing to start
+	MigrateOnStart bool
+
+	// Seed the sample catalog and sync its images when the server starts
+	RunSeedOnStart    bool
+	SyncImagesOnStart bool
+
+	// Where image sync looks up item images: "http" (ImageCatalogURL, cached in ImageCatalogCacheDir) or "file"
+	ImageCatalogSource   string
+	ImageCatalogURL      string
+	ImageCatalogFile     string
+	ImageCatalogCacheDir string
+	ImageCatalogTimeout  time.Duration
+
+	// Directory the image proxy stores fetched and resized images in
+	ImageStoreDir string
+
+	// Market price provider ("mock" or "skinport"), its cache and circuit breaker
+	PriceProvider        string
+	PriceAPIURL          string
+	PriceCurrency        string
+	PriceTimeout         time.Duration
+	PriceCacheTTL        time.Duration
+	PriceBreakerFailures int
+	PriceBreakerCooldown time.Duration
+
+	// Price history snapshots (0 disables them) and repricing skins from the recent history
+	PriceSnapshotInterval time.Duration
+	PriceRecompute        bool
+	PriceRecomputeWindow  time.Duration
+
+	// Price-check assistant: language model client ("stub" or "openai") and daily per-user quotas (0 disables them)
+	AIProvider          string
+	AIAPIURL            string
+	AIAPIKey            string
+	AIModel             string
+	AITimeout           time.Duration
+	AIChatDailyMessages int
+	AIChatDailyTokens   int
+
+	// Proxies whose X-Forwarded-For is trusted for client IPs (IPs or CIDRs; none by default)
+	TrustedProxies []string
+
+	// Token bucket limits per route group
+	RateLimitAPI      ratelimit.Limit
+	RateLimitAuth     ratelimit.Limit
+	RateLimitRegister ratelimit.Limit
+	RateLimitGameplay ratelimit.Limit
+	RateLimitAI       ratelimit.Limit
+
+	// Email of the verified account promoted to admin on startup when no admin exists yet
+	BootstrapAdminEmail string
+
+	// Ledger reconciliation job
+	ReconcileInterval time.Duration
+	ReconcileRepair   bool
+
+	// Lifetimes of access tokens (JWT) and session refresh tokens
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Outgoing email (verification and password reset links point at AppURL)
+	AppURL       string
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// How long a deleted account can be restored before it is purged
+	AccountDeletionGrace time.Duration
+
+	// Most cases one batch open or bulk buy request may handle
+	MaxBatchOpen int
 }
 
 // LoadConfig function retrieves configuration from environment variables
@@ -35,7 +116,155 @@ func LoadConfig() (*Config, error) {
 		ServerPort:  getEnv("PORT", "8080"),
 		JWTSecret:   os.Getenv("JWT_SECRET"),
 		frontendURL: os.Getenv("FRONTEND_URL"),
+
+		MigrateOnStart:      strings.EqualFold(getEnv("MIGRATE_ON_START", "false"), "true"),
+		RunSeedOnStart:      strings.EqualFold(getEnv("RUN_SEED_ON_START", "true"), "true"),
+		SyncImagesOnStart:   strings.EqualFold(getEnv("SYNC_IMAGES_ON_START", "true"), "true"),
+		BootstrapAdminEmail: os.Getenv("BOOTSTRAP_ADMIN_EMAIL"),
+
+		ImageCatalogSource:   getEnv("IMAGE_CATALOG_SOURCE", "http"),
+		ImageCatalogURL:      getEnv("IMAGE_CATALOG_URL", "https://api.steamapis.com/image/items/730"),
+		ImageCatalogFile:     os.Getenv("IMAGE_CATALOG_FILE"),
+		ImageCatalogCacheDir: getEnv("IMAGE_CATALOG_CACHE_DIR", "cache/image-catalog"),
+		ImageStoreDir:        getEnv("IMAGE_STORE_DIR", "data/images"),
+
+		PriceProvider: getEnv("PRICE_PROVIDER", "mock"),
+		PriceAPIURL:   getEnv("PRICE_API_URL", "https://api.skinport.com"),
+		PriceCurrency: getEnv("PRICE_CURRENCY", "USD"),
+
+		PriceRecompute: strings.EqualFold(getEnv("PRICE_RECOMPUTE", "false"), "true"),
+
+		AIProvider: getEnv("AI_PROVIDER", "stub"),
+		AIAPIURL:   getEnv("AI_API_URL", "https://api.openai.com/v1"),
+		AIAPIKey:   os.Getenv("AI_API_KEY"),
+		AIModel:    getEnv("AI_MODEL", "gpt-4o-mini"),
+
+		ReconcileRepair:     strings.EqualFold(getEnv("RECONCILE_REPAIR", "false"), "true"),
+
+		AppURL:       getEnv("APP_URL", "http://localhost:3000"),
+		MailDriver:   getEnv("MAIL_DRIVER", "log"),
+		MailFrom:     getEnv("MAIL_FROM", "CS:OPN <no-reply@localhost>"),
+		MailDir:      getEnv("MAIL_DIR", "mail"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+	}
+
+	reconcileInterval, err := time.ParseDuration(getEnv("RECONCILE_INTERVAL", "24h"))
+	if err != nil || reconcileInterval <= 0 {
+		return nil, fmt.Errorf("RECONCILE_INTERVAL must be a positive duration (e.g. 24h)")
+	}
+	config.ReconcileInterval = reconcileInterval
+
+	imageCatalogTimeout, err := time.ParseDuration(getEnv("IMAGE_CATALOG_TIMEOUT", "12s"))
+	if err != nil || imageCatalogTimeout <= 0 {
+		return nil, fmt.Errorf("IMAGE_CATALOG_TIMEOUT must be a positive duration (e.g. 12s)")
+	}
+	config.ImageCatalogTimeout = imageCatalogTimeout
+
+	priceTimeout, err := time.ParseDuration(getEnv("PRICE_TIMEOUT", "10s"))
+	if err != nil || priceTimeout <= 0 {
+		return nil, fmt.Errorf("PRICE_TIMEOUT must be a positive duration (e.g. 10s)")
+	}
+	config.PriceTimeout = priceTimeout
+
+	priceCacheTTL, err := time.ParseDuration(getEnv("PRICE_CACHE_TTL", "10m"))
+	if err != nil || priceCacheTTL < 0 {
+		return nil, fmt.Errorf("PRICE_CACHE_TTL must be a duration (e.g. 10m, 0 disables the cache)")
+	}
+	config.PriceCacheTTL = priceCacheTTL
+
+	priceBreakerFailures, err := strconv.Atoi(getEnv("PRICE_BREAKER_FAILURES", "5"))
+	if err != nil || priceBreakerFailures < 0 {
+		return nil, fmt.Errorf("PRICE_BREAKER_FAILURES must be a number (0 disables the breaker)")
+	}
+	config.PriceBreakerFailures = priceBreakerFailures
+
+	priceBreakerCooldown, err := time.ParseDuration(getEnv("PRICE_BREAKER_COOLDOWN", "1m"))
+	if err != nil || priceBreakerCooldown <= 0 {
+		return nil, fmt.Errorf("PRICE_BREAKER_COOLDOWN must be a positive duration (e.g. 1m)")
+	}
+	config.PriceBreakerCooldown = priceBreakerCooldown
+
+	priceSnapshotInterval, err := time.ParseDuration(getEnv("PRICE_SNAPSHOT_INTERVAL", "6h"))
+	if err != nil || priceSnapshotInterval < 0 {
+		return nil, fmt.Errorf("PRICE_SNAPSHOT_INTERVAL must be a duration (e.g. 6h, 0 disables snapshots)")
+	}
+	config.PriceSnapshotInterval = priceSnapshotInterval
+
+	priceRecomputeWindow, err := time.ParseDuration(getEnv("PRICE_RECOMPUTE_WINDOW", "168h"))
+	if err != nil || priceRecomputeWindow <= 0 {
+		return nil, fmt.Errorf("PRICE_RECOMPUTE_WINDOW must be a positive duration (e.g. 168h)")
+	}
+	config.PriceRecomputeWindow = priceRecomputeWindow
+
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			config.TrustedProxies = append(config.TrustedProxies, proxy)
+		}
+	}
+
+	rateLimits := []struct {
+		env      string
+		fallback string
+		limit    *ratelimit.Limit
+	}{
+		{"RATE_LIMIT_API", "300/1m", &config.RateLimitAPI},
+		{"RATE_LIMIT_AUTH", "10/1m", &config.RateLimitAuth},
+		{"RATE_LIMIT_REGISTER", "5/1h:3", &config.RateLimitRegister},
+		{"RATE_LIMIT_GAMEPLAY", "60/1m:20", &config.RateLimitGameplay},
+		{"RATE_LIMIT_AI", "10/1m", &config.RateLimitAI},
+	}
+	for _, rateLimit := range rateLimits {
+		limit, err := ratelimit.ParseLimit(getEnv(rateLimit.env, rateLimit.fallback))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rateLimit.env, err)
+		}
+		*rateLimit.limit = limit
+	}
+
+	aiTimeout, err := time.ParseDuration(getEnv("AI_TIMEOUT", "30s"))
+	if err != nil || aiTimeout <= 0 {
+		return nil, fmt.Errorf("AI_TIMEOUT must be a positive duration (e.g. 30s)")
+	}
+	config.AITimeout = aiTimeout
+
+	aiChatDailyMessages, err := strconv.Atoi(getEnv("AI_CHAT_DAILY_MESSAGES", "50"))
+	if err != nil || aiChatDailyMessages < 0 {
+		return nil, fmt.Errorf("AI_CHAT_DAILY_MESSAGES must be a number (0 disables the limit)")
+	}
+	config.AIChatDailyMessages = aiChatDailyMessages
+
+	aiChatDailyTokens, err := strconv.Atoi(getEnv("AI_CHAT_DAILY_TOKENS", "50000"))
+	if err != nil || aiChatDailyTokens < 0 {
+		return nil, fmt.Errorf("AI_CHAT_DAILY_TOKENS must be a number (0 disables the limit)")
+	}
+	config.AIChatDailyTokens = aiChatDailyTokens
+
+	accessTokenTTL, err := time.ParseDuration(getEnv("ACCESS_TOKEN_TTL", "15m"))
+	if err != nil || accessTokenTTL <= 0 {
+		return nil, fmt.Errorf("ACCESS_TOKEN_TTL must be a positive duration (e.g. 15m)")
+	}
+	config.AccessTokenTTL = accessTokenTTL
+
+	refreshTokenTTL, err := time.ParseDuration(getEnv("REFRESH_TOKEN_TTL", "720h"))
+	if err != nil || refreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("REFRESH_TOKEN_TTL must be a positive duration (e.g. 720h)")
+	}
+	config.RefreshTokenTTL = refreshTokenTTL
+
+	accountDeletionGrace, err := time.ParseDuration(getEnv("ACCOUNT_DELETION_GRACE", "720h"))
+	if err != nil || accountDeletionGrace <= 0 {
+		return nil, fmt.Errorf("ACCOUNT_DELETION_GRACE must be a positive duration (e.g. 720h)")
+	}
+	config.AccountDeletionGrace = accountDeletionGrace
+
+	maxBatchOpen, err := strconv.Atoi(getEnv("MAX_BATCH_OPEN", "50"))
+	if err != nil || maxBatchOpen <= 0 {
+		return nil, fmt.Errorf("MAX_BATCH_OPEN must be a positive number")
 	}
+	config.MaxBatchOpen = maxBatchOpen
 
 	// Check to see if any required variables are missing
 	if config.DBPassword == "" {
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"gorm.io/gorm"
+)
+
+// PurgeDeletedAccounts finalizes deletions whose grace period has ended. Unsold inventory,
+// unopened cases and login state are removed and the original email and username are forgotten.
+// The anonymized user row and its transactions and ledger entries are kept so the books still balance.
+func PurgeDeletedAccounts() (int, error) {
+	var deletions []models.AccountDeletion
+	if err := DB.
+		Where("restored_at IS NULL AND purged_at IS NULL AND purge_after <= ?", time.Now()).
+		Find(&deletions).Error; err != nil {
+		return 0, fmt.Errorf("failed to load expired account deletions: %w", err)
+	}
+
+	purged := 0
+	for _, deletion := range deletions {
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			// re-check under lock in case the account was restored in the meantime
+			var current models.AccountDeletion
+			if err := ForUpdate(tx).First(&current, "id = ?", deletion.ID).Error; err != nil {
+				return err
+			}
+			if current.RestoredAt != nil || current.PurgedAt != nil {
+				return nil
+			}
+
+			if err := tx.Where("user_id = ? AND is_sold = ?", current.UserID, false).Delete(&models.Inventory{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ? AND is_opened = ?", current.UserID, false).Delete(&models.UserCase{}).Error; err != nil {
+				return err
+			}
+			for _, model := range []interface{}{&models.Session{}, &models.UserToken{}, &models.IdempotencyKey{}, &models.ChatThread{}} {
+				if err := tx.Where("user_id = ?", current.UserID).Delete(model).Error; err != nil {
+					return err
+				}
+			}
+
+			purged++
+			return tx.Model(&current).Updates(map[string]interface{}{
+				"purged_at":         time.Now(),
+				"original_email":    "",
+				"original_username": "",
+			}).Error
+		})
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge account %s: %w", deletion.UserID, err)
+		}
+	}
+	return purged, nil
+}
+
+// StartAccountPurgeJob runs PurgeDeletedAccounts in the background on the given interval
+func StartAccountPurgeJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := PurgeDeletedAccounts()
+			if err != nil {
+				log.Printf("❌ Account purge failed: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("🧹 Purged %d deleted account(s)", purged)
+			}
+		}
+	}()
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"gorm.io/gorm"
+)
+
+// BootstrapAdmin promotes the account with the given email to admin once its email is verified,
+// but only while no admin exists yet so the config cannot be used to take over later.
+func BootstrapAdmin(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+
+	var adminCount int64
+	if err := DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&adminCount).Error; err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if adminCount > 0 {
+		return nil
+	}
+
+	var user models.User
+	if err := DB.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("⚠️  Bootstrap admin %s has not registered yet, skipping promotion", email)
+			return nil
+		}
+		return fmt.Errorf("failed to find bootstrap admin: %w", err)
+	}
+
+	// anyone can register the address first, so only an owner who proved it gets promoted
+	if user.EmailVerifiedAt == nil {
+		log.Printf("⚠️  Bootstrap admin %s has not verified their email yet, skipping promotion", email)
+		return nil
+	}
+
+	if err := DB.Model(&user).Updates(map[string]interface{}{
+		"role":          models.RoleAdmin,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error; err != nil {
+		return fmt.Errorf("failed to promote bootstrap admin: %w", err)
+	}
+
+	log.Printf("👑 Promoted %s to admin", user.Email)
+	return nil
+}
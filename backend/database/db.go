@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/TyronOdame/CS-OPN/backend/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -33,32 +32,3 @@ func ConnectDB(host, port, user, password, dbname string)  error {
 	log.Println("Database connection successful")
 	return nil
 }
-
-
-
-// Auto-migrate the models to create/update database tables
-func AutoMigrate() error {
-	log.Println("Running database migrations...")
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.Skin{},
-		&models.Case{},
-		&models.CaseSkin{},
-
-		// Junction/ relationship models 
-
-		&models.CaseContent{},   // this links the cases and skins
-		&models.UserCase{},      // this tracks bought cases users can open later
-		&models.Inventory{},     // this links the users and the skins they own
-		&models.Transaction{},   // this records user transactions
-
-	)
-
-	if err != nil {
-		return fmt.Errorf("AutoMigrate failed: %w", err)
-	}
-
-	log.Println("Database migrations completed successfully")
-	return nil
-}
-	
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EnsureDropTables wraps case contents that are not yet part of a drop table version
+// (legacy rows and freshly seeded ones) into a new published version for their case.
+// Openings recorded before versioning are pointed at that first version.
+func EnsureDropTables() error {
+	var caseIDs []uuid.UUID
+	if err := DB.Model(&models.CaseContent{}).
+		Where("drop_table_id IS NULL").
+		Distinct().
+		Pluck("case_id", &caseIDs).Error; err != nil {
+		return fmt.Errorf("failed to find unversioned case contents: %w", err)
+	}
+
+	for _, caseID := range caseIDs {
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			var latestVersion int
+			if err := tx.Model(&models.DropTable{}).
+				Where("case_id = ?", caseID).
+				Select("COALESCE(MAX(version), 0)").
+				Scan(&latestVersion).Error; err != nil {
+				return err
+			}
+
+			// the unversioned rows replace whatever version was active
+			if err := tx.Model(&models.DropTable{}).
+				Where("case_id = ? AND retired_at IS NULL", caseID).
+				Update("retired_at", gorm.Expr("NOW()")).Error; err != nil {
+				return err
+			}
+
+			dropTable := models.DropTable{
+				CaseID:  caseID,
+				Version: latestVersion + 1,
+				Notes:   "Created from existing case contents",
+			}
+			if err := tx.Create(&dropTable).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&models.CaseContent{}).
+				Where("case_id = ? AND drop_table_id IS NULL", caseID).
+				Update("drop_table_id", dropTable.ID).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(&models.CaseOpening{}).
+				Where("case_id = ? AND drop_table_id IS NULL", caseID).
+				Update("drop_table_id", dropTable.ID).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to version drop table for case %s: %w", caseID, err)
+		}
+	}
+
+	if len(caseIDs) > 0 {
+		log.Printf("📦 Published drop table versions for %d cases", len(caseIDs))
+	}
+	return nil
+}
+
+// PublishDropTable retires the case's active drop table and publishes the contents as the next version.
+// The caller should hold the case's row lock (ForUpdate) inside tx so two publishes cannot race.
+func PublishDropTable(tx *gorm.DB, caseID uuid.UUID, contents []models.CaseContent, notes string, createdBy *uuid.UUID) (*models.DropTable, error) {
+	var latestVersion int
+	if err := tx.Model(&models.DropTable{}).
+		Where("case_id = ?", caseID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latestVersion).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&models.DropTable{}).
+		Where("case_id = ? AND retired_at IS NULL", caseID).
+		Update("retired_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+
+	dropTable := models.DropTable{
+		CaseID:    caseID,
+		Version:   latestVersion + 1,
+		Notes:     notes,
+		CreatedBy: createdBy,
+	}
+	if err := tx.Create(&dropTable).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range contents {
+		contents[i].CaseID = caseID
+		contents[i].DropTableID = &dropTable.ID
+	}
+	if err := tx.Create(&contents).Error; err != nil {
+		return nil, err
+	}
+	return &dropTable, nil
+}
+
+// CheckDropTables reports active cases whose active drop table is missing, empty or whose chances
+// don't sum to 1. It only logs them: such cases are refused when someone tries to buy or open them,
+// and serve again as soon as an admin publishes valid contents.
+func CheckDropTables() error {
+	var cases []models.Case
+	if err := DB.Where("is_active = ?", true).Find(&cases).Error; err != nil {
+		return fmt.Errorf("failed to load active cases: %w", err)
+	}
+
+	broken := 0
+	for _, caseItem := range cases {
+		var contents []models.CaseContent
+		err := DB.Joins("JOIN drop_tables ON drop_tables.id = case_contents.drop_table_id").
+			Where("drop_tables.case_id = ? AND drop_tables.retired_at IS NULL", caseItem.ID).
+			Find(&contents).Error
+		if err != nil {
+			return fmt.Errorf("failed to load contents of case %s: %w", caseItem.Name, err)
+		}
+
+		if problem := models.ValidateCaseContents(contents); problem != nil {
+			log.Printf("⚠️  Case %s cannot be opened until its drop table is fixed: %v", caseItem.Name, problem)
+			broken++
+		}
+	}
+
+	if broken > 0 {
+		log.Printf("⚠️  %d cases failed the drop table integrity check", broken)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ForUpdate adds a SELECT ... FOR UPDATE row lock to the query.
+// Balance-mutating handlers lock the user row first, then the item rows, so concurrent requests queue instead of double spending.
+func ForUpdate(tx *gorm.DB) *gorm.DB {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS inventories;
+DROP TABLE IF EXISTS user_cases;
+DROP TABLE IF EXISTS case_contents;
+DROP TABLE IF EXISTS case_skins;
+DROP TABLE IF EXISTS cases;
+DROP TABLE IF EXISTS skins;
+DROP TABLE IF EXISTS users;
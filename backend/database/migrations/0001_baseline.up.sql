@@ -0,0 +1,114 @@
+-- Schema as originally created by GORM AutoMigrate.
+-- Every statement is guarded so databases created by AutoMigrate can adopt the migration history.
+
+CREATE TABLE IF NOT EXISTS users (
+    id uuid PRIMARY KEY,
+    email text NOT NULL,
+    username text NOT NULL,
+    password text NOT NULL,
+    casebucks numeric DEFAULT 0,
+    last_daily_reward_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS skins (
+    id uuid PRIMARY KEY,
+    name text NOT NULL,
+    weapon_type text NOT NULL,
+    rarity text NOT NULL,
+    "float" numeric DEFAULT 0.5,
+    image_url text NOT NULL,
+    min_value numeric NOT NULL,
+    max_value numeric NOT NULL,
+    description text,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS cases (
+    id uuid PRIMARY KEY,
+    name text NOT NULL,
+    price numeric NOT NULL,
+    image_url text NOT NULL,
+    description text,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS case_skins (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    case_id uuid NOT NULL,
+    skin_id uuid NOT NULL,
+    drop_rate numeric NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_case_skins_case FOREIGN KEY (case_id) REFERENCES cases (id) ON DELETE CASCADE,
+    CONSTRAINT fk_case_skins_skin FOREIGN KEY (skin_id) REFERENCES skins (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS case_contents (
+    id uuid PRIMARY KEY,
+    case_id uuid NOT NULL,
+    skin_id uuid NOT NULL,
+    drop_chance numeric NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_case_contents_case FOREIGN KEY (case_id) REFERENCES cases (id) ON DELETE CASCADE,
+    CONSTRAINT fk_case_contents_skin FOREIGN KEY (skin_id) REFERENCES skins (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_case_contents_case_id ON case_contents (case_id);
+CREATE INDEX IF NOT EXISTS idx_case_contents_skin_id ON case_contents (skin_id);
+
+CREATE TABLE IF NOT EXISTS user_cases (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    case_id uuid NOT NULL,
+    is_opened boolean NOT NULL DEFAULT false,
+    opened_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_user_cases_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_cases_case FOREIGN KEY (case_id) REFERENCES cases (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_cases_user_id ON user_cases (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_cases_case_id ON user_cases (case_id);
+
+CREATE TABLE IF NOT EXISTS inventories (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    skin_id uuid NOT NULL,
+    "float" numeric NOT NULL,
+    acquired_from text NOT NULL,
+    value numeric NOT NULL,
+    is_sold boolean NOT NULL DEFAULT false,
+    sold_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_inventories_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_inventories_skin FOREIGN KEY (skin_id) REFERENCES skins (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_inventories_user_id ON inventories (user_id);
+CREATE INDEX IF NOT EXISTS idx_inventories_skin_id ON inventories (skin_id);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    type varchar(50) NOT NULL,
+    amount numeric NOT NULL,
+    balance_before numeric NOT NULL,
+    balance_after numeric NOT NULL,
+    description text,
+    reference_id uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_transactions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions (user_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_reference_id ON transactions (reference_id);
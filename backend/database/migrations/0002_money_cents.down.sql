@@ -0,0 +1,8 @@
+ALTER TABLE users ALTER COLUMN casebucks TYPE numeric USING casebucks / 100.0;
+ALTER TABLE cases ALTER COLUMN price TYPE numeric USING price / 100.0;
+ALTER TABLE skins ALTER COLUMN min_value TYPE numeric USING min_value / 100.0;
+ALTER TABLE skins ALTER COLUMN max_value TYPE numeric USING max_value / 100.0;
+ALTER TABLE inventories ALTER COLUMN value TYPE numeric USING value / 100.0;
+ALTER TABLE transactions ALTER COLUMN amount TYPE numeric USING amount / 100.0;
+ALTER TABLE transactions ALTER COLUMN balance_before TYPE numeric USING balance_before / 100.0;
+ALTER TABLE transactions ALTER COLUMN balance_after TYPE numeric USING balance_after / 100.0;
@@ -0,0 +1,27 @@
+-- Store Case Bucks as integer cents. Columns that are already bigint are left alone.
+DO $$
+DECLARE
+    money_column record;
+BEGIN
+    FOR money_column IN
+        SELECT table_name, column_name
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND data_type IN ('double precision', 'real', 'numeric')
+          AND (table_name, column_name) IN (
+              ('users', 'casebucks'),
+              ('cases', 'price'),
+              ('skins', 'min_value'),
+              ('skins', 'max_value'),
+              ('inventories', 'value'),
+              ('transactions', 'amount'),
+              ('transactions', 'balance_before'),
+              ('transactions', 'balance_after')
+          )
+    LOOP
+        EXECUTE format(
+            'ALTER TABLE %I ALTER COLUMN %I TYPE bigint USING ROUND(%I::numeric * 100)::bigint',
+            money_column.table_name, money_column.column_name, money_column.column_name
+        );
+    END LOOP;
+END $$;
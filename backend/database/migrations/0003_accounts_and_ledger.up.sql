@@ -0,0 +1,115 @@
+-- Roles, sessions, email tokens, two-factor auth, login lockout, account deletion,
+-- idempotency keys and the double-entry ledger.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS permissions jsonb;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version bigint NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret varchar(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_used_step bigint NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_count bigint NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until timestamptz;
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    refresh_token_hash varchar(64) NOT NULL,
+    previous_refresh_token_hash varchar(64),
+    user_agent text,
+    ip_address varchar(64),
+    expires_at timestamptz NOT NULL,
+    last_used_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    revoked_reason varchar(50),
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_refresh_token_hash ON sessions (refresh_token_hash);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_refresh_token_hash ON sessions (previous_refresh_token_hash);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    purpose varchar(30) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    email text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    used_at timestamptz,
+    created_at timestamptz,
+    CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_tokens_token_hash ON user_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz,
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_code_hash ON recovery_codes (code_hash);
+
+CREATE TABLE IF NOT EXISTS account_deletions (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    original_email text,
+    original_username text,
+    requested_at timestamptz NOT NULL,
+    purge_after timestamptz NOT NULL,
+    restored_at timestamptz,
+    purged_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_user_id ON account_deletions (user_id);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_original_email ON account_deletions (original_email);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_original_username ON account_deletions (original_username);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_purge_after ON account_deletions (purge_after);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    key varchar(255) NOT NULL,
+    request_hash varchar(64) NOT NULL,
+    status_code bigint NOT NULL DEFAULT 0,
+    response_body bytea,
+    completed_at timestamptz,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT fk_idempotency_keys_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_keys_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id uuid PRIMARY KEY,
+    code varchar(100) NOT NULL,
+    name text NOT NULL,
+    kind varchar(20) NOT NULL,
+    user_id uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_ledger_accounts_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_code ON ledger_accounts (code);
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_kind ON ledger_accounts (kind);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_user_id ON ledger_accounts (user_id);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id uuid PRIMARY KEY,
+    transaction_id uuid NOT NULL,
+    account_id uuid NOT NULL,
+    amount bigint NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT fk_ledger_entries_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (id) ON DELETE RESTRICT,
+    CONSTRAINT fk_ledger_entries_account FOREIGN KEY (account_id) REFERENCES ledger_accounts (id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_id ON ledger_entries (account_id);
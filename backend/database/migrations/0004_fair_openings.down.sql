@@ -0,0 +1,10 @@
+ALTER TABLE cases DROP COLUMN IF EXISTS bulk_discounts;
+
+DROP TABLE IF EXISTS case_openings;
+
+ALTER TABLE case_contents DROP CONSTRAINT IF EXISTS fk_drop_tables_contents;
+DROP INDEX IF EXISTS idx_case_contents_drop_table_id;
+ALTER TABLE case_contents DROP COLUMN IF EXISTS drop_table_id;
+
+DROP TABLE IF EXISTS drop_tables;
+DROP TABLE IF EXISTS fairness_seeds;
@@ -0,0 +1,67 @@
+-- Provably fair seeds, versioned drop tables, recorded openings and bulk discounts.
+
+CREATE TABLE IF NOT EXISTS fairness_seeds (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    server_seed text NOT NULL,
+    server_seed_hash text NOT NULL,
+    client_seed text NOT NULL,
+    nonce bigint NOT NULL DEFAULT 0,
+    revealed_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_fairness_seeds_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fairness_seeds_active_user ON fairness_seeds (user_id) WHERE revealed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_fairness_seeds_server_seed_hash ON fairness_seeds (server_seed_hash);
+
+CREATE TABLE IF NOT EXISTS drop_tables (
+    id uuid PRIMARY KEY,
+    case_id uuid NOT NULL,
+    version bigint NOT NULL,
+    notes text,
+    created_by uuid,
+    published_at timestamptz NOT NULL,
+    retired_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_drop_tables_case FOREIGN KEY (case_id) REFERENCES cases (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_drop_tables_case_version ON drop_tables (case_id, version);
+
+ALTER TABLE case_contents ADD COLUMN IF NOT EXISTS drop_table_id uuid;
+CREATE INDEX IF NOT EXISTS idx_case_contents_drop_table_id ON case_contents (drop_table_id);
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_drop_tables_contents') THEN
+        ALTER TABLE case_contents
+            ADD CONSTRAINT fk_drop_tables_contents FOREIGN KEY (drop_table_id) REFERENCES drop_tables (id);
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS case_openings (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    case_id uuid NOT NULL,
+    drop_table_id uuid,
+    inventory_id uuid NOT NULL,
+    fairness_seed_id uuid NOT NULL,
+    server_seed_hash text NOT NULL,
+    client_seed text NOT NULL,
+    nonce bigint NOT NULL,
+    skin_roll numeric NOT NULL,
+    float_roll numeric NOT NULL,
+    skin_id uuid NOT NULL,
+    "float" numeric NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT fk_case_openings_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_case_openings_fairness_seed FOREIGN KEY (fairness_seed_id) REFERENCES fairness_seeds (id) ON DELETE CASCADE
+);
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS drop_table_id uuid;
+CREATE INDEX IF NOT EXISTS idx_case_openings_user_id ON case_openings (user_id);
+CREATE INDEX IF NOT EXISTS idx_case_openings_case_id ON case_openings (case_id);
+CREATE INDEX IF NOT EXISTS idx_case_openings_drop_table_id ON case_openings (drop_table_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_case_openings_inventory_id ON case_openings (inventory_id);
+CREATE INDEX IF NOT EXISTS idx_case_openings_fairness_seed_id ON case_openings (fairness_seed_id);
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS bulk_discounts jsonb;
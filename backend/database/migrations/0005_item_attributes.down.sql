@@ -0,0 +1,32 @@
+DROP TABLE IF EXISTS pattern_rules;
+
+ALTER TABLE case_openings DROP COLUMN IF EXISTS pattern_tier;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS pattern_seed;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS pattern_roll;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS is_souvenir;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS is_stat_trak;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS stat_trak_chance;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS stat_trak_roll;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS max_float;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS min_float;
+ALTER TABLE case_openings DROP COLUMN IF EXISTS float_method;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS pattern_tier;
+
+ALTER TABLE inventories DROP COLUMN IF EXISTS pattern_tier;
+ALTER TABLE inventories DROP COLUMN IF EXISTS pattern_seed;
+ALTER TABLE inventories DROP COLUMN IF EXISTS is_souvenir;
+ALTER TABLE inventories DROP COLUMN IF EXISTS is_stat_trak;
+
+ALTER TABLE cases DROP COLUMN IF EXISTS is_souvenir_package;
+ALTER TABLE cases DROP COLUMN IF EXISTS stat_trak_chance;
+
+-- skins get back a neutral static float; per-condition prices are lost
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS "float" numeric NOT NULL DEFAULT 0.5;
+ALTER TABLE skins DROP COLUMN IF EXISTS battle_scarred_price;
+ALTER TABLE skins DROP COLUMN IF EXISTS well_worn_price;
+ALTER TABLE skins DROP COLUMN IF EXISTS field_tested_price;
+ALTER TABLE skins DROP COLUMN IF EXISTS minimal_wear_price;
+ALTER TABLE skins DROP COLUMN IF EXISTS factory_new_price;
+ALTER TABLE skins DROP COLUMN IF EXISTS max_float;
+ALTER TABLE skins DROP COLUMN IF EXISTS min_float;
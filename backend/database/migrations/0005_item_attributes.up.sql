@@ -0,0 +1,61 @@
+-- Float caps and per-condition prices, StatTrak/Souvenir variants and pattern seeds.
+
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS min_float numeric NOT NULL DEFAULT 0;
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS max_float numeric NOT NULL DEFAULT 1;
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS factory_new_price bigint NOT NULL DEFAULT 0;
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS minimal_wear_price bigint NOT NULL DEFAULT 0;
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS field_tested_price bigint NOT NULL DEFAULT 0;
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS well_worn_price bigint NOT NULL DEFAULT 0;
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS battle_scarred_price bigint NOT NULL DEFAULT 0;
+
+-- price unpriced skins along their old value range at the midpoint of each wear bucket
+-- (the same curve Skin.DeriveConditionPrices uses for an uncapped 0-1 float range);
+-- the value range then runs from the Battle-Scarred to the Factory New price
+UPDATE skins SET
+    factory_new_price    = min_value + ROUND((max_value - min_value) * (1 - 0.035))::bigint,
+    minimal_wear_price   = min_value + ROUND((max_value - min_value) * (1 - 0.11))::bigint,
+    field_tested_price   = min_value + ROUND((max_value - min_value) * (1 - 0.265))::bigint,
+    well_worn_price      = min_value + ROUND((max_value - min_value) * (1 - 0.415))::bigint,
+    battle_scarred_price = min_value + ROUND((max_value - min_value) * (1 - 0.725))::bigint,
+    max_value            = min_value + ROUND((max_value - min_value) * (1 - 0.035))::bigint,
+    min_value            = min_value + ROUND((max_value - min_value) * (1 - 0.725))::bigint
+WHERE max_value > 0
+  AND factory_new_price = 0 AND minimal_wear_price = 0 AND field_tested_price = 0
+  AND well_worn_price = 0 AND battle_scarred_price = 0;
+
+-- the static float was never used for drops; every opening rolls its own float
+ALTER TABLE skins DROP COLUMN IF EXISTS "float";
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS stat_trak_chance numeric NOT NULL DEFAULT 0.1;
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS is_souvenir_package boolean NOT NULL DEFAULT false;
+
+ALTER TABLE inventories ADD COLUMN IF NOT EXISTS is_stat_trak boolean NOT NULL DEFAULT false;
+ALTER TABLE inventories ADD COLUMN IF NOT EXISTS is_souvenir boolean NOT NULL DEFAULT false;
+ALTER TABLE inventories ADD COLUMN IF NOT EXISTS pattern_seed bigint NOT NULL DEFAULT 0;
+ALTER TABLE inventories ADD COLUMN IF NOT EXISTS pattern_tier varchar(50);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS pattern_tier varchar(50);
+
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS float_method varchar(20) NOT NULL DEFAULT 'uniform';
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS min_float numeric NOT NULL DEFAULT 0;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS max_float numeric NOT NULL DEFAULT 1;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS stat_trak_roll numeric;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS stat_trak_chance numeric NOT NULL DEFAULT 0;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS is_stat_trak boolean NOT NULL DEFAULT false;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS is_souvenir boolean NOT NULL DEFAULT false;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS pattern_roll numeric;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS pattern_seed bigint;
+ALTER TABLE case_openings ADD COLUMN IF NOT EXISTS pattern_tier varchar(50);
+
+CREATE TABLE IF NOT EXISTS pattern_rules (
+    id uuid PRIMARY KEY,
+    skin_id uuid NOT NULL,
+    seed_min bigint NOT NULL,
+    seed_max bigint NOT NULL,
+    tier varchar(50) NOT NULL,
+    multiplier numeric NOT NULL DEFAULT 1,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_pattern_rules_skin FOREIGN KEY (skin_id) REFERENCES skins (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_pattern_rules_skin_id ON pattern_rules (skin_id);
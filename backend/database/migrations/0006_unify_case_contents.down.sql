@@ -0,0 +1,11 @@
+-- Recreates an empty case_skins table; the merged rows stay in case_contents.
+CREATE TABLE IF NOT EXISTS case_skins (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    case_id uuid NOT NULL,
+    skin_id uuid NOT NULL,
+    drop_rate numeric NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_case_skins_case FOREIGN KEY (case_id) REFERENCES cases (id) ON DELETE CASCADE,
+    CONSTRAINT fk_case_skins_skin FOREIGN KEY (skin_id) REFERENCES skins (id) ON DELETE CASCADE
+);
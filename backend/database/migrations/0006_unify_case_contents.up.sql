@@ -0,0 +1,15 @@
+-- Fold the legacy case_skins table (percent drop_rate) into case_contents (fraction drop_chance).
+-- Cases that already have case contents keep them; their case_skins rows were never read by the
+-- opening flow. Merged rows are left unversioned so startup publishes them as a drop table.
+
+DO $$
+BEGIN
+    IF to_regclass('case_skins') IS NOT NULL THEN
+        INSERT INTO case_contents (id, case_id, skin_id, drop_chance, created_at, updated_at)
+        SELECT cs.id, cs.case_id, cs.skin_id, cs.drop_rate / 100.0, cs.created_at, cs.updated_at
+        FROM case_skins cs
+        WHERE NOT EXISTS (SELECT 1 FROM case_contents cc WHERE cc.case_id = cs.case_id);
+
+        DROP TABLE case_skins;
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cases_slug;
+ALTER TABLE cases DROP COLUMN IF EXISTS slug;
+
+DROP INDEX IF EXISTS idx_skins_slug;
+ALTER TABLE skins DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,20 @@
+-- Stable slug keys for skins and cases so catalog files can upsert them.
+-- Existing rows get Slugify(name); the rare collision keeps its id prefix to stay unique.
+
+ALTER TABLE skins ADD COLUMN IF NOT EXISTS slug varchar(100);
+UPDATE skins SET slug = trim(both '-' from left(trim(both '-' from regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), 100))
+WHERE slug IS NULL;
+UPDATE skins SET slug = left(id::text, 8) WHERE slug = '';
+UPDATE skins s SET slug = left(s.slug, 91) || '-' || left(s.id::text, 8)
+WHERE EXISTS (SELECT 1 FROM skins o WHERE o.slug = s.slug AND o.id < s.id);
+ALTER TABLE skins ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skins_slug ON skins (slug);
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS slug varchar(100);
+UPDATE cases SET slug = trim(both '-' from left(trim(both '-' from regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), 100))
+WHERE slug IS NULL;
+UPDATE cases SET slug = left(id::text, 8) WHERE slug = '';
+UPDATE cases c SET slug = left(c.slug, 91) || '-' || left(c.id::text, 8)
+WHERE EXISTS (SELECT 1 FROM cases o WHERE o.slug = c.slug AND o.id < c.id);
+ALTER TABLE cases ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cases_slug ON cases (slug);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS skin_price_points;
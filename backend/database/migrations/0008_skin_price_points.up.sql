@@ -0,0 +1,14 @@
+-- Market prices of skins over time, recorded by the price snapshot job
+CREATE TABLE IF NOT EXISTS skin_price_points (
+    id uuid PRIMARY KEY,
+    skin_id uuid NOT NULL,
+    condition varchar(20) NOT NULL,
+    market_hash_name varchar(255) NOT NULL,
+    price bigint NOT NULL,
+    quantity bigint NOT NULL DEFAULT 0,
+    currency varchar(3) NOT NULL,
+    provider varchar(50) NOT NULL,
+    recorded_at timestamptz NOT NULL,
+    CONSTRAINT fk_skin_price_points_skin FOREIGN KEY (skin_id) REFERENCES skins (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_skin_price_points_skin_recorded ON skin_price_points (skin_id, recorded_at);
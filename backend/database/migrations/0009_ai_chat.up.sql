@@ -0,0 +1,32 @@
+-- Price-check assistant conversations and per-user daily usage for quotas
+CREATE TABLE IF NOT EXISTS chat_threads (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    title varchar(120) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_chat_threads_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_chat_threads_user_id ON chat_threads (user_id);
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id uuid PRIMARY KEY,
+    thread_id uuid NOT NULL,
+    role varchar(20) NOT NULL,
+    content text NOT NULL,
+    tool_calls jsonb,
+    tool_call_id varchar(100),
+    created_at timestamptz,
+    CONSTRAINT fk_chat_messages_thread FOREIGN KEY (thread_id) REFERENCES chat_threads (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_thread_created ON chat_messages (thread_id, created_at);
+
+CREATE TABLE IF NOT EXISTS chat_usages (
+    user_id uuid NOT NULL,
+    day date NOT NULL,
+    messages bigint NOT NULL DEFAULT 0,
+    tokens bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, day),
+    CONSTRAINT fk_chat_usages_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS failed_two_factor_count;
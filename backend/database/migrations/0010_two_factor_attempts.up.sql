@@ -0,0 +1,4 @@
+-- Wrong 2FA codes on sensitive actions are counted per user and lock further attempts for a while.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_two_factor_count integer NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_locked_until timestamptz;
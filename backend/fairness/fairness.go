@@ -0,0 +1,70 @@
+package fairness
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// Roll cursors let a single opening derive several independent values
+// from the same server seed, client seed and nonce.
+const (
+	CursorSkin     = 0 // picks the skin from the case's drop table
+	CursorFloat    = 1 // picks the wear float of the dropped skin
+	CursorStatTrak = 2 // decides whether the drop is StatTrak
+	CursorPattern  = 3 // picks the pattern seed of the dropped skin
+)
+
+// Float methods turn the float roll into a wear float. Every opening records the method
+// it used so openings made before a change stay verifiable.
+const (
+	FloatMethodUniform     = "uniform"      // float = float_roll
+	FloatMethodWearBuckets = "wear_buckets" // CS2 wear buckets, scaled into the skin's float cap
+)
+
+// Algorithm describes how rolls are derived so users can verify them offline
+const Algorithm = "HMAC-SHA256(key=server_seed, message=client_seed:nonce:cursor); first 53 bits / 2^53; " +
+	"wear_buckets float: float_roll picks a bucket by weight (FN 0-0.07: 3%, MW 0.07-0.15: 24%, FT 0.15-0.38: 33%, " +
+	"WW 0.38-0.45: 24%, BS 0.45-1: 16%), the rest of the roll is uniform within the bucket, " +
+	"then float = min_float + wear * (max_float - min_float); " +
+	"StatTrak when stattrak_roll (cursor 2) < the case's stattrak_chance (0 for souvenir packages); " +
+	"pattern_seed = floor(pattern_roll (cursor 3) * 1000)"
+
+// GenerateServerSeed returns a new random hex-encoded server seed
+func GenerateServerSeed() (string, error) {
+	return randomHex(32)
+}
+
+// GenerateClientSeed returns a new random hex-encoded client seed
+func GenerateClientSeed() (string, error) {
+	return randomHex(16)
+}
+
+// HashServerSeed returns the SHA-256 commitment shown to users before the seed is revealed
+func HashServerSeed(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Roll derives a deterministic value in [0, 1) from the given inputs
+func Roll(serverSeed, clientSeed string, nonce int64, cursor int) float64 {
+	mac := hmac.New(sha256.New, []byte(serverSeed))
+	fmt.Fprintf(mac, "%s:%d:%d", clientSeed, nonce, cursor)
+	sum := mac.Sum(nil)
+
+	// keep the top 53 bits so every value is exactly representable as a float64
+	bits := binary.BigEndian.Uint64(sum[:8]) >> 11
+	return float64(bits) / float64(uint64(1)<<53)
+}
+
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate seed: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
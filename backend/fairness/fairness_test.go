@@ -0,0 +1,95 @@
+package fairness
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashServerSeed(t *testing.T) {
+	// SHA-256 of "abc" from FIPS 180-2
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := HashServerSeed("abc"); got != want {
+		t.Fatalf("HashServerSeed(abc) = %s, want %s", got, want)
+	}
+}
+
+func TestRollMatchesDocumentedAlgorithm(t *testing.T) {
+	serverSeed, clientSeed := "server-seed", "client-seed"
+
+	mac := hmac.New(sha256.New, []byte(serverSeed))
+	mac.Write([]byte("client-seed:42:1"))
+	bits := binary.BigEndian.Uint64(mac.Sum(nil)[:8]) >> 11
+	want := float64(bits) / (1 << 53)
+
+	if got := Roll(serverSeed, clientSeed, 42, CursorFloat); got != want {
+		t.Fatalf("Roll = %v, want %v", got, want)
+	}
+}
+
+func TestRollIsDeterministicAndInRange(t *testing.T) {
+	for nonce := int64(1); nonce <= 1000; nonce++ {
+		roll := Roll("server", "client", nonce, CursorSkin)
+		if roll < 0 || roll >= 1 {
+			t.Fatalf("Roll(nonce %d) = %v, want a value in [0, 1)", nonce, roll)
+		}
+		if again := Roll("server", "client", nonce, CursorSkin); again != roll {
+			t.Fatalf("Roll(nonce %d) changed between calls: %v then %v", nonce, roll, again)
+		}
+	}
+}
+
+func TestRollInputsAreIndependent(t *testing.T) {
+	base := Roll("server", "client", 1, CursorSkin)
+	others := map[string]float64{
+		"cursor":      Roll("server", "client", 1, CursorFloat),
+		"nonce":       Roll("server", "client", 2, CursorSkin),
+		"client seed": Roll("server", "client2", 1, CursorSkin),
+		"server seed": Roll("server2", "client", 1, CursorSkin),
+	}
+	for input, roll := range others {
+		if roll == base {
+			t.Errorf("changing the %s did not change the roll", input)
+		}
+	}
+}
+
+func TestRollIsRoughlyUniform(t *testing.T) {
+	const rolls, buckets = 20000, 10
+	counts := make([]int, buckets)
+	for nonce := int64(0); nonce < rolls; nonce++ {
+		counts[int(Roll("server", "client", nonce, CursorSkin)*buckets)]++
+	}
+	for i, count := range counts {
+		// expected 2000 per bucket; 10% slack is far outside random variation
+		if count < 1800 || count > 2200 {
+			t.Errorf("bucket %d got %d of %d rolls", i, count, rolls)
+		}
+	}
+}
+
+func TestGenerateSeeds(t *testing.T) {
+	serverSeed, err := GenerateServerSeed()
+	if err != nil {
+		t.Fatalf("GenerateServerSeed: %v", err)
+	}
+	clientSeed, err := GenerateClientSeed()
+	if err != nil {
+		t.Fatalf("GenerateClientSeed: %v", err)
+	}
+
+	for name, seed := range map[string]struct {
+		value string
+		bytes int
+	}{"server": {serverSeed, 32}, "client": {clientSeed, 16}} {
+		decoded, err := hex.DecodeString(seed.value)
+		if err != nil || len(decoded) != seed.bytes {
+			t.Errorf("%s seed %q is not %d hex-encoded bytes", name, seed.value, seed.bytes)
+		}
+	}
+	if other, _ := GenerateServerSeed(); other == serverSeed {
+		t.Error("two server seeds are identical")
+	}
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/twofactor"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ChangePasswordRequest represents the expected payload for changing the password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// DeleteAccountRequest represents the expected payload for deleting the account
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// RestoreAccountRequest represents the expected payload for restoring a deleted account
+type RestoreAccountRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"` // 2FA code, required when the account has 2FA enabled
+}
+
+// errWrongPassword is returned when the confirmation password does not match
+var errWrongPassword = errors.New("wrong password")
+
+// heldByDeletedAccount checks if an email or username still belongs to an account that can be restored
+func heldByDeletedAccount(column, value string) bool {
+	var count int64
+	database.DB.Model(&models.AccountDeletion{}).
+		Where(column+" = ? AND restored_at IS NULL AND purged_at IS NULL", value).
+		Count(&count)
+	return count > 0
+}
+
+// ChangePassword sets a new password after checking the current one.
+// Every session is logged out and the caller gets a fresh session in the response.
+func ChangePassword(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req ChangePasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var user models.User
+		var tokens gin.H
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+				return err
+			}
+			if !user.CheckPassword(req.CurrentPassword) {
+				return errWrongPassword
+			}
+			if err := user.HashPassword(req.NewPassword); err != nil {
+				return err
+			}
+			if err := tx.Model(&user).Update("password", user.Password).Error; err != nil {
+				return err
+			}
+
+			if err := logoutEverywhere(tx, user.ID, models.SessionRevokedPasswordChange); err != nil {
+				return err
+			}
+			user.TokenVersion++
+
+			tokens, err = issueSession(c, tx, &user, cfg)
+			return err
+		})
+		if errors.Is(err, errWrongPassword) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+			return
+		}
+
+		tokens["message"] = "Password changed successfully. Other sessions have been logged out"
+		tokens["user"] = user.ToJSON()
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// DeleteAccount soft-deletes the user's account. The account is anonymized and logged out
+// everywhere; inventory and unopened cases stay frozen until the grace period ends,
+// so restoring the account brings everything back.
+func DeleteAccount(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req DeleteAccountRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Password is required to delete the account",
+			})
+			return
+		}
+
+		var deletion models.AccountDeletion
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			var user models.User
+			if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+				return err
+			}
+			if !user.CheckPassword(req.Password) {
+				return errWrongPassword
+			}
+
+			now := time.Now()
+			deletion = models.AccountDeletion{
+				UserID:           user.ID,
+				OriginalEmail:    user.Email,
+				OriginalUsername: user.Username,
+				RequestedAt:      now,
+				PurgeAfter:       now.Add(cfg.AccountDeletionGrace),
+			}
+			if err := tx.Create(&deletion).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&user).Updates(map[string]interface{}{
+				"email":    models.AnonymizedEmail(user.ID),
+				"username": models.AnonymizedUsername(user.ID),
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&user).Error; err != nil {
+				return err
+			}
+
+			// outstanding verification and reset links must not work on the anonymized account
+			if err := tx.Model(&models.UserToken{}).
+				Where("user_id = ? AND used_at IS NULL", user.ID).
+				Update("used_at", now).Error; err != nil {
+				return err
+			}
+
+			return logoutEverywhere(tx, user.ID, models.SessionRevokedAccountDeleted)
+		})
+		if errors.Is(err, errWrongPassword) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Account deleted. It can be restored with your email and password until purge_after",
+			"deletion": deletion.ToJSON(),
+		})
+	}
+}
+
+// RestoreAccount brings back a deleted account during its grace period and logs the user in
+func RestoreAccount(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RestoreAccountRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request data",
+			})
+			return
+		}
+
+		// same response for unknown emails, expired deletions and wrong passwords
+		invalidCredentials := func() {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid credentials, or the account can no longer be restored",
+			})
+		}
+
+		var deletion models.AccountDeletion
+		err := database.DB.
+			Where("original_email = ? AND restored_at IS NULL AND purged_at IS NULL", req.Email).
+			Order("requested_at DESC").
+			First(&deletion).Error
+		if err == nil && !deletion.IsPending() {
+			err = gorm.ErrRecordNotFound
+		}
+		var user models.User
+		if err == nil {
+			err = database.DB.Unscoped().First(&user, "id = ?", deletion.UserID).Error
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			invalidCredentials()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to restore account",
+			})
+			return
+		}
+
+		// the same lockout and counters as Login, so the endpoint can't be used to guess
+		// a deleted account's password or 2FA code. Failures are recorded outside the
+		// restore transaction, which they would otherwise be rolled back with.
+		if user.IsLocked() {
+			respondAccountLocked(c, *user.LockedUntil)
+			return
+		}
+		if !user.CheckPassword(req.Password) {
+			lockedUntil, err := recordFailedLogin(user.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to restore account",
+				})
+				return
+			}
+			if lockedUntil != nil {
+				respondAccountLocked(c, *lockedUntil)
+				return
+			}
+			invalidCredentials()
+			return
+		}
+
+		err = twofactor.CheckFresh(database.DB.Unscoped(), user.ID, req.Code)
+		var locked *twofactor.LockedError
+		switch {
+		case errors.As(err, &locked):
+			middleware.RespondTwoFactorLocked(c, locked.Until)
+			return
+		case errors.Is(err, twofactor.ErrCodeRequired) || errors.Is(err, twofactor.ErrInvalidCode):
+			invalidCredentials()
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to restore account",
+			})
+			return
+		}
+
+		var tokens gin.H
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			// a concurrent restore may have won since the checks above
+			if err := database.ForUpdate(tx).First(&deletion, "id = ?", deletion.ID).Error; err != nil {
+				return err
+			}
+			if !deletion.IsPending() {
+				return gorm.ErrRecordNotFound
+			}
+			if err := database.ForUpdate(tx.Unscoped()).First(&user, "id = ?", deletion.UserID).Error; err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if err := tx.Unscoped().Model(&user).Updates(map[string]interface{}{
+				"email":              deletion.OriginalEmail,
+				"username":           deletion.OriginalUsername,
+				"deleted_at":         nil,
+				"failed_login_count": 0,
+				"locked_until":       nil,
+			}).Error; err != nil {
+				return err
+			}
+			user.Email = deletion.OriginalEmail
+			user.Username = deletion.OriginalUsername
+			user.DeletedAt = gorm.DeletedAt{}
+
+			if err := tx.Model(&deletion).Update("restored_at", now).Error; err != nil {
+				return err
+			}
+
+			var err error
+			tokens, err = issueSession(c, tx, &user, cfg)
+			return err
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			invalidCredentials()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to restore account",
+			})
+			return
+		}
+
+		tokens["message"] = "Account restored successfully"
+		tokens["user"] = user.ToJSON()
+		c.JSON(http.StatusOK, tokens)
+	}
+}
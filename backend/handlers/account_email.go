@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/mailer"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VerifyEmailRequest represents the expected payload for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordRequest represents the expected payload for requesting a password reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents the expected payload for setting a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// errInvalidUserToken is returned for unknown, expired or already used emailed tokens
+var errInvalidUserToken = errors.New("invalid or expired token")
+
+// issueUserToken replaces any outstanding token of the same purpose with a new one and returns it
+func issueUserToken(tx *gorm.DB, user *models.User, purpose models.TokenPurpose, ttl time.Duration) (string, error) {
+	now := time.Now()
+	if err := tx.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, purpose).
+		Update("used_at", now).Error; err != nil {
+		return "", err
+	}
+
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	userToken := models.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		Email:     user.Email,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := tx.Create(&userToken).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// redeemUserToken locks the token, checks it is still usable and marks it used
+func redeemUserToken(tx *gorm.DB, token string, purpose models.TokenPurpose) (*models.UserToken, error) {
+	var userToken models.UserToken
+	err := database.ForUpdate(tx).
+		Where("token_hash = ? AND purpose = ?", utils.HashToken(token), purpose).
+		First(&userToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errInvalidUserToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !userToken.IsUsable() {
+		return nil, errInvalidUserToken
+	}
+
+	if err := tx.Model(&userToken).Update("used_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return &userToken, nil
+}
+
+// appLink builds a link into the frontend carrying a token
+func appLink(cfg AuthConfig, path, token string) string {
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimRight(cfg.AppURL, "/"), path, url.QueryEscape(token))
+}
+
+// sendMail delivers an email without failing the request; the user can always ask for it again
+func sendMail(cfg AuthConfig, msg mailer.Message) {
+	if cfg.Mailer == nil {
+		return
+	}
+	if err := cfg.Mailer.Send(msg); err != nil {
+		log.Printf("⚠️ Failed to send %q email: %v", msg.Subject, err)
+	}
+}
+
+// sendVerificationEmail emails the user a link to confirm their address
+func sendVerificationEmail(cfg AuthConfig, user *models.User, token string) {
+	sendMail(cfg, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your CS:OPN email",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address to start claiming daily rewards:\n\n%s\n\nThis link expires in %s.\n",
+			user.Username, appLink(cfg, "/verify-email", token), models.EmailVerificationTokenTTL),
+	})
+}
+
+// sendPasswordResetEmail emails the user a link to choose a new password
+func sendPasswordResetEmail(cfg AuthConfig, user *models.User, token string) {
+	sendMail(cfg, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your CS:OPN password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password for your account. If it was you, choose a new one here:\n\n%s\n\nThis link expires in %s. If you didn't ask for this, you can ignore this email.\n",
+			user.Username, appLink(cfg, "/reset-password", token), models.PasswordResetTokenTTL),
+	})
+}
+
+// VerifyEmail confirms the user's email address with the token from the verification email
+func VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "token is required",
+		})
+		return
+	}
+
+	var user models.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		userToken, err := redeemUserToken(tx, req.Token, models.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userToken.UserID).Error; err != nil {
+			return err
+		}
+		// the address changed after this link was sent
+		if !strings.EqualFold(user.Email, userToken.Email) {
+			return errInvalidUserToken
+		}
+		if user.IsEmailVerified() {
+			return nil
+		}
+
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		return tx.Model(&user).Update("email_verified_at", now).Error
+	})
+	if errors.Is(err, errInvalidUserToken) || errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or expired verification link",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+		"user":    user.ToJSON(),
+	})
+}
+
+// ResendVerificationEmail sends a fresh verification link to the user's current address
+func ResendVerificationEmail(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var user models.User
+		if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		if user.IsEmailVerified() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email is already verified"})
+			return
+		}
+
+		token, err := issueUserToken(database.DB, &user, models.TokenPurposeEmailVerification, models.EmailVerificationTokenTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create verification link"})
+			return
+		}
+		sendVerificationEmail(cfg, &user, token)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Verification email sent",
+		})
+	}
+}
+
+// ForgotPassword emails a password reset link. It responds the same way whether or not
+// the address belongs to an account so it can't be used to discover registered emails.
+func ForgotPassword(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ForgotPasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "A valid email is required",
+			})
+			return
+		}
+
+		var user models.User
+		if err := database.DB.Where("email = ?", req.Email).First(&user).Error; err == nil {
+			token, err := issueUserToken(database.DB, &user, models.TokenPurposePasswordReset, models.PasswordResetTokenTTL)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to create password reset link",
+				})
+				return
+			}
+			sendPasswordResetEmail(cfg, &user, token)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "If an account exists for that email, a password reset link has been sent",
+		})
+	}
+}
+
+// ResetPassword sets a new password using a reset token and logs out every session
+func ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		userToken, err := redeemUserToken(tx, req.Token, models.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userToken.UserID).Error; err != nil {
+			return err
+		}
+		if err := user.HashPassword(req.Password); err != nil {
+			return err
+		}
+
+		// a successful reset also lifts any login lockout
+		updates := map[string]interface{}{
+			"password":           user.Password,
+			"failed_login_count": 0,
+			"locked_until":       nil,
+		}
+		// the reset link proves control of the mailbox it was sent to
+		if !user.IsEmailVerified() && strings.EqualFold(user.Email, userToken.Email) {
+			updates["email_verified_at"] = time.Now()
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return logoutEverywhere(tx, user.ID, models.SessionRevokedPasswordReset)
+	})
+	if errors.Is(err, errInvalidUserToken) || errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or expired password reset link",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to reset password",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully. Please log in with your new password",
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// createDeletedUser creates a user with the given password and deletes the account like
+// DeleteAccount does, returning the original email
+func createDeletedUser(t *testing.T, password string) (models.User, string) {
+	t.Helper()
+	user := createTestUser(t, 0)
+	if err := user.HashPassword(password); err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	email := user.Email
+
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password", user.Password).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.AccountDeletion{
+			UserID:           user.ID,
+			OriginalEmail:    user.Email,
+			OriginalUsername: user.Username,
+			RequestedAt:      now,
+			PurgeAfter:       now.Add(time.Hour),
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"email":    models.AnonymizedEmail(user.ID),
+			"username": models.AnonymizedUsername(user.ID),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&user).Error
+	})
+	if err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	return user, email
+}
+
+func restoreAccount(router *gin.Engine, email, password string) int {
+	body, _ := json.Marshal(RestoreAccountRequest{Email: email, Password: password})
+	req := httptest.NewRequest(http.MethodPost, "/auth/restore-account", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestRestoreAccountLocksOutWrongPasswords(t *testing.T) {
+	requireTestDB(t)
+	user, email := createDeletedUser(t, "correct-password")
+	router := gin.New()
+	router.POST("/auth/restore-account", RestoreAccount(AuthConfig{JWTSecret: "test-secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}))
+
+	for i := 1; i < models.LoginLockoutThreshold; i++ {
+		if status := restoreAccount(router, email, "wrong-password"); status != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: status %d, want 401", i, status)
+		}
+	}
+	if status := restoreAccount(router, email, "wrong-password"); status != http.StatusTooManyRequests {
+		t.Fatalf("attempt %d: status %d, want 429", models.LoginLockoutThreshold, status)
+	}
+	// while locked even the right password is refused, and the account stays deleted
+	if status := restoreAccount(router, email, "correct-password"); status != http.StatusTooManyRequests {
+		t.Fatalf("correct password while locked: status %d, want 429", status)
+	}
+
+	var stored models.User
+	if err := database.DB.Unscoped().First(&stored, "id = ?", user.ID).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if !stored.DeletedAt.Valid || stored.FailedLoginCount != models.LoginLockoutThreshold || !stored.IsLocked() {
+		t.Fatalf("user deleted=%v failed_login_count=%d locked=%v, want still deleted and locked",
+			stored.DeletedAt.Valid, stored.FailedLoginCount, stored.IsLocked())
+	}
+}
+
+func TestRestoreAccountResetsFailedLogins(t *testing.T) {
+	requireTestDB(t)
+	user, email := createDeletedUser(t, "correct-password")
+	router := gin.New()
+	router.POST("/auth/restore-account", RestoreAccount(AuthConfig{JWTSecret: "test-secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}))
+
+	if status := restoreAccount(router, email, "wrong-password"); status != http.StatusUnauthorized {
+		t.Fatalf("wrong password: status %d, want 401", status)
+	}
+	if status := restoreAccount(router, email, "correct-password"); status != http.StatusOK {
+		t.Fatalf("correct password: status %d, want 200", status)
+	}
+
+	var stored models.User
+	if err := database.DB.First(&stored, "id = ?", user.ID).Error; err != nil {
+		t.Fatalf("restored user not found: %v", err)
+	}
+	if stored.Email != email || stored.FailedLoginCount != 0 {
+		t.Fatalf("restored user email=%s failed_login_count=%d, want %s and 0", stored.Email, stored.FailedLoginCount, email)
+	}
+}
@@ -0,0 +1,546 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateCaseRequest represents the payload for creating a case
+type CreateCaseRequest struct {
+	Slug              string                `json:"slug"` // derived from the name when empty
+	Name              string                `json:"name" binding:"required"`
+	Price             models.Money          `json:"price" binding:"required,gt=0"`
+	ImageURL          string                `json:"image_url" binding:"required"`
+	Description       string                `json:"description"`
+	IsActive          *bool                 `json:"is_active"`
+	BulkDiscounts     []models.BulkDiscount `json:"bulk_discounts"`
+	StatTrakChance    *float64              `json:"stattrak_chance"` // defaults to models.DefaultStatTrakChance
+	IsSouvenirPackage bool                  `json:"is_souvenir_package"`
+}
+
+// UpdateCaseRequest represents the payload for updating a case (only provided fields change)
+type UpdateCaseRequest struct {
+	Slug              *string                `json:"slug"`
+	Name              *string                `json:"name" binding:"omitempty,min=1"`
+	Price             *models.Money          `json:"price" binding:"omitempty,gt=0"`
+	ImageURL          *string                `json:"image_url" binding:"omitempty,min=1"`
+	Description       *string                `json:"description"`
+	IsActive          *bool                  `json:"is_active"`
+	BulkDiscounts     *[]models.BulkDiscount `json:"bulk_discounts"` // replaces every tier; send [] to remove them
+	StatTrakChance    *float64               `json:"stattrak_chance"`
+	IsSouvenirPackage *bool                  `json:"is_souvenir_package"`
+}
+
+// CreateSkinRequest represents the payload for creating a skin
+type CreateSkinRequest struct {
+	Slug        string                  `json:"slug"` // derived from the name when empty
+	Name        string                  `json:"name" binding:"required"`
+	WeaponType  string                  `json:"weapon_type" binding:"required"`
+	Rarity      string                  `json:"rarity" binding:"required"`
+	ImageURL    string                  `json:"image_url" binding:"required"`
+	MinValue    models.Money            `json:"min_value" binding:"gte=0"` // derives condition prices when prices is empty
+	MaxValue    models.Money            `json:"max_value" binding:"gte=0"`
+	MinFloat    *float64                `json:"min_float"`
+	MaxFloat    *float64                `json:"max_float"`
+	Prices      map[string]models.Money `json:"prices"` // keyed by wear condition
+	Description string                  `json:"description"`
+	IsActive    *bool                   `json:"is_active"`
+}
+
+// UpdateSkinRequest represents the payload for updating a skin (only provided fields change)
+type UpdateSkinRequest struct {
+	Slug        *string                 `json:"slug"`
+	Name        *string                 `json:"name" binding:"omitempty,min=1"`
+	WeaponType  *string                 `json:"weapon_type" binding:"omitempty,min=1"`
+	Rarity      *string                 `json:"rarity"`
+	ImageURL    *string                 `json:"image_url" binding:"omitempty,min=1"`
+	MinValue    *models.Money           `json:"min_value" binding:"omitempty,gte=0"` // re-derives condition prices when prices is not sent
+	MaxValue    *models.Money           `json:"max_value" binding:"omitempty,gte=0"`
+	MinFloat    *float64                `json:"min_float"`
+	MaxFloat    *float64                `json:"max_float"`
+	Prices      map[string]models.Money `json:"prices"` // only the given conditions change
+	Description *string                 `json:"description"`
+	IsActive    *bool                   `json:"is_active"`
+}
+
+// AdminListCases returns every case, including inactive ones
+func AdminListCases(c *gin.Context) {
+	var cases []models.Case
+	if err := database.DB.Order("created_at ASC").Find(&cases).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cases"})
+		return
+	}
+
+	response := make([]map[string]interface{}, 0, len(cases))
+	for _, cs := range cases {
+		response = append(response, cs.ToJSON())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cases": response,
+		"count": len(response),
+	})
+}
+
+// AdminCreateCase creates a new case. It can only be opened once a drop table is published.
+func AdminCreateCase(c *gin.Context) {
+	var req CreateCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	if err := models.ValidateBulkDiscounts(req.BulkDiscounts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	statTrakChance := models.DefaultStatTrakChance
+	if req.StatTrakChance != nil {
+		statTrakChance = *req.StatTrakChance
+	}
+	if err := models.ValidateStatTrakChance(statTrakChance); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug, ok := checkSlug(c, &models.Case{}, req.Slug, req.Name, uuid.Nil)
+	if !ok {
+		return
+	}
+
+	caseItem := models.Case{
+		Slug:              slug,
+		Name:              req.Name,
+		Price:             req.Price,
+		ImageURL:          req.ImageURL,
+		Description:       req.Description,
+		IsActive:          true,
+		BulkDiscounts:     req.BulkDiscounts,
+		StatTrakChance:    statTrakChance,
+		IsSouvenirPackage: req.IsSouvenirPackage,
+	}
+	if err := database.DB.Create(&caseItem).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create case"})
+		return
+	}
+
+	// the column defaults to true, so an inactive case is switched off after insert
+	if req.IsActive != nil && !*req.IsActive {
+		if err := database.DB.Model(&caseItem).Update("is_active", false).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create case"})
+			return
+		}
+	}
+
+	// likewise a zero StatTrak chance is replaced by the column default on insert
+	if statTrakChance == 0 {
+		if err := database.DB.Model(&caseItem).Update("stat_trak_chance", 0).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create case"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Case created successfully",
+		"case":    caseItem.ToJSON(),
+	})
+}
+
+// AdminUpdateCase updates a case's details
+func AdminUpdateCase(c *gin.Context) {
+	caseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid case ID"})
+		return
+	}
+
+	var req UpdateCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	if req.BulkDiscounts != nil {
+		if err := models.ValidateBulkDiscounts(*req.BulkDiscounts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.StatTrakChance != nil {
+		if err := models.ValidateStatTrakChance(*req.StatTrakChance); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var caseItem models.Case
+	if err := database.DB.First(&caseItem, "id = ?", caseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+
+	if req.Slug != nil {
+		if _, ok := checkSlug(c, &models.Case{}, *req.Slug, "", caseID); !ok {
+			return
+		}
+	}
+
+	// a case can only go live with a drop table that passes the integrity check
+	if req.IsActive != nil && *req.IsActive {
+		_, contents, err := loadActiveDropTable(database.DB, caseID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch case contents"})
+			return
+		}
+		if err := models.ValidateCaseContents(contents); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Case contents are misconfigured", "details": err.Error()})
+			return
+		}
+	}
+
+	updates := map[string]interface{}{}
+	if req.Slug != nil {
+		updates["slug"] = *req.Slug
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Price != nil {
+		updates["price"] = *req.Price
+	}
+	if req.ImageURL != nil {
+		updates["image_url"] = *req.ImageURL
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.StatTrakChance != nil {
+		updates["stat_trak_chance"] = *req.StatTrakChance
+	}
+	if req.IsSouvenirPackage != nil {
+		updates["is_souvenir_package"] = *req.IsSouvenirPackage
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(&caseItem).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update case"})
+			return
+		}
+	}
+
+	// serialized columns go through the struct so the JSON serializer runs
+	if req.BulkDiscounts != nil {
+		caseItem.BulkDiscounts = *req.BulkDiscounts
+		if err := database.DB.Model(&caseItem).Select("bulk_discounts").Updates(&caseItem).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update case"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Case updated successfully",
+		"case":    caseItem.ToJSON(),
+	})
+}
+
+// AdminDeactivateCase stops a case from being sold or opened.
+// Purchased cases and past openings are kept.
+func AdminDeactivateCase(c *gin.Context) {
+	caseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid case ID"})
+		return
+	}
+
+	var caseItem models.Case
+	if err := database.DB.First(&caseItem, "id = ?", caseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+
+	if err := database.DB.Model(&caseItem).Update("is_active", false).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate case"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Case deactivated successfully",
+		"case":    caseItem.ToJSON(),
+	})
+}
+
+// AdminListSkins returns every skin, including inactive ones
+func AdminListSkins(c *gin.Context) {
+	query := database.DB.Order("name ASC")
+	if rarity := c.Query("rarity"); rarity != "" {
+		query = query.Where("rarity = ?", rarity)
+	}
+
+	var skins []models.Skin
+	if err := query.Find(&skins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skins"})
+		return
+	}
+
+	response := make([]map[string]interface{}, 0, len(skins))
+	for _, skin := range skins {
+		response = append(response, skin.ToJSON())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skins": response,
+		"count": len(response),
+	})
+}
+
+// AdminCreateSkin creates a new skin
+func AdminCreateSkin(c *gin.Context) {
+	var req CreateSkinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	if !models.IsValidRarity(req.Rarity) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown rarity", "rarities": models.Rarities})
+		return
+	}
+	if req.MinValue > req.MaxValue {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_value cannot be greater than max_value"})
+		return
+	}
+
+	slug, ok := checkSlug(c, &models.Skin{}, req.Slug, req.Name, uuid.Nil)
+	if !ok {
+		return
+	}
+
+	skin := models.Skin{
+		Slug:        slug,
+		Name:        req.Name,
+		WeaponType:  req.WeaponType,
+		Rarity:      req.Rarity,
+		ImageURL:    req.ImageURL,
+		MinValue:    req.MinValue,
+		MaxValue:    req.MaxValue,
+		MinFloat:    0,
+		MaxFloat:    1,
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if req.MinFloat != nil {
+		skin.MinFloat = *req.MinFloat
+	}
+	if req.MaxFloat != nil {
+		skin.MaxFloat = *req.MaxFloat
+	}
+	if err := models.ValidateFloatRange(skin.MinFloat, skin.MaxFloat); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applySkinPrices(&skin, req.Prices); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "conditions": models.Conditions})
+		return
+	}
+	if err := database.DB.Create(&skin).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create skin"})
+		return
+	}
+
+	// the column defaults to true, so an inactive skin is switched off after insert
+	if req.IsActive != nil && !*req.IsActive {
+		if err := database.DB.Model(&skin).Update("is_active", false).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create skin"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Skin created successfully",
+		"skin":    skin.ToJSON(),
+	})
+}
+
+// checkSlug validates a requested slug (or derives one from the name when empty) and checks that no
+// other row of the model's table uses it. It writes the error response and returns false on failure.
+func checkSlug(c *gin.Context, model interface{}, slug, name string, exceptID uuid.UUID) (string, bool) {
+	if slug == "" {
+		slug = models.Slugify(name)
+	}
+	if err := models.ValidateSlug(slug); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+
+	var count int64
+	if err := database.DB.Model(model).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check slug"})
+		return "", false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use", "slug": slug})
+		return "", false
+	}
+	return slug, true
+}
+
+// applySkinPrices sets the given condition prices on the skin
+func applySkinPrices(skin *models.Skin, prices map[string]models.Money) error {
+	for condition, price := range prices {
+		if !models.IsValidCondition(condition) {
+			return fmt.Errorf("unknown wear condition %q", condition)
+		}
+		if price < 0 {
+			return fmt.Errorf("price for %s cannot be negative", condition)
+		}
+		skin.SetConditionPrice(condition, price)
+	}
+	return nil
+}
+
+// AdminUpdateSkin updates a skin's details
+func AdminUpdateSkin(c *gin.Context) {
+	skinID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skin ID"})
+		return
+	}
+
+	var req UpdateSkinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	var skin models.Skin
+	if err := database.DB.First(&skin, "id = ?", skinID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skin not found"})
+		return
+	}
+
+	if req.Rarity != nil && !models.IsValidRarity(*req.Rarity) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown rarity", "rarities": models.Rarities})
+		return
+	}
+	if req.Slug != nil {
+		if _, ok := checkSlug(c, &models.Skin{}, *req.Slug, "", skinID); !ok {
+			return
+		}
+	}
+
+	// validate the value range as it will be after the update
+	minValue, maxValue := skin.MinValue, skin.MaxValue
+	if req.MinValue != nil {
+		minValue = *req.MinValue
+	}
+	if req.MaxValue != nil {
+		maxValue = *req.MaxValue
+	}
+	if minValue > maxValue {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_value cannot be greater than max_value"})
+		return
+	}
+
+	// validate the float cap as it will be after the update
+	if req.MinFloat != nil {
+		skin.MinFloat = *req.MinFloat
+	}
+	if req.MaxFloat != nil {
+		skin.MaxFloat = *req.MaxFloat
+	}
+	if err := models.ValidateFloatRange(skin.MinFloat, skin.MaxFloat); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// a new value range without explicit prices re-derives the condition prices
+	if len(req.Prices) == 0 && (req.MinValue != nil || req.MaxValue != nil) {
+		skin.DeriveConditionPrices(minValue, maxValue)
+	}
+	if err := applySkinPrices(&skin, req.Prices); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "conditions": models.Conditions})
+		return
+	}
+	skin.SyncValueRange()
+
+	updates := map[string]interface{}{}
+	if req.Slug != nil {
+		updates["slug"] = *req.Slug
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.WeaponType != nil {
+		updates["weapon_type"] = *req.WeaponType
+	}
+	if req.Rarity != nil {
+		updates["rarity"] = *req.Rarity
+	}
+	if req.ImageURL != nil {
+		updates["image_url"] = *req.ImageURL
+	}
+	if req.MinValue != nil || req.MaxValue != nil || req.Prices != nil || req.MinFloat != nil || req.MaxFloat != nil {
+		updates["min_float"] = skin.MinFloat
+		updates["max_float"] = skin.MaxFloat
+		updates["factory_new_price"] = skin.FactoryNewPrice
+		updates["minimal_wear_price"] = skin.MinimalWearPrice
+		updates["field_tested_price"] = skin.FieldTestedPrice
+		updates["well_worn_price"] = skin.WellWornPrice
+		updates["battle_scarred_price"] = skin.BattleScarredPrice
+		updates["min_value"] = skin.MinValue
+		updates["max_value"] = skin.MaxValue
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(&skin).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update skin"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Skin updated successfully",
+		"skin":    skin.ToJSON(),
+	})
+}
+
+// AdminDeactivateSkin stops a skin from being added to new drop tables.
+// Published drop tables and owned items are not changed.
+func AdminDeactivateSkin(c *gin.Context) {
+	skinID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skin ID"})
+		return
+	}
+
+	var skin models.Skin
+	if err := database.DB.First(&skin, "id = ?", skinID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skin not found"})
+		return
+	}
+
+	if err := database.DB.Model(&skin).Update("is_active", false).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate skin"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Skin deactivated successfully",
+		"skin":    skin.ToJSON(),
+	})
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DropTableEntryRequest is one skin and its chance in a drop table
+type DropTableEntryRequest struct {
+	SkinID     uuid.UUID `json:"skin_id" binding:"required"`
+	DropChance float64   `json:"drop_chance" binding:"required,gt=0,lte=1"`
+}
+
+// UpdateDropTableRequest represents the payload for publishing a new drop table version
+type UpdateDropTableRequest struct {
+	Contents []DropTableEntryRequest `json:"contents" binding:"required,min=1,dive"`
+	Notes    string                  `json:"notes"`
+}
+
+// AdminGetCaseContents returns a case's drop table (the active one or ?version=N) and its version history
+func AdminGetCaseContents(c *gin.Context) {
+	caseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid case ID"})
+		return
+	}
+
+	var caseItem models.Case
+	if err := database.DB.First(&caseItem, "id = ?", caseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+
+	var versions []models.DropTable
+	if err := database.DB.Where("case_id = ?", caseID).Order("version DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch drop tables"})
+		return
+	}
+
+	versionsJSON := make([]map[string]interface{}, 0, len(versions))
+	var selected *models.DropTable
+	requestedVersion := c.Query("version")
+	for i := range versions {
+		versionsJSON = append(versionsJSON, versions[i].ToJSON())
+		if requestedVersion == "" && versions[i].IsActive() {
+			selected = &versions[i]
+		}
+		if requestedVersion == strconv.Itoa(versions[i].Version) {
+			selected = &versions[i]
+		}
+	}
+
+	response := gin.H{
+		"case":     caseItem.ToJSON(),
+		"versions": versionsJSON,
+	}
+
+	if selected == nil {
+		if requestedVersion != "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Drop table version not found"})
+			return
+		}
+		response["drop_table"] = nil
+		response["contents"] = []map[string]interface{}{}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	contents, err := loadDropTableContents(database.DB, selected.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch case contents"})
+		return
+	}
+
+	contentsJSON := make([]map[string]interface{}, 0, len(contents))
+	var total float64
+	for _, content := range contents {
+		contentsJSON = append(contentsJSON, content.ToJSONWithSkin())
+		total += content.DropChance
+	}
+
+	response["drop_table"] = selected.ToJSON()
+	response["contents"] = contentsJSON
+	response["total_drop_chance"] = total
+	c.JSON(http.StatusOK, response)
+}
+
+// AdminUpdateCaseContents publishes a new drop table version for a case.
+// The chances must sum to 1.0 within models.DropChanceTolerance; the previous version is retired.
+func AdminUpdateCaseContents(c *gin.Context) {
+	adminID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	caseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid case ID"})
+		return
+	}
+
+	var req UpdateDropTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// validate the odds and that every skin appears once
+	chances := make([]float64, 0, len(req.Contents))
+	skinIDs := make([]uuid.UUID, 0, len(req.Contents))
+	seen := make(map[uuid.UUID]bool, len(req.Contents))
+	for _, entry := range req.Contents {
+		if seen[entry.SkinID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Each skin can only appear once", "skin_id": entry.SkinID})
+			return
+		}
+		seen[entry.SkinID] = true
+		chances = append(chances, entry.DropChance)
+		skinIDs = append(skinIDs, entry.SkinID)
+	}
+	if err := models.ValidateDropChances(chances); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid drop table", "details": err.Error()})
+		return
+	}
+
+	var activeSkinCount int64
+	if err := database.DB.Model(&models.Skin{}).Where("id IN ? AND is_active = ?", skinIDs, true).Count(&activeSkinCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skins"})
+		return
+	}
+	if int(activeSkinCount) != len(skinIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Every skin must exist and be active"})
+		return
+	}
+
+	var dropTable *models.DropTable
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// lock the case so two admins cannot publish the same version number
+		var caseItem models.Case
+		if err := database.ForUpdate(tx).First(&caseItem, "id = ?", caseID).Error; err != nil {
+			return err
+		}
+
+		contents := make([]models.CaseContent, 0, len(req.Contents))
+		for _, entry := range req.Contents {
+			contents = append(contents, models.CaseContent{
+				SkinID:     entry.SkinID,
+				DropChance: entry.DropChance,
+			})
+		}
+		dropTable, err = database.PublishDropTable(tx, caseID, contents, req.Notes, &adminID)
+		return err
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish drop table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Drop table published successfully",
+		"drop_table": dropTable.ToJSON(),
+	})
+}
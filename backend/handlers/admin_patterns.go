@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreatePatternRuleRequest represents the payload for naming a range of pattern seeds
+type CreatePatternRuleRequest struct {
+	SeedMin    *int    `json:"seed_min" binding:"required"`
+	SeedMax    *int    `json:"seed_max" binding:"required"`
+	Tier       string  `json:"tier" binding:"required,max=50"`
+	Multiplier float64 `json:"multiplier" binding:"required,gt=0"`
+}
+
+// AdminListPatternRules returns a skin's pattern rules ordered by seed
+func AdminListPatternRules(c *gin.Context) {
+	skinID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skin ID"})
+		return
+	}
+
+	var rules []models.PatternRule
+	if err := database.DB.Where("skin_id = ?", skinID).Order("seed_min ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pattern rules"})
+		return
+	}
+
+	response := make([]map[string]interface{}, 0, len(rules))
+	for i := range rules {
+		response = append(response, rules[i].ToJSON())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pattern_rules": response,
+		"count":         len(response),
+	})
+}
+
+// AdminCreatePatternRule adds a pattern rule to a skin. Rules of a skin may not overlap,
+// so every pattern seed maps to at most one tier.
+func AdminCreatePatternRule(c *gin.Context) {
+	skinID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skin ID"})
+		return
+	}
+
+	var req CreatePatternRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	rule := models.PatternRule{
+		SkinID:     skinID,
+		SeedMin:    *req.SeedMin,
+		SeedMax:    *req.SeedMax,
+		Tier:       req.Tier,
+		Multiplier: req.Multiplier,
+	}
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var conflict *models.PatternRule
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// lock the skin so two admins can't add overlapping rules at the same time
+		var skin models.Skin
+		if err := database.ForUpdate(tx).First(&skin, "id = ?", skinID).Error; err != nil {
+			return err
+		}
+
+		var existing []models.PatternRule
+		if err := tx.Where("skin_id = ?", skinID).Find(&existing).Error; err != nil {
+			return err
+		}
+		for i := range existing {
+			if rule.Overlaps(existing[i]) {
+				conflict = &existing[i]
+				return nil
+			}
+		}
+
+		return tx.Create(&rule).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skin not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pattern rule"})
+		return
+	}
+	if conflict != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "Pattern seeds overlap an existing rule",
+			"conflict": conflict.ToJSON(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Pattern rule created successfully",
+		"pattern_rule": rule.ToJSON(),
+	})
+}
+
+// AdminDeletePatternRule removes a pattern rule. Items already dropped keep their tier.
+func AdminDeletePatternRule(c *gin.Context) {
+	skinID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skin ID"})
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pattern rule ID"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND skin_id = ?", ruleID, skinID).Delete(&models.PatternRule{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pattern rule"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pattern rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pattern rule deleted successfully"})
+}
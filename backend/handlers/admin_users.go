@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UpdateUserRoleRequest represents the payload for changing a user's role and extra permissions
+type UpdateUserRoleRequest struct {
+	Role        models.Role          `json:"role" binding:"required"`
+	Permissions *[]models.Permission `json:"permissions"`
+}
+
+// AdminListUsers returns users, optionally filtered by role or a search term
+func AdminListUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	query := database.DB.Order("created_at DESC").Limit(limit)
+	if role := c.Query("role"); role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if search := c.Query("search"); search != "" {
+		query = query.Where("email ILIKE ? OR username ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var users []models.User
+	if err := query.Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	response := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		response = append(response, user.ToJSON())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": response,
+		"count": len(response),
+	})
+}
+
+// AdminUpdateUserRole changes a user's role and, optionally, their individually granted permissions.
+// The change applies to the user's next token.
+func AdminUpdateUserRole(c *gin.Context) {
+	adminID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	if !req.Role.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role", "roles": models.Roles})
+		return
+	}
+	if req.Permissions != nil {
+		for _, permission := range *req.Permissions {
+			if !models.IsValidPermission(permission) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown permission", "permission": permission})
+				return
+			}
+		}
+	}
+
+	// admins cannot demote themselves and lock everyone out of role management
+	if userID == adminID && req.Role != models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot remove your own admin role"})
+		return
+	}
+
+	// bumping the token version drops access tokens carrying the old claims; refreshing issues new ones
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		user.Role = req.Role
+		if req.Permissions != nil {
+			user.Permissions = *req.Permissions
+		}
+		user.TokenVersion++
+		return tx.Model(&user).Select("role", "permissions", "token_version").Updates(&user).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role updated successfully",
+		"user":    user.ToJSON(),
+	})
+}
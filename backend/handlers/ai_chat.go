@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/assistant"
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/llm"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxChatHistory is how many earlier messages of a thread are sent to the model
+const maxChatHistory = 40
+
+// maxChatTitleLength caps thread titles, which are taken from the first message
+const maxChatTitleLength = 60
+
+// ChatConfig configures the price-check assistant endpoints
+type ChatConfig struct {
+	Assistant *assistant.Assistant
+
+	// per-user limits for each UTC day; 0 means unlimited
+	DailyMessages int
+	DailyTokens   int
+}
+
+// ChatRequest represents the payload of a chat message; without a thread_id a new thread is started
+type ChatRequest struct {
+	ThreadID *uuid.UUID `json:"thread_id"`
+	Message  string     `json:"message" binding:"required,max=2000"`
+}
+
+// Chat sends the user's message to the price-check assistant and stores the exchange in the thread
+func Chat(cfg ChatConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req ChatRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		req.Message = strings.TrimSpace(req.Message)
+		if req.Message == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "message cannot be empty"})
+			return
+		}
+
+		// continue an existing thread or start a new one titled after the first message
+		thread := models.ChatThread{UserID: userID, Title: chatTitle(req.Message)}
+		history := []llm.Message{}
+		if req.ThreadID != nil {
+			err := database.DB.Where("id = ? AND user_id = ?", *req.ThreadID, userID).First(&thread).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load thread"})
+				return
+			}
+			if history, err = chatHistory(thread.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load thread"})
+				return
+			}
+		}
+
+		// take the message out of today's quota before calling the model, so concurrent
+		// requests can't all pass the check and overspend it
+		usage, reserved, err := reserveChatMessage(cfg, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load assistant usage"})
+			return
+		}
+		if !reserved {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": "Daily assistant limit reached, try again tomorrow",
+				"usage": chatUsageJSON(cfg, usage),
+			})
+			return
+		}
+
+		userMessage := llm.Message{Role: llm.RoleUser, Content: req.Message}
+		added, tokens, replyErr := cfg.Assistant.Reply(userID, append(history, userMessage))
+
+		// a failed reply gives the message back, but the tokens it used up until then still count
+		refund := 0
+		if replyErr != nil {
+			refund = 1
+		}
+		if err := recordChatTokens(usage, tokens.Total(), refund); err != nil {
+			log.Printf("⚠️  Failed to record assistant usage: %v", err)
+		}
+		usage.Messages -= refund
+		usage.Tokens += tokens.Total()
+
+		if replyErr != nil {
+			log.Printf("⚠️  Assistant reply failed: %v", replyErr)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "The assistant is unavailable, try again later"})
+			return
+		}
+
+		stored := make([]models.ChatMessage, 0, len(added)+1)
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if thread.ID == uuid.Nil {
+				if err := tx.Create(&thread).Error; err != nil {
+					return err
+				}
+			} else if err := tx.Model(&thread).Update("updated_at", time.Now()).Error; err != nil {
+				return err
+			}
+
+			// spread the timestamps so the thread reads back in order
+			createdAt := time.Now()
+			for i, message := range append([]llm.Message{userMessage}, added...) {
+				stored = append(stored, chatMessageFromLLM(thread.ID, message, createdAt.Add(time.Duration(i)*time.Microsecond)))
+			}
+			return tx.Create(&stored).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save conversation"})
+			return
+		}
+
+		reply := added[len(added)-1]
+		c.JSON(http.StatusOK, gin.H{
+			"thread":   thread,
+			"reply":    reply.Content,
+			"messages": stored,
+			"model":    cfg.Assistant.Model(),
+			"usage":    chatUsageJSON(cfg, usage),
+		})
+	}
+}
+
+// GetChatThreads lists the user's assistant threads, most recently active first
+func GetChatThreads(cfg ChatConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var threads []models.ChatThread
+		if err := database.DB.Where("user_id = ?", userID).Order("updated_at DESC").Limit(50).Find(&threads).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch threads"})
+			return
+		}
+		usage, err := todaysChatUsage(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load assistant usage"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"threads": threads,
+			"usage":   chatUsageJSON(cfg, usage),
+		})
+	}
+}
+
+// GetChatThread returns a thread with all of its messages, including tool calls and results
+func GetChatThread(c *gin.Context) {
+	thread, ok := loadOwnChatThread(c)
+	if !ok {
+		return
+	}
+
+	var messages []models.ChatMessage
+	if err := database.DB.Where("thread_id = ?", thread.ID).Order("created_at ASC").Find(&messages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"thread":   thread,
+		"messages": messages,
+	})
+}
+
+// DeleteChatThread deletes a thread and its messages
+func DeleteChatThread(c *gin.Context) {
+	thread, ok := loadOwnChatThread(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(thread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete thread"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Thread deleted"})
+}
+
+// loadOwnChatThread loads the :id thread of the current user, writing the error response if it fails
+func loadOwnChatThread(c *gin.Context) (*models.ChatThread, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return nil, false
+	}
+
+	var thread models.ChatThread
+	err = database.DB.Where("id = ? AND user_id = ?", threadID, userID).First(&thread).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load thread"})
+		return nil, false
+	}
+	return &thread, true
+}
+
+// chatHistory returns the latest messages of a thread for the model. The window starts at a
+// user message so it never opens with tool results whose calls were cut off.
+func chatHistory(threadID uuid.UUID) ([]llm.Message, error) {
+	var recent []models.ChatMessage
+	err := database.DB.Where("thread_id = ?", threadID).
+		Order("created_at DESC").
+		Limit(maxChatHistory).
+		Find(&recent).Error
+	if err != nil {
+		return nil, err
+	}
+
+	history := []llm.Message{}
+	for i := len(recent) - 1; i >= 0; i-- {
+		message := recent[i]
+		if len(history) == 0 && message.Role != models.ChatRoleUser {
+			continue
+		}
+		converted := llm.Message{Role: message.Role, Content: message.Content, ToolCallID: message.ToolCallID}
+		for _, call := range message.ToolCalls {
+			converted.ToolCalls = append(converted.ToolCalls, llm.ToolCall{ID: call.ID, Name: call.Name, Arguments: call.Arguments})
+		}
+		history = append(history, converted)
+	}
+	return history, nil
+}
+
+// chatMessageFromLLM converts a conversation message for storage
+func chatMessageFromLLM(threadID uuid.UUID, message llm.Message, createdAt time.Time) models.ChatMessage {
+	stored := models.ChatMessage{
+		ThreadID:   threadID,
+		Role:       message.Role,
+		Content:    message.Content,
+		ToolCallID: message.ToolCallID,
+		CreatedAt:  createdAt,
+	}
+	for _, call := range message.ToolCalls {
+		stored.ToolCalls = append(stored.ToolCalls, models.ChatToolCall{ID: call.ID, Name: call.Name, Arguments: call.Arguments})
+	}
+	return stored
+}
+
+// todaysChatUsage returns the user's usage for the current UTC day (zero if there is none yet)
+func todaysChatUsage(userID uuid.UUID) (models.ChatUsage, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	usage := models.ChatUsage{UserID: userID, Day: today}
+	err := database.DB.Where("user_id = ? AND day = ?", userID, today).First(&usage).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return usage, err
+	}
+	usage.Day = today
+	return usage, nil
+}
+
+// reserveChatMessage counts a message against the user's quota for the current UTC day in a
+// single upsert that only goes through while the day is under both limits. It returns the day's
+// usage (including the reserved message) and false, with the current usage, when a limit is reached.
+func reserveChatMessage(cfg ChatConfig, userID uuid.UUID) (models.ChatUsage, bool, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	usage := models.ChatUsage{UserID: userID, Day: today, Messages: 1}
+
+	underLimits := clause.Where{}
+	if cfg.DailyMessages > 0 {
+		underLimits.Exprs = append(underLimits.Exprs, gorm.Expr("chat_usages.messages < ?", cfg.DailyMessages))
+	}
+	if cfg.DailyTokens > 0 {
+		underLimits.Exprs = append(underLimits.Exprs, gorm.Expr("chat_usages.tokens < ?", cfg.DailyTokens))
+	}
+
+	result := database.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"messages":   gorm.Expr("chat_usages.messages + 1"),
+			"updated_at": time.Now(),
+		}),
+		Where: underLimits,
+	}, clause.Returning{}).Create(&usage)
+	if result.Error != nil {
+		return usage, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		current, err := todaysChatUsage(userID)
+		return current, false, err
+	}
+	usage.Day = today
+	return usage, true, nil
+}
+
+// recordChatTokens adds the tokens a reply consumed to the day's usage and gives back
+// refund reserved messages
+func recordChatTokens(usage models.ChatUsage, tokens, refund int) error {
+	if tokens == 0 && refund == 0 {
+		return nil
+	}
+	return database.DB.Model(&models.ChatUsage{}).
+		Where("user_id = ? AND day = ?", usage.UserID, usage.Day).
+		Updates(map[string]interface{}{
+			"messages":   gorm.Expr("messages - ?", refund),
+			"tokens":     gorm.Expr("tokens + ?", tokens),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// chatUsageJSON reports the day's usage next to the limits
+func chatUsageJSON(cfg ChatConfig, usage models.ChatUsage) gin.H {
+	return gin.H{
+		"messages_today": usage.Messages,
+		"tokens_today":   usage.Tokens,
+		"daily_messages": cfg.DailyMessages,
+		"daily_tokens":   cfg.DailyTokens,
+		"resets_at":      usage.Day.Add(24 * time.Hour),
+	}
+}
+
+// chatTitle shortens the first message of a thread into its title
+func chatTitle(message string) string {
+	title := strings.Join(strings.Fields(message), " ")
+	if runes := []rune(title); len(runes) > maxChatTitleLength {
+		title = strings.TrimSpace(string(runes[:maxChatTitleLength-1])) + "…"
+	}
+	return title
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestReserveChatMessageConcurrentStaysWithinLimit(t *testing.T) {
+	requireTestDB(t)
+	user := createTestUser(t, 0)
+	cfg := ChatConfig{DailyMessages: 5}
+
+	var reserved sync.WaitGroup
+	results := make(chan bool, parallelRequests)
+	for i := 0; i < parallelRequests; i++ {
+		reserved.Add(1)
+		go func() {
+			defer reserved.Done()
+			_, ok, err := reserveChatMessage(cfg, user.ID)
+			if err != nil {
+				t.Errorf("reserveChatMessage: %v", err)
+			}
+			results <- ok
+		}()
+	}
+	reserved.Wait()
+	close(results)
+
+	granted := 0
+	for ok := range results {
+		if ok {
+			granted++
+		}
+	}
+	if granted != cfg.DailyMessages {
+		t.Fatalf("granted %d messages, want %d", granted, cfg.DailyMessages)
+	}
+
+	usage, err := todaysChatUsage(user.ID)
+	if err != nil {
+		t.Fatalf("todaysChatUsage: %v", err)
+	}
+	if usage.Messages != cfg.DailyMessages {
+		t.Fatalf("messages = %d, want %d", usage.Messages, cfg.DailyMessages)
+	}
+}
+
+func TestRecordChatTokensCountsFailedReplies(t *testing.T) {
+	requireTestDB(t)
+	user := createTestUser(t, 0)
+	cfg := ChatConfig{DailyTokens: 100}
+
+	usage, ok, err := reserveChatMessage(cfg, user.ID)
+	if err != nil || !ok {
+		t.Fatalf("reserveChatMessage = %v, %v", ok, err)
+	}
+	// a reply that failed after using 150 tokens gives the message back but keeps the tokens
+	if err := recordChatTokens(usage, 150, 1); err != nil {
+		t.Fatalf("recordChatTokens: %v", err)
+	}
+
+	stored, err := todaysChatUsage(user.ID)
+	if err != nil {
+		t.Fatalf("todaysChatUsage: %v", err)
+	}
+	if stored.Messages != 0 || stored.Tokens != 150 {
+		t.Fatalf("usage = %d messages, %d tokens; want 0 and 150", stored.Messages, stored.Tokens)
+	}
+
+	if _, ok, err := reserveChatMessage(cfg, user.ID); err != nil || ok {
+		t.Fatalf("reserveChatMessage over the token limit = %v, %v; want refused", ok, err)
+	}
+}
@@ -1,35 +1,59 @@
 package handlers
 
 import (
-	"hash/fnv"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
 	"github.com/gin-gonic/gin"
 )
 
 type PriceCheckRequest struct {
-	SkinName string `json:"skin_name" binding:"required"`
+	SkinName  string `json:"skin_name" binding:"required"`
+	Condition string `json:"condition"` // optional wear condition, e.g. "Field-Tested"
+	StatTrak  bool   `json:"stattrak"`
+	Souvenir  bool   `json:"souvenir"`
 }
 
-// PriceCheckMock provides a deterministic mocked price response.
-func PriceCheckMock(c *gin.Context) {
-	var req PriceCheckRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "skin_name is required"})
-		return
-	}
+// PriceCheck looks up the market price of a skin with the configured price provider.
+func PriceCheck(provider pricing.Provider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PriceCheckRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "skin_name is required"})
+			return
+		}
+		if req.Condition != "" && !models.IsValidCondition(req.Condition) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid condition"})
+			return
+		}
 
-	// Deterministic pseudo-price from skin name for stable UI demos.
-	h := fnv.New32a()
-	_, _ = h.Write([]byte(req.SkinName))
-	seed := float64(h.Sum32()%15000) / 100.0 // 0.00 - 149.99
-	mockPrice := 5.0 + seed                   // 5.00 - 154.99
+		marketHashName := pricing.MarketHashName(req.SkinName, req.Condition, req.StatTrak, req.Souvenir)
+		quote, err := pricing.QuoteSkin(provider, req.SkinName, req.Condition, req.StatTrak, req.Souvenir)
+		if errors.Is(err, pricing.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No market price for %s", marketHashName)})
+			return
+		}
+		if err != nil {
+			log.Printf("⚠️  Price check for %s failed: %v", marketHashName, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Price provider is unavailable, try again later"})
+			return
+		}
 
-	c.JSON(http.StatusOK, gin.H{
-		"provider":      "mock",
-		"skin_name":     req.SkinName,
-		"suggested_usd": mockPrice,
-		"message":       "Mocked AI price check result",
-	})
+		message := fmt.Sprintf("Market price from %s", quote.Provider)
+		if quote.Stale {
+			message += " (cached, the provider is currently unavailable)"
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"provider":         quote.Provider,
+			"skin_name":        req.SkinName,
+			"market_hash_name": quote.MarketHashName,
+			"suggested_usd":    quote.Suggested,
+			"quote":            quote,
+			"message":          message,
+		})
+	}
 }
-
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
 	"github.com/TyronOdame/CS-OPN/backend/models"
-	"github.com/TyronOdame/CS-OPN/backend/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // RegisterRequest represents the expected payload for user registration
@@ -23,7 +29,7 @@ type LoginRequest struct {
 }
 
 // This is the register handler
-func RegisterHandler(jwtSecret string) gin.HandlerFunc {
+func RegisterHandler(cfg AuthConfig) gin.HandlerFunc {
 	return func (c *gin.Context) {
 		var req RegisterRequest
 
@@ -38,7 +44,7 @@ func RegisterHandler(jwtSecret string) gin.HandlerFunc {
 
 		// check if email already exists
 		var existingUser models.User
-		if err := database.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+		if err := database.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil || heldByDeletedAccount("original_email", req.Email) {
 			c.JSON(http.StatusConflict, gin.H{
 				"error": "Email already in use",
 			})
@@ -46,7 +52,7 @@ func RegisterHandler(jwtSecret string) gin.HandlerFunc {
 		}
 
 		//check if username already exists
-		if err := database.DB.Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
+		if err := database.DB.Where("username = ?", req.Username).First(&existingUser).Error; err == nil || heldByDeletedAccount("original_username", req.Username) {
 			c.JSON(http.StatusConflict, gin.H{
 				"error": "Username already in use",
 			})
@@ -69,34 +75,47 @@ func RegisterHandler(jwtSecret string) gin.HandlerFunc {
 	
 		}
 
-		// save user to database
-		if err := database.DB.Create(&user).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to create user",
+		// save user to database, credit the starting balance through the ledger and start a session
+		var tokens gin.H
+		var verificationToken string
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+			_, err := ledger.Post(tx, &user, ledger.Posting{
+				Type:        models.TransactionTypeRegistration,
+				Amount:      models.StartingCasebucks,
+				Counterpart: ledger.AccountRegistrationBonus,
+				Description: "Registration bonus",
 			})
-			return
-		}
-
-		// generate JWT
-		token, err := utils.GenerateJWT(user.ID, user.Email, user.Username, jwtSecret)
+			if err != nil {
+				return err
+			}
+			verificationToken, err = issueUserToken(tx, &user, models.TokenPurposeEmailVerification, models.EmailVerificationTokenTTL)
+			if err != nil {
+				return err
+			}
+			tokens, err = issueSession(c, tx, &user, cfg)
+			return err
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to generate token",
+				"error": "Failed to create user",
 			})
 			return
 		}
 
-		// respond with token
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "User registered successfully",
-			"token":   token,
-			"user":    user.ToJSON(),
-		})
+		sendVerificationEmail(cfg, &user, verificationToken)
+
+		// respond with tokens
+		tokens["message"] = "User registered successfully. Check your email to verify your account"
+		tokens["user"] = user.ToJSON()
+		c.JSON(http.StatusCreated, tokens)
 	}
 }
 
 // This handler logs in a user
-func Login(jwtSecret string) gin.HandlerFunc {
+func Login(cfg AuthConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		var req LoginRequest
@@ -118,41 +137,124 @@ func Login(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// refuse logins while the account is locked out, even with the right password
+		if user.IsLocked() {
+			respondAccountLocked(c, *user.LockedUntil)
+			return
+		}
+
 		// check to see if password is correct
 		if !user.CheckPassword(req.Password) {
+			lockedUntil, err := recordFailedLogin(user.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to log in",
+				})
+				return
+			}
+			if lockedUntil != nil {
+				respondAccountLocked(c, *lockedUntil)
+				return
+			}
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid email or password",
 			})
 			return
 		}
 
-		dailyRewardClaimed, err := ApplyDailyRewardForUser(&user)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to apply daily reward",
-			})
-			return
+		if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+			if err := database.DB.Model(&user).Updates(map[string]interface{}{
+				"failed_login_count": 0,
+				"locked_until":       nil,
+			}).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to log in",
+				})
+				return
+			}
 		}
 
-		// generate JWT
-		token, err := utils.GenerateJWT(user.ID, user.Email, user.Username, jwtSecret)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to generate token",
+		// accounts with 2FA get a challenge instead of tokens until the code is checked
+		if user.IsTwoFactorEnabled() {
+			challengeToken, err := issueUserToken(database.DB, &user, models.TokenPurposeTwoFactorLogin, models.TwoFactorChallengeTTL)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to start two-factor login",
+				})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"message":             "Two-factor code required",
+				"two_factor_required": true,
+				"challenge_token":     challengeToken,
+				"expires_in":          int(models.TwoFactorChallengeTTL.Seconds()),
 			})
 			return
 		}
 
-		// respond with token
-		c.JSON(http.StatusOK, gin.H{
-			"message":              "Login successful",
-			"token":                token,
-			"user":                 user.ToJSON(),
-			"daily_reward_claimed": dailyRewardClaimed,
-			"daily_reward_amount":  DailyLoginRewardAmount,
+		completeLogin(c, &user, cfg)
+	}
+}
+
+// recordFailedLogin counts a wrong password and locks the account once the threshold is passed.
+// It returns the lock expiry when the account is now locked.
+func recordFailedLogin(userID uuid.UUID) (*time.Time, error) {
+	var lockedUntil *time.Time
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// unscoped so attempts to restore a soft-deleted account are counted too
+		var user models.User
+		if err := database.ForUpdate(tx.Unscoped()).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		failedLogins := user.FailedLoginCount + 1
+		updates := map[string]interface{}{"failed_login_count": failedLogins}
+		if lockout := models.LockoutDuration(failedLogins); lockout > 0 {
+			until := time.Now().Add(lockout)
+			lockedUntil = &until
+			updates["locked_until"] = until
+		}
+		return tx.Unscoped().Model(&user).Updates(updates).Error
+	})
+	return lockedUntil, err
+}
+
+// respondAccountLocked tells the client how long the account stays locked
+func respondAccountLocked(c *gin.Context, lockedUntil time.Time) {
+	retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       fmt.Sprintf("Too many failed login attempts. Try again in %d seconds", retryAfter),
+		"retry_after": retryAfter,
+	})
+}
+
+// completeLogin applies the daily reward, starts a session and responds with the tokens
+func completeLogin(c *gin.Context, user *models.User, cfg AuthConfig) {
+	dailyRewardClaimed, err := ApplyDailyRewardForUser(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to apply daily reward",
 		})
+		return
+	}
 
+	// start a session and generate tokens
+	tokens, err := issueSession(c, database.DB, user, cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate token",
+		})
+		return
 	}
+
+	// respond with tokens
+	tokens["message"] = "Login successful"
+	tokens["user"] = user.ToJSON()
+	tokens["daily_reward_claimed"] = dailyRewardClaimed
+	tokens["daily_reward_amount"] = DailyLoginRewardAmount
+	tokens["email_verified"] = user.IsEmailVerified()
+	c.JSON(http.StatusOK, tokens)
 }
 		
 	
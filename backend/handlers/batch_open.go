@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OpenBatchRequest selects purchased cases to open: either explicit IDs, or the
+// oldest Count unopened purchases of CaseID
+type OpenBatchRequest struct {
+	UserCaseIDs []uuid.UUID `json:"user_case_ids"`
+	CaseID      *uuid.UUID  `json:"case_id"`
+	Count       int         `json:"count"`
+}
+
+// batchOpenError is a batch failure with the status and message to respond with
+type batchOpenError struct {
+	status  int
+	message string
+}
+
+func (e *batchOpenError) Error() string {
+	return e.message
+}
+
+// OpenPurchasedCasesBatch opens several purchased cases in one transaction.
+// Either every case opens or none do.
+func OpenPurchasedCasesBatch(maxBatchSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req OpenBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		userCaseIDs := uniqueIDs(req.UserCaseIDs)
+		requested := len(userCaseIDs)
+		switch {
+		case requested > 0 && req.CaseID != nil:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Send either user_case_ids or case_id with count, not both"})
+			return
+		case req.CaseID != nil:
+			if req.Count <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "count must be at least 1"})
+				return
+			}
+			requested = req.Count
+		case requested == 0:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_case_ids or case_id with count is required"})
+			return
+		}
+		if requested > maxBatchSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d cases can be opened at once", maxBatchSize)})
+			return
+		}
+
+		var user models.User
+		var results []gin.H
+		var summary *batchSummary
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			// lock the user row first, then the purchased cases so each can only be opened once
+			if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+				return err
+			}
+
+			userCases, err := lockBatchUserCases(tx, userID, userCaseIDs, req.CaseID, requested)
+			if err != nil {
+				return err
+			}
+
+			results, summary, err = openUserCases(tx, &user, userCases)
+			return err
+		})
+		var batchErr *batchOpenError
+		if errors.As(err, &batchErr) {
+			c.JSON(batchErr.status, gin.H{"error": batchErr.message})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open cases"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     fmt.Sprintf("Opened %d cases!", len(results)),
+			"results":     results,
+			"summary":     summary.toJSON(),
+			"new_balance": user.Casebucks,
+		})
+	}
+}
+
+// openUserCases opens locked, unopened purchased cases and marks them opened.
+// The user row must be locked by the caller.
+func openUserCases(tx *gorm.DB, user *models.User, userCases []models.UserCase) ([]gin.H, *batchSummary, error) {
+	summary := newBatchSummary()
+	results := make([]gin.H, 0, len(userCases))
+	now := time.Now()
+	for i := range userCases {
+		userCase := &userCases[i]
+		result, err := openCaseForUser(tx, user.ID, userCase.Case)
+		if err != nil {
+			return nil, nil, &batchOpenError{status: openCaseErrorStatus(err), message: openCaseErrorMessage(err)}
+		}
+
+		if err := tx.Model(userCase).Updates(map[string]interface{}{
+			"is_opened": true,
+			"opened_at": now,
+		}).Error; err != nil {
+			return nil, nil, err
+		}
+
+		// the case was paid for when bought, so this records a zero-amount event
+		transaction, err := ledger.Post(tx, user, ledger.Posting{
+			Type:        models.TransactionTypeCaseOpen,
+			Amount:      0,
+			Description: "Opened purchased " + userCase.Case.Name,
+			ReferenceID: &userCase.CaseID,
+			PatternTier: result.Inventory.PatternTier,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		summary.add(result)
+		opened := result.toJSON()
+		opened["user_case_id"] = userCase.ID
+		opened["case"] = userCase.Case.ToJSON()
+		opened["transaction_id"] = transaction.ID
+		results = append(results, opened)
+	}
+	return results, summary, nil
+}
+
+// lockBatchUserCases locks the requested unopened cases in a stable order
+func lockBatchUserCases(tx *gorm.DB, userID uuid.UUID, userCaseIDs []uuid.UUID, caseID *uuid.UUID, count int) ([]models.UserCase, error) {
+	var userCases []models.UserCase
+	query := database.ForUpdate(tx).Preload("Case").Where("user_id = ? AND is_opened = ?", userID, false)
+
+	if caseID != nil {
+		if err := query.Where("case_id = ?", *caseID).
+			Order("created_at ASC").Order("id ASC").
+			Limit(count).
+			Find(&userCases).Error; err != nil {
+			return nil, err
+		}
+		if len(userCases) < count {
+			return nil, &batchOpenError{
+				status:  http.StatusConflict,
+				message: fmt.Sprintf("Only %d unopened cases of that kind in your inventory", len(userCases)),
+			}
+		}
+		return userCases, nil
+	}
+
+	if err := query.Where("id IN ?", userCaseIDs).Order("id ASC").Find(&userCases).Error; err != nil {
+		return nil, err
+	}
+	if len(userCases) != len(userCaseIDs) {
+		return nil, &batchOpenError{
+			status:  http.StatusNotFound,
+			message: "One or more purchased cases were not found or are already opened",
+		}
+	}
+	return userCases, nil
+}
+
+// uniqueIDs drops duplicate IDs, keeping the first occurrence
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// batchSummary aggregates the drops of a batch opening
+type batchSummary struct {
+	count      int
+	totalValue models.Money
+	best       *caseOpenResult
+	rarities   map[string]int
+}
+
+func newBatchSummary() *batchSummary {
+	return &batchSummary{rarities: make(map[string]int)}
+}
+
+// add counts one opening in the summary
+func (s *batchSummary) add(result *caseOpenResult) {
+	s.count++
+	s.totalValue += result.Value
+	s.rarities[result.Skin.Rarity]++
+	if s.best == nil || result.Value > s.best.Value {
+		s.best = result
+	}
+}
+
+// toJSON converts the summary to a JSON-compatible map
+func (s *batchSummary) toJSON() map[string]interface{} {
+	summary := map[string]interface{}{
+		"count":            s.count,
+		"total_value":      s.totalValue,
+		"rarity_breakdown": s.rarities,
+		"best_drop":        nil,
+	}
+	if s.best != nil {
+		summary["best_drop"] = s.best.toJSON()
+	}
+	return summary
+}
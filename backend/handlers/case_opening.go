@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/fairness"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// caseUnavailableMessage is shown for cases whose drop table is missing or fails validation
+const caseUnavailableMessage = "This case is temporarily unavailable"
+
+// openCaseError carries the client-facing status and message for a failed opening step
+type openCaseError struct {
+	status  int // defaults to 500
+	message string
+	err     error
+}
+
+func (e *openCaseError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *openCaseError) Unwrap() error {
+	return e.err
+}
+
+// openCaseErrorMessage returns the message to show the client for an opening failure
+func openCaseErrorMessage(err error) string {
+	var openErr *openCaseError
+	if errors.As(err, &openErr) {
+		return openErr.message
+	}
+	return "Failed to open case"
+}
+
+// openCaseErrorStatus returns the HTTP status for an opening failure
+func openCaseErrorStatus(err error) int {
+	var openErr *openCaseError
+	if errors.As(err, &openErr) && openErr.status != 0 {
+		return openErr.status
+	}
+	return http.StatusInternalServerError
+}
+
+// caseOpenResult is everything produced by opening a single case
+type caseOpenResult struct {
+	Skin      models.Skin
+	Float     float64
+	Value     models.Money
+	Inventory models.Inventory
+	Opening   models.CaseOpening
+}
+
+// fairnessJSON returns the provably fair inputs of the opening for API responses
+func (r *caseOpenResult) fairnessJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"opening_id":       r.Opening.ID,
+		"server_seed_hash": r.Opening.ServerSeedHash,
+		"client_seed":      r.Opening.ClientSeed,
+		"nonce":            r.Opening.Nonce,
+	}
+}
+
+// toJSON returns the drop for API responses; callers add the case, balance and transaction
+func (r *caseOpenResult) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"skin":         r.Skin.ToJSON(),
+		"float":        r.Float,
+		"condition":    r.Inventory.GetCondition(),
+		"stattrak":     r.Inventory.IsStatTrak,
+		"souvenir":     r.Inventory.IsSouvenir,
+		"pattern_seed": r.Inventory.PatternSeed,
+		"pattern_tier": r.Inventory.PatternTier,
+		"value":        r.Value,
+		"inventory_id": r.Inventory.ID,
+		"fairness":     r.fairnessJSON(),
+	}
+}
+
+// openCaseForUser rolls a drop from the case using the user's active fairness seed,
+// adds it to their inventory and records the opening. It does not touch the balance.
+func openCaseForUser(tx *gorm.DB, userID uuid.UUID, caseItem models.Case) (*caseOpenResult, error) {
+	dropTable, contents, err := loadOpenableDropTable(tx, caseItem)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, err := lockActiveFairnessSeed(tx, userID)
+	if err != nil {
+		return nil, &openCaseError{message: "Failed to load fairness seed", err: err}
+	}
+
+	// every opening consumes the next nonce of the seed pair
+	seed.Nonce++
+	if err := tx.Model(seed).Update("nonce", seed.Nonce).Error; err != nil {
+		return nil, &openCaseError{message: "Failed to update fairness seed", err: err}
+	}
+
+	skinRoll := fairness.Roll(seed.ServerSeed, seed.ClientSeed, seed.Nonce, fairness.CursorSkin)
+	floatRoll := fairness.Roll(seed.ServerSeed, seed.ClientSeed, seed.Nonce, fairness.CursorFloat)
+	statTrakRoll := fairness.Roll(seed.ServerSeed, seed.ClientSeed, seed.Nonce, fairness.CursorStatTrak)
+	patternRoll := fairness.Roll(seed.ServerSeed, seed.ClientSeed, seed.Nonce, fairness.CursorPattern)
+
+	selectedContent := selectSkin(contents, skinRoll)
+	skin := selectedContent.Skin
+	skinFloat := generateFloat(floatRoll, fairness.FloatMethodWearBuckets, skin.MinFloat, skin.MaxFloat)
+	statTrakChance := effectiveStatTrakChance(caseItem)
+	patternSeed := patternSeedFromRoll(patternRoll)
+
+	var patternRules []models.PatternRule
+	if err := tx.Where("skin_id = ?", skin.ID).Find(&patternRules).Error; err != nil {
+		return nil, &openCaseError{message: "Failed to fetch pattern rules", err: err}
+	}
+	patternRule := models.MatchPatternRule(patternRules, patternSeed)
+
+	inventory := models.Inventory{
+		UserID:       userID,
+		SkinID:       skin.ID,
+		Float:        skinFloat,
+		AcquiredFrom: caseItem.Name,
+		IsStatTrak:   isStatTrak(statTrakRoll, statTrakChance),
+		IsSouvenir:   caseItem.IsSouvenirPackage,
+		PatternSeed:  patternSeed,
+		IsSold:       false,
+	}
+	if patternRule != nil {
+		inventory.PatternTier = patternRule.Tier
+	}
+	inventory.Value = calculateSkinValue(skin, &inventory, patternRule)
+	skinValue := inventory.Value
+	if err := tx.Create(&inventory).Error; err != nil {
+		return nil, &openCaseError{message: "Failed to add skin to inventory", err: err}
+	}
+
+	opening := models.CaseOpening{
+		UserID:         userID,
+		CaseID:         caseItem.ID,
+		DropTableID:    &dropTable.ID,
+		InventoryID:    inventory.ID,
+		FairnessSeedID: seed.ID,
+		ServerSeedHash: seed.ServerSeedHash,
+		ClientSeed:     seed.ClientSeed,
+		Nonce:          seed.Nonce,
+		SkinRoll:       skinRoll,
+		FloatRoll:      floatRoll,
+		FloatMethod:    fairness.FloatMethodWearBuckets,
+		MinFloat:       skin.MinFloat,
+		MaxFloat:       skin.MaxFloat,
+		StatTrakRoll:   &statTrakRoll,
+		StatTrakChance: statTrakChance,
+		SkinID:         skin.ID,
+		Float:          skinFloat,
+		IsStatTrak:     inventory.IsStatTrak,
+		IsSouvenir:     inventory.IsSouvenir,
+		PatternRoll:    &patternRoll,
+		PatternSeed:    &patternSeed,
+		PatternTier:    inventory.PatternTier,
+	}
+	if err := tx.Create(&opening).Error; err != nil {
+		return nil, &openCaseError{message: "Failed to record case opening", err: err}
+	}
+
+	return &caseOpenResult{
+		Skin:      skin,
+		Float:     skinFloat,
+		Value:     skinValue,
+		Inventory: inventory,
+		Opening:   opening,
+	}, nil
+}
+
+// loadOpenableDropTable returns the case's active drop table and contents, refusing the case
+// when the table is missing or its chances are broken so nobody pays for a case that can't open
+func loadOpenableDropTable(tx *gorm.DB, caseItem models.Case) (*models.DropTable, []models.CaseContent, error) {
+	dropTable, contents, err := loadActiveDropTable(tx, caseItem.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = errors.New("no active drop table")
+	} else if err != nil {
+		return nil, nil, &openCaseError{message: "Failed to fetch case contents", err: err}
+	} else {
+		err = models.ValidateCaseContents(contents)
+	}
+	if err != nil {
+		log.Printf("⚠️  Refused case %s: %v", caseItem.Name, err)
+		return nil, nil, &openCaseError{status: http.StatusServiceUnavailable, message: caseUnavailableMessage, err: err}
+	}
+	return dropTable, contents, nil
+}
+
+// loadActiveDropTable returns the case's current drop table version and its contents
+func loadActiveDropTable(tx *gorm.DB, caseID uuid.UUID) (*models.DropTable, []models.CaseContent, error) {
+	var dropTable models.DropTable
+	if err := tx.Where("case_id = ? AND retired_at IS NULL", caseID).First(&dropTable).Error; err != nil {
+		return nil, nil, err
+	}
+
+	contents, err := loadDropTableContents(tx, dropTable.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &dropTable, contents, nil
+}
+
+// loadDropTableContents returns a drop table version's contents in the stable order used for rolls
+func loadDropTableContents(tx *gorm.DB, dropTableID uuid.UUID) ([]models.CaseContent, error) {
+	var contents []models.CaseContent
+	err := tx.Preload("Skin").Where("drop_table_id = ?", dropTableID).Order("id ASC").Find(&contents).Error
+	return contents, err
+}
+
+// lockActiveFairnessSeed returns the user's active seed pair, creating one if needed.
+// The row is locked so concurrent openings never reuse a nonce.
+func lockActiveFairnessSeed(tx *gorm.DB, userID uuid.UUID) (*models.FairnessSeed, error) {
+	var seed models.FairnessSeed
+	err := database.ForUpdate(tx).
+		Where("user_id = ? AND revealed_at IS NULL", userID).
+		First(&seed).Error
+	if err == nil {
+		return &seed, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return createFairnessSeed(tx, userID, "")
+}
+
+// createFairnessSeed creates a new active seed pair for the user.
+// A random client seed is generated when none is given.
+func createFairnessSeed(tx *gorm.DB, userID uuid.UUID, clientSeed string) (*models.FairnessSeed, error) {
+	serverSeed, err := fairness.GenerateServerSeed()
+	if err != nil {
+		return nil, err
+	}
+	if clientSeed == "" {
+		clientSeed, err = fairness.GenerateClientSeed()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seed := models.FairnessSeed{
+		UserID:         userID,
+		ServerSeed:     serverSeed,
+		ServerSeedHash: fairness.HashServerSeed(serverSeed),
+		ClientSeed:     clientSeed,
+	}
+	if err := tx.Create(&seed).Error; err != nil {
+		return nil, err
+	}
+	return &seed, nil
+}
+
+// selectSkin uses weighted selection based on drop chances.
+// roll must be in [0, 1) and contents must be in a stable order.
+func selectSkin(contents []models.CaseContent, roll float64) *models.CaseContent {
+	if len(contents) == 0 {
+		return nil
+	}
+
+	// Calculate total drop chance (should sum to ~1.0)
+	var totalChance float64
+	for _, content := range contents {
+		totalChance += content.DropChance
+	}
+
+	target := roll * totalChance
+
+	// Select skin based on weighted probability
+	var cumulativeChance float64
+	for i := range contents {
+		cumulativeChance += contents[i].DropChance
+		if target < cumulativeChance {
+			return &contents[i]
+		}
+	}
+
+	// Fallback to last skin (only reachable through float rounding)
+	return &contents[len(contents)-1]
+}
+
+// generateFloat maps a roll to a wear float using the given float method.
+// Wear bucket floats are scaled into the skin's float cap.
+func generateFloat(roll float64, method string, minFloat, maxFloat float64) float64 {
+	if method == fairness.FloatMethodUniform {
+		return roll
+	}
+	return models.FloatFromRoll(roll, minFloat, maxFloat)
+}
+
+// effectiveStatTrakChance returns the case's StatTrak probability.
+// Souvenir package drops are always Souvenir and never StatTrak.
+func effectiveStatTrakChance(caseItem models.Case) float64 {
+	if caseItem.IsSouvenirPackage {
+		return 0
+	}
+	return caseItem.StatTrakChance
+}
+
+// isStatTrak decides from the StatTrak roll whether a drop is StatTrak
+func isStatTrak(roll, chance float64) bool {
+	return roll < chance
+}
+
+// patternSeedFromRoll maps a roll to a pattern seed between 0 and models.MaxPatternSeed
+func patternSeedFromRoll(roll float64) int {
+	return int(roll * (models.MaxPatternSeed + 1))
+}
+
+// calculateSkinValue prices a dropped item at the skin's price for the item's wear condition,
+// with the premiums of its variant (StatTrak, Souvenir) and special pattern on top
+func calculateSkinValue(skin models.Skin, item *models.Inventory, patternRule *models.PatternRule) models.Money {
+	multiplier := item.ValueMultiplier()
+	if patternRule != nil {
+		multiplier *= patternRule.Multiplier
+	}
+	return skin.PriceForCondition(item.GetCondition()).MulFloat(multiplier)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/TyronOdame/CS-OPN/backend/fairness"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+)
+
+func TestSelectSkinFollowsCumulativeChances(t *testing.T) {
+	contents := []models.CaseContent{
+		{Skin: models.Skin{Name: "common"}, DropChance: 0.7},
+		{Skin: models.Skin{Name: "rare"}, DropChance: 0.25},
+		{Skin: models.Skin{Name: "knife"}, DropChance: 0.05},
+	}
+
+	tests := []struct {
+		roll float64
+		want string
+	}{
+		{0, "common"},
+		{0.6999, "common"},
+		{0.7, "rare"},
+		{0.9499, "rare"},
+		{0.95, "knife"},
+		{0.999999, "knife"},
+	}
+	for _, tt := range tests {
+		if got := selectSkin(contents, tt.roll); got.Skin.Name != tt.want {
+			t.Errorf("selectSkin(%v) = %s, want %s", tt.roll, got.Skin.Name, tt.want)
+		}
+	}
+}
+
+func TestSelectSkinNormalizesChances(t *testing.T) {
+	// chances that don't sum to 1 are scaled so every roll still picks a skin
+	contents := []models.CaseContent{
+		{Skin: models.Skin{Name: "a"}, DropChance: 2},
+		{Skin: models.Skin{Name: "b"}, DropChance: 2},
+	}
+	if got := selectSkin(contents, 0.49); got.Skin.Name != "a" {
+		t.Errorf("selectSkin(0.49) = %s, want a", got.Skin.Name)
+	}
+	if got := selectSkin(contents, 0.5); got.Skin.Name != "b" {
+		t.Errorf("selectSkin(0.5) = %s, want b", got.Skin.Name)
+	}
+}
+
+func TestSelectSkinEmpty(t *testing.T) {
+	if got := selectSkin(nil, 0.5); got != nil {
+		t.Fatalf("selectSkin(nil) = %v, want nil", got)
+	}
+}
+
+func TestGenerateFloatByMethod(t *testing.T) {
+	if got := generateFloat(0.5, fairness.FloatMethodUniform, 0.2, 0.4); got != 0.5 {
+		t.Errorf("uniform float = %v, want the roll", got)
+	}
+	if got, want := generateFloat(0.5, fairness.FloatMethodWearBuckets, 0.2, 0.4), models.FloatFromRoll(0.5, 0.2, 0.4); got != want {
+		t.Errorf("wear bucket float = %v, want %v", got, want)
+	}
+}
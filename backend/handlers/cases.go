@@ -1,109 +1,188 @@
 package handlers
 
 import (
-    "math/rand"
+    "errors"
+    "fmt"
+    "io"
     "net/http"
-    "time"
 
     "github.com/TyronOdame/CS-OPN/backend/database"
+    "github.com/TyronOdame/CS-OPN/backend/ledger"
     "github.com/TyronOdame/CS-OPN/backend/middleware"
     "github.com/TyronOdame/CS-OPN/backend/models"
     "github.com/gin-gonic/gin"
     "github.com/google/uuid"
 )
 
-// BuyCase purchases a case and puts it in user's case inventory.
-func BuyCase(c *gin.Context) {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
-	caseID := c.Param("id")
-	parsedCaseID, err := uuid.Parse(caseID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid case ID"})
-		return
-	}
-
-	tx := database.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	var caseItem models.Case
-	if err := tx.First(&caseItem, "id = ? AND is_active = ?", parsedCaseID, true).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
-		return
-	}
-
-	var user models.User
-	if err := tx.First(&user, "id = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
-		return
-	}
-
-	if user.Casebucks < caseItem.Price {
-		tx.Rollback()
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":           "Insufficient Case Bucks",
-			"required":        caseItem.Price,
-			"current_balance": user.Casebucks,
-		})
-		return
-	}
-
-	balanceBefore := user.Casebucks
-	user.Casebucks -= caseItem.Price
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update balance"})
-		return
-	}
-
-	userCase := models.UserCase{
-		UserID:   userID,
-		CaseID:   caseItem.ID,
-		IsOpened: false,
-	}
-	if err := tx.Create(&userCase).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add case to inventory"})
-		return
-	}
-
-	transaction := models.Transaction{
-		UserID:        userID,
-		Type:          models.TransactionTypeCaseBuy,
-		Amount:        -caseItem.Price,
-		BalanceBefore: balanceBefore,
-		BalanceAfter:  user.Casebucks,
-		Description:   "Bought " + caseItem.Name,
-		ReferenceID:   &caseItem.ID,
-	}
-	if err := tx.Create(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transaction"})
-		return
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete purchase"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message":        "Case purchased successfully!",
-		"purchased_case": userCase.ToJSON(),
-		"case":           caseItem.ToJSON(),
-		"new_balance":    user.Casebucks,
-		"transaction_id": transaction.ID,
-	})
+// BuyCaseRequest is the optional body of a case purchase
+type BuyCaseRequest struct {
+    Quantity int  `json:"quantity"` // defaults to 1
+    Open     bool `json:"open"`     // open every purchased case straight away
+}
+
+// BuyCase purchases one or more cases and puts them in user's case inventory,
+// applying the case's bulk discount and optionally opening them right away.
+func BuyCase(maxQuantity int) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        userID, err := middleware.GetUserID(c)
+        if err != nil {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+            return
+        }
+
+        caseID := c.Param("id")
+        parsedCaseID, err := uuid.Parse(caseID)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid case ID"})
+            return
+        }
+
+        // the body is optional; an empty one buys a single case
+        var req BuyCaseRequest
+        if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+            return
+        }
+        if req.Quantity == 0 {
+            req.Quantity = 1
+        }
+        if req.Quantity < 1 || req.Quantity > maxQuantity {
+            c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("quantity must be between 1 and %d", maxQuantity)})
+            return
+        }
+
+        tx := database.DB.Begin()
+        defer func() {
+            if r := recover(); r != nil {
+                tx.Rollback()
+            }
+        }()
+
+        var caseItem models.Case
+        if err := tx.First(&caseItem, "id = ? AND is_active = ?", parsedCaseID, true).Error; err != nil {
+            tx.Rollback()
+            c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+            return
+        }
+
+        // a case that could not be opened is not sold either
+        if _, _, err := loadOpenableDropTable(tx, caseItem); err != nil {
+            tx.Rollback()
+            c.JSON(openCaseErrorStatus(err), gin.H{"error": openCaseErrorMessage(err)})
+            return
+        }
+
+        // lock the user row so concurrent purchases cannot both pass the balance check
+        var user models.User
+        if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+            tx.Rollback()
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+            return
+        }
+
+        subtotal, discount, total := caseItem.PriceFor(req.Quantity)
+        if user.Casebucks < total {
+            tx.Rollback()
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error":           "Insufficient Case Bucks",
+                "required":        total,
+                "current_balance": user.Casebucks,
+            })
+            return
+        }
+
+        userCases := make([]models.UserCase, req.Quantity)
+        for i := range userCases {
+            userCases[i] = models.UserCase{
+                UserID:   userID,
+                CaseID:   caseItem.ID,
+                IsOpened: false,
+            }
+        }
+        if err := tx.Create(&userCases).Error; err != nil {
+            tx.Rollback()
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add case to inventory"})
+            return
+        }
+
+        // Deduct the price of the whole order in one posting to the case sales account
+        description := "Bought " + caseItem.Name
+        if req.Quantity > 1 {
+            description = fmt.Sprintf("Bought %dx %s", req.Quantity, caseItem.Name)
+        }
+        if discount > 0 {
+            description += fmt.Sprintf(" (%g%% bulk discount)", caseItem.DiscountFor(req.Quantity).PercentOff)
+        }
+        transaction, err := ledger.Post(tx, &user, ledger.Posting{
+            Type:        models.TransactionTypeCaseBuy,
+            Amount:      -total,
+            Counterpart: ledger.AccountCaseSales,
+            Description: description,
+            ReferenceID: &caseItem.ID,
+        })
+        if err != nil {
+            tx.Rollback()
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record transaction"})
+            return
+        }
+
+        // opening needs the case on each row, the same as when it is loaded with Preload
+        var openResults []gin.H
+        var summary *batchSummary
+        if req.Open {
+            for i := range userCases {
+                userCases[i].Case = caseItem
+            }
+            openResults, summary, err = openUserCases(tx, &user, userCases)
+            if err != nil {
+                tx.Rollback()
+                var batchErr *batchOpenError
+                if errors.As(err, &batchErr) {
+                    c.JSON(batchErr.status, gin.H{"error": batchErr.message})
+                    return
+                }
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open cases"})
+                return
+            }
+        }
+
+        if err := tx.Commit().Error; err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete purchase"})
+            return
+        }
+
+        purchasedCases := make([]map[string]interface{}, 0, len(userCases))
+        for _, userCase := range userCases {
+            purchasedCases = append(purchasedCases, userCase.ToJSON())
+        }
+
+        response := gin.H{
+            "message":         "Case purchased successfully!",
+            "purchased_case":  purchasedCases[0],
+            "purchased_cases": purchasedCases,
+            "quantity":        req.Quantity,
+            "subtotal":        subtotal,
+            "discount":        discount,
+            "total_price":     total,
+            "case":            caseItem.ToJSON(),
+            "new_balance":     user.Casebucks,
+            "transaction_id":  transaction.ID,
+        }
+        if req.Open {
+            response["message"] = "Case purchased and opened successfully!"
+            response["results"] = openResults
+            response["summary"] = summary.toJSON()
+            // a single buy-and-open also carries the drop at the top level, like OpenCase
+            if req.Quantity == 1 {
+                for key, value := range openResults[0] {
+                    if key != "transaction_id" && key != "case" {
+                        response[key] = value
+                    }
+                }
+            }
+        }
+        c.JSON(http.StatusOK, response)
+    }
 }
 
 // GetAllCases returns all active cases
@@ -149,9 +228,12 @@ func GetCaseByID(c *gin.Context) {
         return
     }
 
-    // get all skins in this case with drop chances
+    // get all skins in the case's active drop table with drop chances
     var contents []models.CaseContent
-    if err := database.DB.Preload("Skin").Where("case_id = ?", caseID).Find(&contents).Error; err != nil {
+    if err := database.DB.Preload("Skin").
+        Joins("JOIN drop_tables ON drop_tables.id = case_contents.drop_table_id").
+        Where("case_contents.case_id = ? AND drop_tables.retired_at IS NULL", caseID).
+        Find(&contents).Error; err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{  // Changed from NotFound
             "error": "Failed to fetch case contents",
         })
@@ -221,9 +303,9 @@ func OpenCase(c *gin.Context) {
         return
     }
 
-    // Get user and check balance
+    // Get user and check balance (row is locked until commit)
     var user models.User
-    if err := tx.First(&user, "id = ?", userID).Error; err != nil {
+    if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
         tx.Rollback()
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to fetch user",
@@ -242,76 +324,29 @@ func OpenCase(c *gin.Context) {
         return
     }
 
-    // Get all possible skins in the case with drop chances
-    var contents []models.CaseContent
-    if err := tx.Preload("Skin").Where("case_id = ?", parsedCaseID).Find(&contents).Error; err != nil {
-        tx.Rollback()
-        c.JSON(http.StatusInternalServerError, gin.H{
-            "error": "Failed to fetch case contents",
-        })
-        return
-    }
-
-    // Select random skin based on drop chances
-    selectedContent := selectRandomSkin(contents)  // ✅ Fixed: Removed ', err'
-    if selectedContent == nil {
-        tx.Rollback()
-        c.JSON(http.StatusInternalServerError, gin.H{
-            "error": "Failed to select skin",
-        })
-        return
-    }
-
-    // Generate random float for skins 
-    randomFloat := generateFloat()
-
-    // Calculate value based on the float 
-    skin := selectedContent.Skin
-    floatMultiplier := 1.0 - randomFloat // better float will result in higher value
-    skinValue := skin.MinValue + (skin.MaxValue-skin.MinValue)*floatMultiplier
-
-    // Deduct Case Bucks from user 
-    balanceBefore := user.Casebucks
-    user.Casebucks -= caseItem.Price
-    if err := tx.Save(&user).Error; err != nil {
-        tx.Rollback()
-        c.JSON(http.StatusInternalServerError, gin.H{
-            "error": "Failed to update balance",
-        })
-        return 
-    }
-
-    // Create transaction record for case opening
-    transaction := models.Transaction{
-        UserID:        userID,
-        Type:          models.TransactionTypeCaseOpen,
-        Amount:        -caseItem.Price,
-        BalanceBefore: balanceBefore,
-        BalanceAfter:  user.Casebucks,
-        Description:   "Opened " + caseItem.Name,
-        ReferenceID:   &parsedCaseID,
-    }
-    if err := tx.Create(&transaction).Error; err != nil {
+    // Roll the drop and add it to the user's inventory
+    result, err := openCaseForUser(tx, userID, caseItem)
+    if err != nil {
         tx.Rollback()
-        c.JSON(http.StatusInternalServerError, gin.H{
-            "error": "Failed to create transaction",
+        c.JSON(openCaseErrorStatus(err), gin.H{
+            "error": openCaseErrorMessage(err),
         })
         return
     }
 
-    // Add skin to user's inventory
-    inventory := models.Inventory{
-        UserID:       userID,
-        SkinID:       skin.ID,
-        Float:        randomFloat,
-        AcquiredFrom: caseItem.Name,
-        Value:        skinValue,
-        IsSold:       false,
-    }
-    if err := tx.Create(&inventory).Error; err != nil {
+    // Deduct Case Bucks from user and post them to the case sales account
+    transaction, err := ledger.Post(tx, &user, ledger.Posting{
+        Type:        models.TransactionTypeCaseOpen,
+        Amount:      -caseItem.Price,
+        Counterpart: ledger.AccountCaseSales,
+        Description: "Opened " + caseItem.Name,
+        ReferenceID: &parsedCaseID,
+        PatternTier: result.Inventory.PatternTier,
+    })
+    if err != nil {
         tx.Rollback()
         c.JSON(http.StatusInternalServerError, gin.H{
-            "error": "Failed to add skin to inventory",
+            "error": "Failed to record transaction",
         })
         return
     }
@@ -325,50 +360,10 @@ func OpenCase(c *gin.Context) {
     }
 
     // Build response
-    c.JSON(http.StatusOK, gin.H{
-        "message":        "Case opened successfully!",
-        "case":           caseItem.ToJSON(),
-        "skin":           skin.ToJSON(),
-        "float":          randomFloat,
-        "condition":      inventory.GetCondition(),
-        "value":          skinValue,
-        "new_balance":    user.Casebucks,
-        "inventory_id":   inventory.ID,
-        "transaction_id": transaction.ID,
-    })
-}
-
-// selectRandomSkin uses weighted random selection based on drop chances
-func selectRandomSkin(contents []models.CaseContent) *models.CaseContent {
-    if len(contents) == 0 {
-        return nil
-    }
-
-    // Calculate total drop chance (should sum to ~1.0)
-    var totalChance float64
-    for _, content := range contents {
-        totalChance += content.DropChance
-    }
-
-    // Generate random number between 0 and total chance
-    rand.Seed(time.Now().UnixNano())
-    randomValue := rand.Float64() * totalChance
-
-    // Select skin based on weighted probability
-    var cumulativeChance float64
-    for i := range contents {
-        cumulativeChance += contents[i].DropChance
-        if randomValue <= cumulativeChance {
-            return &contents[i]
-        }
-    }
-
-    // Fallback to last skin (shouldn't happen)
-    return &contents[len(contents)-1]
+    response := result.toJSON()
+    response["message"] = "Case opened successfully!"
+    response["case"] = caseItem.ToJSON()
+    response["new_balance"] = user.Casebucks
+    response["transaction_id"] = transaction.ID
+    c.JSON(http.StatusOK, response)
 }
-
-// generateFloat generates a random float value between 0.0 and 1.0
-func generateFloat() float64 {
-    rand.Seed(time.Now().UnixNano())
-    return rand.Float64()
-}
\ No newline at end of file
@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// These tests need a disposable Postgres database, e.g.
+// TEST_DATABASE_URL="host=localhost user=postgres password=postgres dbname=csopn_test sslmode=disable"
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+// parallelRequests is how many identical requests each test fires at once
+const parallelRequests = 20
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+// requireTestDB connects database.DB to the test database and migrates it, or skips the test
+func requireTestDB(t *testing.T) {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	testDBOnce.Do(func() {
+		database.DB, testDBErr = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if testDBErr != nil {
+			return
+		}
+		if _, testDBErr = database.MigrateUp(); testDBErr != nil {
+			return
+		}
+		testDBErr = ledger.EnsureSystemAccounts(database.DB)
+	})
+	if testDBErr != nil {
+		t.Fatalf("test database setup failed: %v", testDBErr)
+	}
+	gin.SetMode(gin.TestMode)
+}
+
+// createTestUser creates a user whose balance is posted through the ledger like a real grant
+func createTestUser(t *testing.T, balance models.Money) models.User {
+	t.Helper()
+	suffix := uuid.NewString()[:8]
+	user := models.User{
+		Email:    "concurrency-" + suffix + "@example.com",
+		Username: "concurrency-" + suffix,
+		Password: "not-a-real-hash",
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if _, err := createFairnessSeed(tx, user.ID, ""); err != nil {
+			return err
+		}
+		_, err := ledger.Post(tx, &user, ledger.Posting{
+			Type:        models.TransactionTypeAdjustment,
+			Amount:      balance,
+			Counterpart: ledger.AccountOperatorGrants,
+			Description: "Test balance",
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+// createTestCase creates an active case with a single skin in its drop table
+func createTestCase(t *testing.T, price models.Money) (models.Case, models.Skin) {
+	t.Helper()
+	suffix := uuid.NewString()[:8]
+	skin := models.Skin{
+		Slug:               "concurrency-skin-" + suffix,
+		Name:               "AK-47 | Concurrency " + suffix,
+		WeaponType:         "Rifle",
+		Rarity:             "Mil-Spec",
+		MinFloat:           0,
+		MaxFloat:           1,
+		MinValue:           models.Money(50),
+		MaxValue:           models.Money(500),
+		FactoryNewPrice:    models.Money(500),
+		MinimalWearPrice:   models.Money(300),
+		FieldTestedPrice:   models.Money(150),
+		WellWornPrice:      models.Money(80),
+		BattleScarredPrice: models.Money(50),
+		IsActive:           true,
+	}
+	caseItem := models.Case{
+		Slug:     "concurrency-case-" + suffix,
+		Name:     "Concurrency Case " + suffix,
+		Price:    price,
+		IsActive: true,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&skin).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&caseItem).Error; err != nil {
+			return err
+		}
+		_, err := database.PublishDropTable(tx, caseItem.ID, []models.CaseContent{{SkinID: skin.ID, DropChance: 1}}, "test", nil)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to create case: %v", err)
+	}
+	return caseItem, skin
+}
+
+// testRouter serves one route as the given user, behind the idempotency middleware like in serve.go
+func testRouter(userID uuid.UUID, method, path string, handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Handle(method, path, func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, middleware.Idempotency(), handler)
+	return router
+}
+
+// fireParallel sends the same request parallelRequests times at once and returns the status codes and bodies
+func fireParallel(router *gin.Engine, method, path string, headers map[string]string) ([]int, [][]byte) {
+	statuses := make([]int, parallelRequests)
+	bodies := make([][]byte, parallelRequests)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < parallelRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			statuses[i], bodies[i] = sendRequest(router, method, path, headers)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return statuses, bodies
+}
+
+func sendRequest(router *gin.Engine, method, path string, headers map[string]string) (int, []byte) {
+	req := httptest.NewRequest(method, path, bytes.NewReader(nil))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+func countStatus(statuses []int, status int) int {
+	count := 0
+	for _, s := range statuses {
+		if s == status {
+			count++
+		}
+	}
+	return count
+}
+
+// assertBalance checks the stored balance and that the ledger wallet agrees with it
+func assertBalance(t *testing.T, userID uuid.UUID, want models.Money) {
+	t.Helper()
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+	if user.Casebucks != want {
+		t.Errorf("balance = %s, want %s", user.Casebucks, want)
+	}
+
+	var ledgerBalance models.Money
+	err := database.DB.Model(&models.LedgerEntry{}).
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_accounts.user_id = ?", userID).
+		Select("COALESCE(SUM(ledger_entries.amount), 0)::bigint").
+		Scan(&ledgerBalance).Error
+	if err != nil {
+		t.Fatalf("failed to sum ledger: %v", err)
+	}
+	if ledgerBalance != want {
+		t.Errorf("ledger balance = %s, want %s", ledgerBalance, want)
+	}
+}
+
+func countTransactions(t *testing.T, userID uuid.UUID, transactionType models.TransactionType) int64 {
+	t.Helper()
+	var count int64
+	if err := database.DB.Model(&models.Transaction{}).Where("user_id = ? AND type = ?", userID, transactionType).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count transactions: %v", err)
+	}
+	return count
+}
+
+func TestBuyCaseConcurrentNoDoubleSpend(t *testing.T) {
+	requireTestDB(t)
+	const affordable = 5
+	price := models.Money(250)
+	caseItem, _ := createTestCase(t, price)
+	user := createTestUser(t, price*affordable)
+
+	path := fmt.Sprintf("/cases/%s/buy", caseItem.ID)
+	router := testRouter(user.ID, http.MethodPost, "/cases/:id/buy", BuyCase(50))
+	statuses, _ := fireParallel(router, http.MethodPost, path, nil)
+
+	if got := countStatus(statuses, http.StatusOK); got != affordable {
+		t.Errorf("%d purchases succeeded, want %d (statuses %v)", got, affordable, statuses)
+	}
+	if got := countStatus(statuses, http.StatusBadRequest); got != parallelRequests-affordable {
+		t.Errorf("%d purchases were refused, want %d (statuses %v)", got, parallelRequests-affordable, statuses)
+	}
+	assertBalance(t, user.ID, 0)
+
+	var userCases int64
+	database.DB.Model(&models.UserCase{}).Where("user_id = ?", user.ID).Count(&userCases)
+	if userCases != affordable {
+		t.Errorf("%d cases in inventory, want %d", userCases, affordable)
+	}
+	if got := countTransactions(t, user.ID, models.TransactionTypeCaseBuy); got != affordable {
+		t.Errorf("%d purchase transactions, want %d", got, affordable)
+	}
+}
+
+func TestOpenCaseConcurrentNoDoubleSpend(t *testing.T) {
+	requireTestDB(t)
+	const affordable = 3
+	price := models.Money(400)
+	caseItem, _ := createTestCase(t, price)
+	user := createTestUser(t, price*affordable)
+
+	path := fmt.Sprintf("/cases/%s/open", caseItem.ID)
+	router := testRouter(user.ID, http.MethodPost, "/cases/:id/open", OpenCase)
+	statuses, _ := fireParallel(router, http.MethodPost, path, nil)
+
+	if got := countStatus(statuses, http.StatusOK); got != affordable {
+		t.Errorf("%d openings succeeded, want %d (statuses %v)", got, affordable, statuses)
+	}
+	assertBalance(t, user.ID, 0)
+
+	var items int64
+	database.DB.Model(&models.Inventory{}).Where("user_id = ?", user.ID).Count(&items)
+	if items != affordable {
+		t.Errorf("%d items dropped, want %d", items, affordable)
+	}
+
+	// every opening must have consumed its own nonce
+	var nonces []int64
+	database.DB.Model(&models.CaseOpening{}).Where("user_id = ?", user.ID).Order("nonce").Pluck("nonce", &nonces)
+	for i, nonce := range nonces {
+		if nonce != int64(i+1) {
+			t.Fatalf("nonces = %v, want 1..%d", nonces, affordable)
+		}
+	}
+}
+
+func TestSellItemConcurrentSellsOnce(t *testing.T) {
+	requireTestDB(t)
+	_, skin := createTestCase(t, models.Money(100))
+	user := createTestUser(t, 0)
+
+	item := models.Inventory{
+		UserID:       user.ID,
+		SkinID:       skin.ID,
+		Float:        0.2,
+		AcquiredFrom: "Test",
+		Value:        models.Money(1234),
+	}
+	if err := database.DB.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+
+	path := fmt.Sprintf("/inventory/%s/sell", item.ID)
+	router := testRouter(user.ID, http.MethodPost, "/inventory/:id/sell", SellInventoryItem)
+	statuses, _ := fireParallel(router, http.MethodPost, path, nil)
+
+	if got := countStatus(statuses, http.StatusOK); got != 1 {
+		t.Errorf("%d sales succeeded, want 1 (statuses %v)", got, statuses)
+	}
+	if got := countStatus(statuses, http.StatusNotFound); got != parallelRequests-1 {
+		t.Errorf("%d sales were refused, want %d (statuses %v)", got, parallelRequests-1, statuses)
+	}
+	assertBalance(t, user.ID, item.Value)
+	if got := countTransactions(t, user.ID, models.TransactionTypeSkinSale); got != 1 {
+		t.Errorf("%d sale transactions, want 1", got)
+	}
+
+	var sold models.Inventory
+	database.DB.First(&sold, "id = ?", item.ID)
+	if !sold.IsSold || sold.SoldAt == nil {
+		t.Errorf("item is_sold = %v, sold_at = %v, want sold", sold.IsSold, sold.SoldAt)
+	}
+}
+
+func TestIdempotencyKeyReplaysStoredResponse(t *testing.T) {
+	requireTestDB(t)
+	price := models.Money(300)
+	caseItem, _ := createTestCase(t, price)
+	user := createTestUser(t, price*10)
+
+	path := fmt.Sprintf("/cases/%s/buy", caseItem.ID)
+	router := testRouter(user.ID, http.MethodPost, "/cases/:id/buy", BuyCase(50))
+	headers := map[string]string{middleware.IdempotencyKeyHeader: "buy-" + uuid.NewString()}
+
+	status, first := sendRequest(router, http.MethodPost, path, headers)
+	if status != http.StatusOK {
+		t.Fatalf("first purchase status = %d, body %s", status, first)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("replay status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if recorder.Header().Get(middleware.IdempotentReplayHeader) != "true" {
+		t.Errorf("replay is missing the %s header", middleware.IdempotentReplayHeader)
+	}
+	if !bytes.Equal(recorder.Body.Bytes(), first) {
+		t.Errorf("replayed body differs from the stored response:\n%s\n%s", recorder.Body.Bytes(), first)
+	}
+
+	assertBalance(t, user.ID, price*9)
+	if got := countTransactions(t, user.ID, models.TransactionTypeCaseBuy); got != 1 {
+		t.Errorf("%d purchase transactions, want 1", got)
+	}
+}
+
+func TestIdempotencyKeyConcurrentRetriesApplyOnce(t *testing.T) {
+	requireTestDB(t)
+	price := models.Money(300)
+	caseItem, _ := createTestCase(t, price)
+	user := createTestUser(t, price*10)
+
+	path := fmt.Sprintf("/cases/%s/buy", caseItem.ID)
+	router := testRouter(user.ID, http.MethodPost, "/cases/:id/buy", BuyCase(50))
+	headers := map[string]string{middleware.IdempotencyKeyHeader: "buy-" + uuid.NewString()}
+	statuses, bodies := fireParallel(router, http.MethodPost, path, headers)
+
+	// one request does the purchase; the rest either see it in progress or get its stored response
+	var transactionIDs = map[string]bool{}
+	for i, status := range statuses {
+		switch status {
+		case http.StatusOK:
+			var response struct {
+				TransactionID string `json:"transaction_id"`
+			}
+			if err := json.Unmarshal(bodies[i], &response); err != nil {
+				t.Fatalf("invalid response %s: %v", bodies[i], err)
+			}
+			transactionIDs[response.TransactionID] = true
+		case http.StatusConflict:
+		default:
+			t.Errorf("unexpected status %d: %s", status, bodies[i])
+		}
+	}
+	if len(transactionIDs) != 1 {
+		t.Errorf("responses carry %d different transactions, want 1", len(transactionIDs))
+	}
+
+	assertBalance(t, user.ID, price*9)
+	if got := countTransactions(t, user.ID, models.TransactionTypeCaseBuy); got != 1 {
+		t.Errorf("%d purchase transactions, want 1", got)
+	}
+}
+
+func TestIdempotencyKeyReleasedAfterPanic(t *testing.T) {
+	requireTestDB(t)
+	user := createTestUser(t, 0)
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	calls := 0
+	router.POST("/panic", func(c *gin.Context) {
+		c.Set("userID", user.ID)
+	}, middleware.Idempotency(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+	headers := map[string]string{middleware.IdempotencyKeyHeader: "panic-" + uuid.NewString()}
+
+	if status, _ := sendRequest(router, http.MethodPost, "/panic", headers); status != http.StatusInternalServerError {
+		t.Fatalf("panicking request status = %d, want %d", status, http.StatusInternalServerError)
+	}
+	if status, body := sendRequest(router, http.MethodPost, "/panic", headers); status != http.StatusOK {
+		t.Fatalf("retry status = %d, want %d (%s)", status, http.StatusOK, body)
+	}
+}
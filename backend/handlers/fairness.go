@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/fairness"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RotateSeedRequest represents the payload for rotating the user's seed pair
+type RotateSeedRequest struct {
+	ClientSeed string `json:"client_seed" binding:"omitempty,max=64"`
+}
+
+// UpdateClientSeedRequest represents the payload for setting a new client seed
+type UpdateClientSeedRequest struct {
+	ClientSeed string `json:"client_seed" binding:"required,max=64"`
+}
+
+// VerifyRollRequest represents the payload for verifying arbitrary fairness inputs
+type VerifyRollRequest struct {
+	ServerSeed string     `json:"server_seed" binding:"required"`
+	ClientSeed string     `json:"client_seed" binding:"required"`
+	Nonce      int64      `json:"nonce" binding:"required,min=1"`
+	CaseID     *uuid.UUID `json:"case_id"`
+}
+
+// GetFairnessSeeds returns the user's active seed pair and recently revealed seeds
+func GetFairnessSeeds(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var active *models.FairnessSeed
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		active, err = lockActiveFairnessSeed(tx, userID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load fairness seed"})
+		return
+	}
+
+	var revealed []models.FairnessSeed
+	if err := database.DB.
+		Where("user_id = ? AND revealed_at IS NOT NULL", userID).
+		Order("revealed_at DESC").
+		Limit(10).
+		Find(&revealed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revealed seeds"})
+		return
+	}
+
+	revealedJSON := make([]map[string]interface{}, 0, len(revealed))
+	for _, seed := range revealed {
+		revealedJSON = append(revealedJSON, seed.ToJSON())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active":    active.ToJSON(),
+		"revealed":  revealedJSON,
+		"algorithm": fairness.Algorithm,
+	})
+}
+
+// RotateServerSeed reveals the current server seed and starts a new seed pair
+func RotateServerSeed(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// the body is optional; an empty one keeps the current client seed
+	var req RotateSeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	respondWithRotatedSeed(c, userID, req.ClientSeed)
+}
+
+// UpdateClientSeed sets a new client seed.
+// The server seed is rotated at the same time so a nonce is never replayed for a client seed.
+func UpdateClientSeed(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req UpdateClientSeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	respondWithRotatedSeed(c, userID, req.ClientSeed)
+}
+
+// respondWithRotatedSeed rotates the user's seed pair and writes the result
+func respondWithRotatedSeed(c *gin.Context, userID uuid.UUID, clientSeed string) {
+	var previous, next *models.FairnessSeed
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		previous, err = lockActiveFairnessSeed(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		previous.RevealedAt = &now
+		if err := tx.Model(previous).Update("revealed_at", now).Error; err != nil {
+			return err
+		}
+
+		next, err = createFairnessSeed(tx, userID, clientSeed)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate seed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Seed rotated successfully",
+		"revealed": previous.ToJSON(),
+		"active":   next.ToJSON(),
+	})
+}
+
+// VerifyOpening recomputes a past opening from its stored inputs.
+// This is public so anyone can audit an opening once its server seed is revealed.
+func VerifyOpening(c *gin.Context) {
+	openingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid opening ID"})
+		return
+	}
+
+	var opening models.CaseOpening
+	if err := database.DB.Preload("FairnessSeed").First(&opening, "id = ?", openingID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Opening not found"})
+		return
+	}
+
+	seed := opening.FairnessSeed
+	if !seed.IsRevealed() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":            "Server seed has not been revealed yet. Rotate your seed to verify this opening.",
+			"opening":          opening.ToJSON(),
+			"server_seed_hash": seed.ServerSeedHash,
+		})
+		return
+	}
+
+	skinRoll := fairness.Roll(seed.ServerSeed, opening.ClientSeed, opening.Nonce, fairness.CursorSkin)
+	floatRoll := fairness.Roll(seed.ServerSeed, opening.ClientSeed, opening.Nonce, fairness.CursorFloat)
+	statTrakRoll := fairness.Roll(seed.ServerSeed, opening.ClientSeed, opening.Nonce, fairness.CursorStatTrak)
+	patternRoll := fairness.Roll(seed.ServerSeed, opening.ClientSeed, opening.Nonce, fairness.CursorPattern)
+
+	// recompute against the drop table version that was active when the case was opened
+	var contents []models.CaseContent
+	if opening.DropTableID != nil {
+		contents, err = loadDropTableContents(database.DB, *opening.DropTableID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch case contents"})
+			return
+		}
+	}
+
+	var recomputedSkinID *uuid.UUID
+	if selected := selectSkin(contents, skinRoll); selected != nil {
+		recomputedSkinID = &selected.SkinID
+	}
+
+	hashMatches := fairness.HashServerSeed(seed.ServerSeed) == opening.ServerSeedHash
+	rollsMatch := skinRoll == opening.SkinRoll && floatRoll == opening.FloatRoll
+	skinMatches := recomputedSkinID != nil && *recomputedSkinID == opening.SkinID
+	recomputedFloat := generateFloat(floatRoll, opening.FloatMethod, opening.MinFloat, opening.MaxFloat)
+	floatMatches := recomputedFloat == opening.Float
+
+	// openings made before StatTrak drops never rolled the StatTrak cursor
+	recomputedStatTrak := false
+	statTrakMatches := !opening.IsStatTrak
+	if opening.StatTrakRoll != nil {
+		recomputedStatTrak = isStatTrak(statTrakRoll, opening.StatTrakChance)
+		rollsMatch = rollsMatch && statTrakRoll == *opening.StatTrakRoll
+		statTrakMatches = recomputedStatTrak == opening.IsStatTrak
+	}
+
+	// likewise pattern seeds only exist on openings made after they were introduced
+	var recomputedPatternSeed *int
+	patternMatches := opening.PatternSeed == nil
+	if opening.PatternRoll != nil && opening.PatternSeed != nil {
+		patternSeed := patternSeedFromRoll(patternRoll)
+		recomputedPatternSeed = &patternSeed
+		rollsMatch = rollsMatch && patternRoll == *opening.PatternRoll
+		patternMatches = patternSeed == *opening.PatternSeed
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"opening":     opening.ToJSON(),
+		"server_seed": seed.ServerSeed,
+		"algorithm":   fairness.Algorithm,
+		"recomputed": gin.H{
+			"skin_roll":    skinRoll,
+			"float_roll":   floatRoll,
+			"skin_id":      recomputedSkinID,
+			"float":        recomputedFloat,
+			"stattrak":     recomputedStatTrak,
+			"pattern_seed": recomputedPatternSeed,
+		},
+		"hash_matches":     hashMatches,
+		"rolls_match":      rollsMatch,
+		"skin_matches":     skinMatches,
+		"float_matches":    floatMatches,
+		"stattrak_matches": statTrakMatches,
+		"pattern_matches":  patternMatches,
+		"verified":         hashMatches && rollsMatch && skinMatches && floatMatches && statTrakMatches && patternMatches,
+	})
+}
+
+// VerifyRoll computes the rolls (and optionally the drop) for arbitrary fairness inputs
+func VerifyRoll(c *gin.Context) {
+	var req VerifyRollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	skinRoll := fairness.Roll(req.ServerSeed, req.ClientSeed, req.Nonce, fairness.CursorSkin)
+	floatRoll := fairness.Roll(req.ServerSeed, req.ClientSeed, req.Nonce, fairness.CursorFloat)
+	statTrakRoll := fairness.Roll(req.ServerSeed, req.ClientSeed, req.Nonce, fairness.CursorStatTrak)
+	patternRoll := fairness.Roll(req.ServerSeed, req.ClientSeed, req.Nonce, fairness.CursorPattern)
+
+	response := gin.H{
+		"server_seed_hash": fairness.HashServerSeed(req.ServerSeed),
+		"client_seed":      req.ClientSeed,
+		"nonce":            req.Nonce,
+		"skin_roll":        skinRoll,
+		"float_roll":       floatRoll,
+		"wear":             models.WearFromRoll(floatRoll),
+		"stattrak_roll":    statTrakRoll,
+		"pattern_roll":     patternRoll,
+		"pattern_seed":     patternSeedFromRoll(patternRoll),
+		"algorithm":        fairness.Algorithm,
+	}
+
+	// resolve the roll against a case's drop table when one is given
+	if req.CaseID != nil {
+		var caseItem models.Case
+		if err := database.DB.First(&caseItem, "id = ?", *req.CaseID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+			return
+		}
+
+		_, contents, err := loadActiveDropTable(database.DB, *req.CaseID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch case contents"})
+			return
+		}
+		if selected := selectSkin(contents, skinRoll); selected != nil {
+			skin := selected.Skin
+			skinFloat := generateFloat(floatRoll, fairness.FloatMethodWearBuckets, skin.MinFloat, skin.MaxFloat)
+			response["skin"] = skin.ToJSON()
+			response["float"] = skinFloat
+			response["condition"] = models.ConditionForFloat(skinFloat)
+			response["stattrak"] = isStatTrak(statTrakRoll, effectiveStatTrakChance(caseItem))
+			response["souvenir"] = caseItem.IsSouvenirPackage
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRotateServerSeedRejectsInvalidBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/fairness/rotate", func(c *gin.Context) {
+		c.Set("userID", uuid.New())
+	}, RotateServerSeed)
+
+	for _, body := range []string{`{"client_seed": `, `{"client_seed": "` + strings.Repeat("x", 65) + `"}`} {
+		// a chunked body has no Content-Length, which must not skip the validation
+		req := httptest.NewRequest(http.MethodPost, "/fairness/rotate", io.NopCloser(strings.NewReader(body)))
+		req.ContentLength = -1
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("body %q: status %d, want 400", body, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/imageproxy"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// imageWidths are the resized variants ServeImage offers: 256 for cards, 512 for detail views
+var imageWidths = []int{128, 256, 512}
+
+// caseImageColor tints case placeholders, which have no rarity
+const caseImageColor = "#B0C3D9"
+
+// Cache lifetimes: the /images URL stays the same when an item's image URL is fixed, so stored
+// images are only cached for an hour and then revalidated against the ETag (which changes with
+// the image URL). Placeholders are kept briefly so a recovered upstream shows up soon.
+const (
+	imageCacheControl       = "public, max-age=3600"
+	placeholderCacheControl = "public, max-age=300"
+)
+
+// ServeImage serves a skin or case image through the image proxy at /images/:kind/:id?w=256.
+// Missing or unreachable upstream images are replaced by a rarity-colored placeholder.
+func ServeImage(proxy *imageproxy.Proxy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind := c.Param("kind")
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+
+		width := 0
+		if raw := c.Query("w"); raw != "" {
+			width, err = strconv.Atoi(raw)
+			if err != nil || !isImageWidth(width) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "w must be one of 128, 256 or 512"})
+				return
+			}
+		}
+
+		var sourceURL, color string
+		switch kind {
+		case "skins":
+			var skin models.Skin
+			err = database.DB.Select("id", "image_url", "rarity").First(&skin, "id = ?", id).Error
+			sourceURL, color = skin.ImageURL, skin.GetRarityColor()
+		case "cases":
+			var caseItem models.Case
+			err = database.DB.Select("id", "image_url").First(&caseItem, "id = ?", id).Error
+			sourceURL, color = caseItem.ImageURL, caseImageColor
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown image kind"})
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load image"})
+			return
+		}
+
+		image, err := proxy.Get(kind, id.String(), sourceURL, width)
+		if err != nil {
+			if !errors.Is(err, imageproxy.ErrNoSource) {
+				log.Printf("⚠️  Serving placeholder for %s %s: %v", kind, id, err)
+			}
+			image, err = proxy.Placeholder(color, width)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render image"})
+				return
+			}
+		}
+
+		if image.Placeholder {
+			c.Header("Cache-Control", placeholderCacheControl)
+		} else {
+			c.Header("Cache-Control", imageCacheControl)
+		}
+		c.Header("ETag", image.ETag)
+		if c.GetHeader("If-None-Match") == image.ETag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, image.ContentType, image.Data)
+	}
+}
+
+// isImageWidth checks if the width is one of the offered variants
+func isImageWidth(width int) bool {
+	for _, allowed := range imageWidths {
+		if width == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
 	"github.com/TyronOdame/CS-OPN/backend/middleware"
 	"github.com/TyronOdame/CS-OPN/backend/models"
 	"github.com/gin-gonic/gin"
@@ -42,7 +43,7 @@ func GetUserInventory(c *gin.Context) {
 	}
 
 	// calculate statistics
-	var totalValue float64
+	var totalValue models.Money
 	var itemCount int
 	stats := make(map[string]int)
 
@@ -104,22 +105,22 @@ func SellInventoryItem(c *gin.Context) {
 
 	}()
 
-	// Fetch item (ensure it belongs to user and isn't already sold)
-	var item models.Inventory
-	if err := tx.Preload("Skin").Where("id = ? AND user_id = ? AND is_sold = ?", parsedItemID, userID, false ).First(&item).Error; err != nil {
+	// Get user to update balance (locked first, like every balance-mutating handler)
+	var user models.User
+	if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Item not found or already sold",
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch user",
 		})
 		return
 	}
 
-	// Get user to update balance
-	var user models.User
-	if err := tx.First(&user, "id = ?", userID).Error; err != nil {
+	// Fetch and lock item (ensure it belongs to user and isn't already sold)
+	var item models.Inventory
+	if err := database.ForUpdate(tx).Preload("Skin").Where("id = ? AND user_id = ? AND is_sold = ?", parsedItemID, userID, false ).First(&item).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch user",
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Item not found or already sold",
 		})
 		return
 	}
@@ -136,32 +137,19 @@ func SellInventoryItem(c *gin.Context) {
 		return
 	}
 
-	// Add Case Bucks to user balance 
-	balanceBefore := user.Casebucks
-	user.Casebucks += item.Value
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update user balance",
-		})
-		return
-	}
-
-	// Create transaction record 
-	transaction := models.Transaction{
-		UserID:        user.ID,
-		Type:          models.TransactionTypeSkinSale,
-		Amount:        item.Value,
-		BalanceBefore: balanceBefore,
-		BalanceAfter:  user.Casebucks,
-		Description:   "Sold " + item.Skin.Name,
-		ReferenceID:   &parsedItemID,
-
-	}
-	if err := tx.Create(&transaction).Error; err != nil {
+	// Add Case Bucks to user balance, paid out of the skin buyback account
+	transaction, err := ledger.Post(tx, &user, ledger.Posting{
+		Type:        models.TransactionTypeSkinSale,
+		Amount:      item.Value,
+		Counterpart: ledger.AccountSkinBuyback,
+		Description: "Sold " + item.DisplayName(),
+		ReferenceID: &parsedItemID,
+		PatternTier: item.PatternTier,
+	})
+	if err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create transaction",
+			"error": "Failed to record transaction",
 		})
 		return
 	}
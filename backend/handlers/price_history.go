@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// priceHistoryRange is a supported ?range= value and the bucket size its series is averaged into
+type priceHistoryRange struct {
+	span   time.Duration
+	bucket time.Duration
+}
+
+var priceHistoryRanges = map[string]priceHistoryRange{
+	"24h": {span: 24 * time.Hour, bucket: time.Hour},
+	"7d":  {span: 7 * 24 * time.Hour, bucket: 6 * time.Hour},
+	"30d": {span: 30 * 24 * time.Hour, bucket: 24 * time.Hour},
+	"90d": {span: 90 * 24 * time.Hour, bucket: 24 * time.Hour},
+	"1y":  {span: 365 * 24 * time.Hour, bucket: 7 * 24 * time.Hour},
+}
+
+// PriceHistoryPoint is one time bucket of a price series
+type PriceHistoryPoint struct {
+	Time    time.Time    `json:"time"`
+	Average models.Money `json:"average"`
+	Min     models.Money `json:"min"`
+	Max     models.Money `json:"max"`
+	Samples int          `json:"samples"`
+}
+
+// PriceHistorySeries is the price history of one wear condition
+type PriceHistorySeries struct {
+	Condition string              `json:"condition"`
+	Points    []PriceHistoryPoint `json:"points"`
+}
+
+// GetSkinPriceHistory returns a skin's recorded market prices per wear condition,
+// averaged into time buckets: /skins/:id/price-history?range=30d[&condition=Field-Tested].
+// Only points from the given provider in the given currency are used, so switching either
+// never mixes made-up and real prices or two currencies in one chart.
+func GetSkinPriceHistory(provider, currency string) gin.HandlerFunc {
+	currency = strings.ToUpper(currency)
+	return func(c *gin.Context) {
+		skinID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skin ID"})
+			return
+		}
+
+		rangeName := c.DefaultQuery("range", "30d")
+		window, ok := priceHistoryRanges[rangeName]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "range must be one of 24h, 7d, 30d, 90d or 1y"})
+			return
+		}
+		condition := c.Query("condition")
+		if condition != "" && !models.IsValidCondition(condition) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid condition"})
+			return
+		}
+
+		var skin models.Skin
+		if err := database.DB.Select("id").First(&skin, "id = ?", skinID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Skin not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load skin"})
+			return
+		}
+
+		to := time.Now()
+		from := to.Add(-window.span)
+		query := database.DB.
+			Where("skin_id = ? AND provider = ? AND currency = ? AND recorded_at >= ?", skinID, provider, currency, from).
+			Order("recorded_at ASC")
+		if condition != "" {
+			query = query.Where("condition = ?", condition)
+		}
+		var points []models.SkinPricePoint
+		if err := query.Find(&points).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load price history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"skin_id":  skinID,
+			"range":    rangeName,
+			"bucket":   window.bucket.String(),
+			"from":     from,
+			"to":       to,
+			"provider": provider,
+			"currency": currency,
+			"series":   bucketPriceHistory(points, window.bucket),
+		})
+	}
+}
+
+// bucketPriceHistory groups price points by condition (best wear first) and averages
+// each condition's points into buckets aligned to multiples of the bucket size
+func bucketPriceHistory(points []models.SkinPricePoint, bucket time.Duration) []PriceHistorySeries {
+	type totals struct {
+		sum      models.Money
+		min, max models.Money
+		samples  int
+	}
+	byCondition := map[string]map[time.Time]*totals{}
+	for _, point := range points {
+		start := point.RecordedAt.UTC().Truncate(bucket)
+		if byCondition[point.Condition] == nil {
+			byCondition[point.Condition] = map[time.Time]*totals{}
+		}
+		entry := byCondition[point.Condition][start]
+		if entry == nil {
+			entry = &totals{min: point.Price, max: point.Price}
+			byCondition[point.Condition][start] = entry
+		}
+		entry.sum += point.Price
+		entry.min = min(entry.min, point.Price)
+		entry.max = max(entry.max, point.Price)
+		entry.samples++
+	}
+
+	series := []PriceHistorySeries{}
+	for _, condition := range models.Conditions {
+		buckets, ok := byCondition[condition]
+		if !ok {
+			continue
+		}
+		conditionSeries := PriceHistorySeries{Condition: condition, Points: []PriceHistoryPoint{}}
+		for start, entry := range buckets {
+			conditionSeries.Points = append(conditionSeries.Points, PriceHistoryPoint{
+				Time:    start,
+				Average: entry.sum / models.Money(entry.samples),
+				Min:     entry.min,
+				Max:     entry.max,
+				Samples: entry.samples,
+			})
+		}
+		sort.Slice(conditionSeries.Points, func(i, j int) bool {
+			return conditionSeries.Points[i].Time.Before(conditionSeries.Points[j].Time)
+		})
+		series = append(series, conditionSeries)
+	}
+	return series
+}
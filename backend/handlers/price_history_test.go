@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestBucketPriceHistory(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	point := func(condition string, offset time.Duration, price float64) models.SkinPricePoint {
+		return models.SkinPricePoint{Condition: condition, RecordedAt: day.Add(offset), Price: models.MoneyFromFloat(price)}
+	}
+
+	tests := []struct {
+		name   string
+		points []models.SkinPricePoint
+		bucket time.Duration
+		want   []PriceHistorySeries
+	}{
+		{
+			name:   "no points",
+			bucket: time.Hour,
+			want:   []PriceHistorySeries{},
+		},
+		{
+			name: "points in one bucket give min, average and max",
+			points: []models.SkinPricePoint{
+				point(models.ConditionFieldTested, 5*time.Minute, 10),
+				point(models.ConditionFieldTested, 50*time.Minute, 14),
+				point(models.ConditionFieldTested, 30*time.Minute, 12),
+			},
+			bucket: time.Hour,
+			want: []PriceHistorySeries{{Condition: models.ConditionFieldTested, Points: []PriceHistoryPoint{
+				{Time: day, Average: models.MoneyFromFloat(12), Min: models.MoneyFromFloat(10), Max: models.MoneyFromFloat(14), Samples: 3},
+			}}},
+		},
+		{
+			name: "buckets align to multiples of the bucket size and come out in time order",
+			points: []models.SkinPricePoint{
+				point(models.ConditionFieldTested, 13*time.Hour, 20),
+				point(models.ConditionFieldTested, 2*time.Hour, 10),
+				point(models.ConditionFieldTested, 7*time.Hour, 15),
+			},
+			bucket: 6 * time.Hour,
+			want: []PriceHistorySeries{{Condition: models.ConditionFieldTested, Points: []PriceHistoryPoint{
+				{Time: day, Average: models.MoneyFromFloat(10), Min: models.MoneyFromFloat(10), Max: models.MoneyFromFloat(10), Samples: 1},
+				{Time: day.Add(6 * time.Hour), Average: models.MoneyFromFloat(15), Min: models.MoneyFromFloat(15), Max: models.MoneyFromFloat(15), Samples: 1},
+				{Time: day.Add(12 * time.Hour), Average: models.MoneyFromFloat(20), Min: models.MoneyFromFloat(20), Max: models.MoneyFromFloat(20), Samples: 1},
+			}}},
+		},
+		{
+			name: "conditions are listed best wear first",
+			points: []models.SkinPricePoint{
+				point(models.ConditionBattleScarred, 0, 5),
+				point(models.ConditionFactoryNew, 0, 50),
+				point(models.ConditionWellWorn, 0, 8),
+			},
+			bucket: time.Hour,
+			want: []PriceHistorySeries{
+				{Condition: models.ConditionFactoryNew, Points: []PriceHistoryPoint{
+					{Time: day, Average: models.MoneyFromFloat(50), Min: models.MoneyFromFloat(50), Max: models.MoneyFromFloat(50), Samples: 1},
+				}},
+				{Condition: models.ConditionWellWorn, Points: []PriceHistoryPoint{
+					{Time: day, Average: models.MoneyFromFloat(8), Min: models.MoneyFromFloat(8), Max: models.MoneyFromFloat(8), Samples: 1},
+				}},
+				{Condition: models.ConditionBattleScarred, Points: []PriceHistoryPoint{
+					{Time: day, Average: models.MoneyFromFloat(5), Min: models.MoneyFromFloat(5), Max: models.MoneyFromFloat(5), Samples: 1},
+				}},
+			},
+		},
+		{
+			name: "non-UTC timestamps land in UTC buckets",
+			points: []models.SkinPricePoint{
+				{Condition: models.ConditionMinimalWear, RecordedAt: day.Add(90 * time.Minute).In(time.FixedZone("UTC+5:30", 5*3600+1800)), Price: models.MoneyFromFloat(3)},
+			},
+			bucket: time.Hour,
+			want: []PriceHistorySeries{{Condition: models.ConditionMinimalWear, Points: []PriceHistoryPoint{
+				{Time: day.Add(time.Hour), Average: models.MoneyFromFloat(3), Min: models.MoneyFromFloat(3), Max: models.MoneyFromFloat(3), Samples: 1},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bucketPriceHistory(tt.points, tt.bucket)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d series, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i].Condition != tt.want[i].Condition || len(got[i].Points) != len(tt.want[i].Points) {
+					t.Fatalf("series %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+				for j, want := range tt.want[i].Points {
+					gotPoint := got[i].Points[j]
+					if !gotPoint.Time.Equal(want.Time) || gotPoint.Average != want.Average || gotPoint.Min != want.Min ||
+						gotPoint.Max != want.Max || gotPoint.Samples != want.Samples {
+						t.Errorf("%s point %d = %+v, want %+v", tt.want[i].Condition, j, gotPoint, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// recordTestPrices stores Field-Tested price points of a skin from several providers and currencies
+func recordTestPrices(t *testing.T, skinID uuid.UUID) {
+	t.Helper()
+	now := time.Now()
+	points := []models.SkinPricePoint{}
+	for _, source := range []struct {
+		provider, currency string
+		price              float64
+	}{
+		{pricing.DriverSkinport, "USD", 20},
+		{pricing.DriverSkinport, "USD", 22},
+		{pricing.DriverSkinport, "EUR", 50},
+		{pricing.DriverMock, "USD", 99},
+	} {
+		points = append(points, models.SkinPricePoint{
+			SkinID:         skinID,
+			Condition:      models.ConditionFieldTested,
+			MarketHashName: "test",
+			Price:          models.MoneyFromFloat(source.price),
+			Currency:       source.currency,
+			Provider:       source.provider,
+			RecordedAt:     now.Add(-time.Minute),
+		})
+	}
+	if err := database.DB.Create(&points).Error; err != nil {
+		t.Fatalf("failed to record prices: %v", err)
+	}
+}
+
+func TestGetSkinPriceHistoryFiltersProviderAndCurrency(t *testing.T) {
+	requireTestDB(t)
+	_, skin := createTestCase(t, models.Money(100))
+	recordTestPrices(t, skin.ID)
+
+	router := gin.New()
+	router.GET("/skins/:id/price-history", GetSkinPriceHistory(pricing.DriverSkinport, "usd"))
+	status, body := sendRequest(router, http.MethodGet, "/skins/"+skin.ID.String()+"/price-history?range=24h", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, body %s", status, body)
+	}
+
+	var response struct {
+		Currency string               `json:"currency"`
+		Series   []PriceHistorySeries `json:"series"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if response.Currency != "USD" || len(response.Series) != 1 {
+		t.Fatalf("response = %+v, want one USD series", response)
+	}
+	samples := 0
+	for _, point := range response.Series[0].Points {
+		samples += point.Samples
+		if point.Max > models.MoneyFromFloat(22) || point.Min < models.MoneyFromFloat(20) {
+			t.Errorf("point %+v includes prices from another provider or currency", point)
+		}
+	}
+	if samples != 2 {
+		t.Errorf("series has %d samples, want the 2 Skinport USD prices", samples)
+	}
+}
+
+func TestRecomputeSkinValuesUsesProviderUSDPrices(t *testing.T) {
+	requireTestDB(t)
+	_, skin := createTestCase(t, models.Money(100))
+	recordTestPrices(t, skin.ID)
+
+	if _, err := pricing.RecomputeSkinValues(database.DB, pricing.DriverSkinport, time.Hour); err != nil {
+		t.Fatalf("RecomputeSkinValues: %v", err)
+	}
+	var repriced models.Skin
+	if err := database.DB.First(&repriced, "id = ?", skin.ID).Error; err != nil {
+		t.Fatalf("failed to reload skin: %v", err)
+	}
+	if got := repriced.PriceForCondition(models.ConditionFieldTested); got != models.MoneyFromFloat(21) {
+		t.Errorf("Field-Tested price = %v, want 21 (median of the Skinport USD prices)", got)
+	}
+	if repriced.FactoryNewPrice != skin.FactoryNewPrice {
+		t.Errorf("Factory New price changed to %v without market data", repriced.FactoryNewPrice)
+	}
+}
@@ -4,35 +4,36 @@ import (
 	"time"
 
 	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
 	"github.com/TyronOdame/CS-OPN/backend/models"
 	"gorm.io/gorm"
 )
 
-const DailyLoginRewardAmount = 100.0
+// DailyLoginRewardAmount is the Case Bucks granted once every 24h on login
+const DailyLoginRewardAmount = 100 * models.CaseBuck
 
-// applyDailyLoginReward grants 100 CB once every 24h on login.
+// applyDailyLoginReward grants 100 CB once every 24h on login, once the user has verified their email.
 func applyDailyLoginReward(tx *gorm.DB, user *models.User) (bool, error) {
+	if !user.IsEmailVerified() {
+		return false, nil
+	}
+
 	now := time.Now()
 	if user.LastDailyRewardAt != nil && now.Sub(*user.LastDailyRewardAt) < 24*time.Hour {
 		return false, nil
 	}
 
-	balanceBefore := user.Casebucks
-	user.Casebucks += DailyLoginRewardAmount
 	user.LastDailyRewardAt = &now
-	if err := tx.Save(user).Error; err != nil {
+	if err := tx.Model(user).Update("last_daily_reward_at", now).Error; err != nil {
 		return false, err
 	}
 
-	transaction := models.Transaction{
-		UserID:        user.ID,
-		Type:          models.TransactionTypeDailyLogin,
-		Amount:        DailyLoginRewardAmount,
-		BalanceBefore: balanceBefore,
-		BalanceAfter:  user.Casebucks,
-		Description:   "Daily login reward",
-	}
-	if err := tx.Create(&transaction).Error; err != nil {
+	if _, err := ledger.Post(tx, user, ledger.Posting{
+		Type:        models.TransactionTypeDailyLogin,
+		Amount:      DailyLoginRewardAmount,
+		Counterpart: ledger.AccountDailyRewards,
+		Description: "Daily login reward",
+	}); err != nil {
 		return false, err
 	}
 
@@ -48,6 +49,12 @@ func ApplyDailyRewardForUser(user *models.User) (bool, error) {
 		}
 	}()
 
+	// re-read the user under lock so two simultaneous logins cannot both claim the reward
+	if err := database.ForUpdate(tx).First(user, "id = ?", user.ID).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	claimed, err := applyDailyLoginReward(tx, user)
 	if err != nil {
 		tx.Rollback()
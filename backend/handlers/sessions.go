@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/mailer"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuthConfig holds the settings auth handlers need to issue tokens and send account emails
+type AuthConfig struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	Mailer          mailer.Mailer
+	AppURL          string // frontend base URL used in emailed links
+
+	AccountDeletionGrace time.Duration // how long a deleted account can be restored
+}
+
+// RefreshRequest represents the expected payload for refreshing tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// issueSession starts a new session for the user and returns the token pair for the response
+func issueSession(c *gin.Context, tx *gorm.DB, user *models.User, cfg AuthConfig) (gin.H, error) {
+	refreshToken, refreshHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: refreshHash,
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        time.Now().Add(cfg.RefreshTokenTTL),
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return tokenPairResponse(user, session.ID, refreshToken, cfg)
+}
+
+// tokenPairResponse signs an access token for the session and bundles it with the refresh token
+func tokenPairResponse(user *models.User, sessionID uuid.UUID, refreshToken string, cfg AuthConfig) (gin.H, error) {
+	accessToken, err := utils.GenerateJWT(user, sessionID, cfg.JWTSecret, cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"token":         accessToken,
+		"refresh_token": refreshToken,
+		"expires_in":    int(cfg.AccessTokenTTL.Seconds()),
+		"session_id":    sessionID,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair, rotating the refresh token.
+// Presenting an already-rotated token revokes the session, since it means the token leaked.
+func Refresh(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RefreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "refresh_token is required",
+			})
+			return
+		}
+
+		presentedHash := utils.HashToken(req.RefreshToken)
+		var response gin.H
+		var user models.User
+		invalid := false
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var session models.Session
+			err := database.ForUpdate(tx).Where("refresh_token_hash = ?", presentedHash).First(&session).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// the revocation has to commit, so this is not returned as an error
+				invalid = true
+				return revokeReusedRefreshToken(tx, presentedHash)
+			}
+			if err != nil {
+				return err
+			}
+			if !session.IsActive() {
+				invalid = true
+				return nil
+			}
+
+			if err := tx.First(&user, "id = ?", session.UserID).Error; err != nil {
+				invalid = true
+				return nil
+			}
+
+			refreshToken, refreshHash, err := utils.GenerateOpaqueToken()
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&session).Updates(map[string]interface{}{
+				"refresh_token_hash":          refreshHash,
+				"previous_refresh_token_hash": presentedHash,
+				"last_used_at":                time.Now(),
+				"ip_address":                  c.ClientIP(),
+				"user_agent":                  c.Request.UserAgent(),
+			}).Error; err != nil {
+				return err
+			}
+
+			response, err = tokenPairResponse(&user, session.ID, refreshToken, cfg)
+			return err
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to refresh token",
+			})
+			return
+		}
+		if invalid {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired refresh token",
+			})
+			return
+		}
+
+		response["message"] = "Token refreshed successfully"
+		response["user"] = user.ToJSON()
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// revokeReusedRefreshToken revokes the session a rotated-out refresh token belonged to,
+// since seeing it again means someone else holds a copy of it
+func revokeReusedRefreshToken(tx *gorm.DB, presentedHash string) error {
+	return revokeSessions(tx.Where("previous_refresh_token_hash = ?", presentedHash), models.SessionRevokedTokenReuse)
+}
+
+// Logout revokes the session the current access token belongs to
+func Logout(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := revokeSessions(database.DB.Where("id = ? AND user_id = ?", middleware.GetSessionID(c), userID), models.SessionRevokedLogout); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// GetSessions lists the user's active sessions with device and IP metadata
+func GetSessions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var sessions []models.Session
+	if err := database.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	currentSessionID := middleware.GetSessionID(c)
+	response := make([]map[string]interface{}, 0, len(sessions))
+	for _, session := range sessions {
+		item := session.ToJSON()
+		item["is_current"] = session.ID == currentSessionID
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": response,
+		"count":    len(response),
+	})
+}
+
+// RevokeSession logs out one of the user's sessions
+func RevokeSession(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	result := database.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": models.SessionRevokedByUser,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RevokeAllSessions logs out every session of the user and invalidates all issued access tokens
+func RevokeAllSessions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return logoutEverywhere(tx, userID, models.SessionRevokedLogoutAll)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out of all sessions",
+	})
+}
+
+// logoutEverywhere revokes all of a user's sessions and bumps their token version
+func logoutEverywhere(tx *gorm.DB, userID uuid.UUID, reason string) error {
+	if err := revokeSessions(tx.Where("user_id = ?", userID), reason); err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// revokeSessions marks the sessions matched by scope as revoked
+func revokeSessions(scope *gorm.DB, reason string) error {
+	return scope.Model(&models.Session{}).
+		Where("revoked_at IS NULL").
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error
+}
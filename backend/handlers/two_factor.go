@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/twofactor"
+	"github.com/TyronOdame/CS-OPN/backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TwoFactorIssuer is the account name authenticator apps show for CS:OPN codes
+const TwoFactorIssuer = "CS:OPN"
+
+// TwoFactorPasswordRequest represents the expected payload for enrolling in or disabling 2FA
+type TwoFactorPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// TwoFactorCodeRequest represents a payload carrying a 2FA code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// VerifyTwoFactorLoginRequest represents the expected payload for the second login step
+type VerifyTwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP code or a recovery code
+}
+
+// EnrollTwoFactor generates a new TOTP secret. 2FA is not enforced until it is confirmed with a code.
+func EnrollTwoFactor(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TwoFactorPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password is required"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if !user.CheckPassword(req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		return
+	}
+	if user.IsTwoFactorEnabled() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := twofactor.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+	// only replace a pending secret, never an enabled one
+	result := database.DB.Model(&models.User{}).
+		Where("id = ? AND two_factor_enabled_at IS NULL", user.ID).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_last_used_step": 0})
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start enrollment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Scan the QR code with your authenticator app, then confirm with a code",
+		"secret":      secret,
+		"otpauth_uri": twofactor.URI(TwoFactorIssuer, user.Email, secret),
+	})
+}
+
+// ConfirmTwoFactor turns on 2FA once the user proves their app produces valid codes.
+// The recovery codes are only ever returned here and by RegenerateRecoveryCodes.
+func ConfirmTwoFactor(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	var codes []string
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if user.IsTwoFactorEnabled() {
+			return errTwoFactorAlreadyEnabled
+		}
+		if user.TOTPSecret == "" {
+			return errTwoFactorNotEnrolled
+		}
+
+		step, ok := twofactor.Validate(user.TOTPSecret, req.Code, time.Now(), user.TOTPLastUsedStep)
+		if !ok {
+			return twofactor.ErrInvalidCode
+		}
+
+		now := time.Now()
+		user.TwoFactorEnabledAt = &now
+		user.TOTPLastUsedStep = step
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled_at": now,
+			"totp_last_used_step":   step,
+		}).Error; err != nil {
+			return err
+		}
+
+		codes, err = twofactor.GenerateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if respondTwoFactorError(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable two-factor authentication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled. Store your recovery codes somewhere safe",
+		"recovery_codes": codes,
+		"user":           user.ToJSON(),
+	})
+}
+
+// DisableTwoFactor turns 2FA off. The route requires a fresh code; the password is checked here.
+func DisableTwoFactor(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TwoFactorPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password is required"})
+		return
+	}
+
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if !user.CheckPassword(req.Password) {
+			return errWrongPassword
+		}
+		if !user.IsTwoFactorEnabled() {
+			return errTwoFactorNotEnrolled
+		}
+
+		user.TOTPSecret = ""
+		user.TwoFactorEnabledAt = nil
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_secret":           "",
+			"two_factor_enabled_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error
+	})
+	if errors.Is(err, errWrongPassword) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		return
+	}
+	if respondTwoFactorError(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable two-factor authentication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+		"user":    user.ToJSON(),
+	})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes. The route requires a fresh code.
+func RegenerateRecoveryCodes(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var codes []string
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if !user.IsTwoFactorEnabled() {
+			return errTwoFactorNotEnrolled
+		}
+		codes, err = twofactor.GenerateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if respondTwoFactorError(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "New recovery codes generated. The old ones no longer work",
+		"recovery_codes": codes,
+	})
+}
+
+// VerifyTwoFactorLogin completes a 2FA login with the challenge token from Login and a code.
+// A challenge is burned after MaxTwoFactorAttempts wrong codes.
+func VerifyTwoFactorLogin(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req VerifyTwoFactorLoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "challenge_token and code are required"})
+			return
+		}
+
+		var user models.User
+		codeRejected := false
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var challenge models.UserToken
+			if err := database.ForUpdate(tx).
+				Where("token_hash = ? AND purpose = ?", utils.HashToken(req.ChallengeToken), models.TokenPurposeTwoFactorLogin).
+				First(&challenge).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errInvalidUserToken
+				}
+				return err
+			}
+			if !challenge.IsUsable() || challenge.Attempts >= models.MaxTwoFactorAttempts {
+				return errInvalidUserToken
+			}
+
+			if err := database.ForUpdate(tx).First(&user, "id = ?", challenge.UserID).Error; err != nil {
+				return errInvalidUserToken
+			}
+
+			err := twofactor.Verify(tx, &user, req.Code)
+			if errors.Is(err, twofactor.ErrInvalidCode) || errors.Is(err, twofactor.ErrCodeRequired) {
+				// the failed attempt has to commit, so this is not returned as an error
+				codeRejected = true
+				updates := map[string]interface{}{"attempts": gorm.Expr("attempts + 1")}
+				if challenge.Attempts+1 >= models.MaxTwoFactorAttempts {
+					updates["used_at"] = time.Now()
+				}
+				return tx.Model(&challenge).Updates(updates).Error
+			}
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&challenge).Update("used_at", time.Now()).Error
+		})
+		if errors.Is(err, errInvalidUserToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Login challenge is invalid or expired. Please log in again"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify code"})
+			return
+		}
+		if codeRejected {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+			return
+		}
+
+		completeLogin(c, &user, cfg)
+	}
+}
+
+var (
+	errTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	errTwoFactorNotEnrolled    = errors.New("two-factor authentication not set up")
+)
+
+// respondTwoFactorError writes the response for 2FA-specific errors and reports whether it did
+func respondTwoFactorError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, twofactor.ErrInvalidCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+	case errors.Is(err, errTwoFactorAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+	case errors.Is(err, errTwoFactorNotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not set up"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		return false
+	}
+	return true
+}
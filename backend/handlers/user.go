@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/TyronOdame/CS-OPN/backend/database"
 	"github.com/TyronOdame/CS-OPN/backend/models"
 	"github.com/TyronOdame/CS-OPN/backend/middleware"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetProfile returns the current user profile
@@ -40,76 +42,98 @@ type UpdateProfileRequest struct {
 }
 
 // updateProfile updates the current user's profile
-func UpdateProfile(c *gin.Context) {
-	// extract user ID from context
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
-		return
-	}
-
-	// parse and validate request body
-	var req UpdateProfileRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request data",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	// Get the user from the database
-	var user models.User
-	if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "User not found",
-		})
-		return
-	}
-
-	//update user fields if provide in the request
-	if req.Username != "" {
-		// check if username is already taken
-		var existingUser models.User
-		if err := database.DB.Where("username = ? AND id != ?", req.Username, userID).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Username already in use",
+func UpdateProfile(cfg AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// extract user ID from context
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
 			})
 			return
 		}
-		user.Username = req.Username
-	}
 
-	if req.Email != "" {
-		// check if email is already taken
-		var existingUser models.User
-		if err := database.DB.Where("email = ? AND id != ?", req.Email, userID).First(&existingUser).Error; err == nil{
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Email already in use",
+		// parse and validate request body
+		var req UpdateProfileRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request data",
+				"details": err.Error(),
 			})
 			return
 		}
-		user.Email = req.Email
-	}
-
-	// save the updated user to the database
-	if err := database.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update profile",
-		})
-		return 
-	}
 
-	// respond with the updated users profile
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
-		"user": user.ToJSON(),
+		// Get the user from the database
+		var user models.User
+		if err := database.DB.First(&user, "id = ?", userID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
 
-	})
-}
+		//update user fields if provide in the request
+		emailChanged := false
+		if req.Username != "" {
+			// check if username is already taken
+			var existingUser models.User
+			if err := database.DB.Where("username = ? AND id != ?", req.Username, userID).First(&existingUser).Error; err == nil || heldByDeletedAccount("original_username", req.Username) {
+				c.JSON(http.StatusConflict, gin.H{
+					"error": "Username already in use",
+				})
+				return
+			}
+			user.Username = req.Username
+		}
 
+		if req.Email != "" {
+			// check if email is already taken
+			var existingUser models.User
+			if err := database.DB.Where("email = ? AND id != ?", req.Email, userID).First(&existingUser).Error; err == nil || heldByDeletedAccount("original_email", req.Email) {
+				c.JSON(http.StatusConflict, gin.H{
+					"error": "Email already in use",
+				})
+				return
+			}
+			// a new address has to be verified again, and changing it needs a fresh 2FA code
+			emailChanged = !strings.EqualFold(user.Email, req.Email)
+			if emailChanged && !middleware.CheckFreshTwoFactor(c) {
+				return
+			}
+			user.Email = req.Email
+			if emailChanged {
+				user.EmailVerifiedAt = nil
+			}
+		}
 
+		// save the updated user to the database, with a verification link for a new address
+		var verificationToken string
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			// only the profile columns, so a stale balance is never written back
+			if err := tx.Model(&user).Select("username", "email", "email_verified_at").Updates(&user).Error; err != nil {
+				return err
+			}
+			if !emailChanged {
+				return nil
+			}
+			verificationToken, err = issueUserToken(tx, &user, models.TokenPurposeEmailVerification, models.EmailVerificationTokenTTL)
+			return err
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to update profile",
+			})
+			return 
+		}
+		if emailChanged {
+			sendVerificationEmail(cfg, &user, verificationToken)
+		}
 
+		// respond with the updated users profile
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Profile updated successfully",
+			"user": user.ToJSON(),
 
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
 	"github.com/TyronOdame/CS-OPN/backend/middleware"
 	"github.com/TyronOdame/CS-OPN/backend/models"
 	"github.com/gin-gonic/gin"
@@ -64,43 +65,25 @@ func OpenPurchasedCase(c *gin.Context) {
 		}
 	}()
 
-	var userCase models.UserCase
-	if err := tx.Preload("Case").Where("id = ? AND user_id = ? AND is_opened = ?", parsedUserCaseID, userID, false).First(&userCase).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchased case not found or already opened"})
-		return
-	}
-
-	var contents []models.CaseContent
-	if err := tx.Preload("Skin").Where("case_id = ?", userCase.CaseID).Find(&contents).Error; err != nil {
+	// lock the user row first, then the purchased case so it can only be opened once
+	var user models.User
+	if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch case contents"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
 		return
 	}
 
-	selectedContent := selectRandomSkin(contents)
-	if selectedContent == nil {
+	var userCase models.UserCase
+	if err := database.ForUpdate(tx).Preload("Case").Where("id = ? AND user_id = ? AND is_opened = ?", parsedUserCaseID, userID, false).First(&userCase).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to select skin"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Purchased case not found or already opened"})
 		return
 	}
 
-	randomFloat := generateFloat()
-	skin := selectedContent.Skin
-	floatMultiplier := 1.0 - randomFloat
-	skinValue := skin.MinValue + (skin.MaxValue-skin.MinValue)*floatMultiplier
-
-	inventory := models.Inventory{
-		UserID:       userID,
-		SkinID:       skin.ID,
-		Float:        randomFloat,
-		AcquiredFrom: userCase.Case.Name,
-		Value:        skinValue,
-		IsSold:       false,
-	}
-	if err := tx.Create(&inventory).Error; err != nil {
+	result, err := openCaseForUser(tx, userID, userCase.Case)
+	if err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add skin to inventory"})
+		c.JSON(openCaseErrorStatus(err), gin.H{"error": openCaseErrorMessage(err)})
 		return
 	}
 
@@ -113,50 +96,30 @@ func OpenPurchasedCase(c *gin.Context) {
 		return
 	}
 
-	transaction := models.Transaction{
-		UserID:        userID,
-		Type:          models.TransactionTypeCaseOpen,
-		Amount:        0,
-		BalanceBefore: 0,
-		BalanceAfter:  0,
-		Description:   "Opened purchased " + userCase.Case.Name,
-		ReferenceID:   &userCase.CaseID,
-	}
-	if err := tx.Create(&transaction).Error; err != nil {
+	// the case was paid for when bought, so this records a zero-amount event
+	transaction, err := ledger.Post(tx, &user, ledger.Posting{
+		Type:        models.TransactionTypeCaseOpen,
+		Amount:      0,
+		Description: "Opened purchased " + userCase.Case.Name,
+		ReferenceID: &userCase.CaseID,
+		PatternTier: result.Inventory.PatternTier,
+	})
+	if err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transaction"})
 		return
 	}
 
-	var user models.User
-	if err := tx.First(&user, "id = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
-		return
-	}
-	transaction.BalanceBefore = user.Casebucks
-	transaction.BalanceAfter = user.Casebucks
-	if err := tx.Save(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transaction"})
-		return
-	}
-
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete case opening"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":        "Case opened successfully!",
-		"case":           userCase.Case.ToJSON(),
-		"skin":           skin.ToJSON(),
-		"float":          randomFloat,
-		"condition":      inventory.GetCondition(),
-		"value":          skinValue,
-		"new_balance":    user.Casebucks,
-		"inventory_id":   inventory.ID,
-		"transaction_id": transaction.ID,
-	})
+	response := result.toJSON()
+	response["message"] = "Case opened successfully!"
+	response["case"] = userCase.Case.ToJSON()
+	response["new_balance"] = user.Casebucks
+	response["transaction_id"] = transaction.ID
+	c.JSON(http.StatusOK, response)
 }
 
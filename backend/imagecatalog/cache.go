@@ -0,0 +1,112 @@
+package imagecatalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps the last successfully fetched catalog body on disk with the validators
+// needed to revalidate it (ETag and Last-Modified)
+type Cache struct {
+	dir string
+}
+
+// cacheMeta is stored next to the cached body
+type cacheMeta struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// cached is a catalog body read back from the cache
+type cached struct {
+	meta   cacheMeta
+	images map[string]string
+}
+
+// NewCache creates a cache in dir; the directory is created on the first write
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+func (c *Cache) bodyPath() string { return filepath.Join(c.dir, "catalog.json") }
+func (c *Cache) metaPath() string { return filepath.Join(c.dir, "catalog.meta.json") }
+
+// load returns the cached catalog for url, or nil when there is none
+func (c *Cache) load(url string) (*cached, error) {
+	metaData, err := os.ReadFile(c.metaPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var meta cacheMeta
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return nil, fmt.Errorf("invalid cache metadata: %w", err)
+	}
+	// a cache filled from another source says nothing about this one
+	if meta.URL != url {
+		return nil, nil
+	}
+
+	body, err := os.ReadFile(c.bodyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	images, err := decodeImages(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cached catalog: %w", err)
+	}
+	return &cached{meta: meta, images: images}, nil
+}
+
+// store replaces the cached body and metadata
+func (c *Cache) store(meta cacheMeta, body []byte) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.bodyPath(), body); err != nil {
+		return err
+	}
+	return writeFileAtomic(c.metaPath(), metaData)
+}
+
+// touch records a successful revalidation of the cached body
+func (c *Cache) touch(meta cacheMeta) error {
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.metaPath(), metaData)
+}
+
+// writeFileAtomic writes through a temporary file so readers never see half a file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,49 @@
+package imagecatalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileProvider reads the catalog from a local JSON file with the same shape as the
+// SteamApis response: an object of market hash names to image URLs (offline use and tests)
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider creates a provider reading path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Fetch reads and decodes the file
+func (p *FileProvider) Fetch() (*Catalog, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image catalog: %w", err)
+	}
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image catalog: %w", err)
+	}
+
+	images, err := decodeImages(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image catalog %s: %w", p.path, err)
+	}
+	return &Catalog{Images: images, Source: p.path, FetchedAt: info.ModTime()}, nil
+}
+
+// decodeImages parses a catalog body and rejects empty catalogs,
+// so a bad response never replaces a good cached copy
+func decodeImages(data []byte) (map[string]string, error) {
+	var images map[string]string
+	if err := json.Unmarshal(data, &images); err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("catalog has no images")
+	}
+	return images, nil
+}
@@ -0,0 +1,125 @@
+package imagecatalog
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout bounds a catalog download when none is configured
+const defaultTimeout = 12 * time.Second
+
+// maxCatalogSize caps how much of a response body is read (the SteamApis catalog is a few MB)
+const maxCatalogSize = 64 << 20
+
+// HTTPProvider downloads the catalog from a URL. With a cache it revalidates the last good
+// copy using If-None-Match/If-Modified-Since and falls back to it when the source fails.
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+	cache  *Cache
+}
+
+// NewHTTPProvider creates a provider for url (SteamApis by default); cacheDir may be empty
+func NewHTTPProvider(url, cacheDir string, timeout time.Duration) *HTTPProvider {
+	if url == "" {
+		url = SteamAPIsURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	provider := &HTTPProvider{url: url, client: &http.Client{Timeout: timeout}}
+	if cacheDir != "" {
+		provider.cache = NewCache(cacheDir)
+	}
+	return provider
+}
+
+// Fetch downloads the catalog, or returns the cached copy when it is still current
+// or the source cannot be reached
+func (p *HTTPProvider) Fetch() (*Catalog, error) {
+	var previous *cached
+	if p.cache != nil {
+		var err error
+		if previous, err = p.cache.load(p.url); err != nil {
+			log.Printf("⚠️  Ignoring image catalog cache: %v", err)
+			previous = nil
+		}
+	}
+
+	catalog, err := p.download(previous)
+	if err == nil {
+		return catalog, nil
+	}
+	if previous == nil {
+		return nil, err
+	}
+
+	log.Printf("⚠️  Image catalog fetch failed, using cached copy from %s: %v",
+		previous.meta.FetchedAt.Format(time.RFC3339), err)
+	return &Catalog{Images: previous.images, Source: p.url, FetchedAt: previous.meta.FetchedAt, Stale: true}, nil
+}
+
+// download performs the (conditional) request and updates the cache
+func (p *HTTPProvider) download(previous *cached) (*Catalog, error) {
+	req, err := http.NewRequest(http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if previous != nil {
+		if previous.meta.ETag != "" {
+			req.Header.Set("If-None-Match", previous.meta.ETag)
+		}
+		if previous.meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", previous.meta.LastModified)
+		}
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	now := time.Now()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && previous != nil:
+		meta := previous.meta
+		meta.FetchedAt = now
+		if err := p.cache.touch(meta); err != nil {
+			log.Printf("⚠️  Failed to update image catalog cache: %v", err)
+		}
+		return &Catalog{Images: previous.images, Source: p.url, FetchedAt: now}, nil
+
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("image catalog returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCatalogSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image catalog: %w", err)
+	}
+	if len(body) > maxCatalogSize {
+		return nil, fmt.Errorf("image catalog is larger than %d bytes", maxCatalogSize)
+	}
+	images, err := decodeImages(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image catalog: %w", err)
+	}
+
+	if p.cache != nil {
+		meta := cacheMeta{
+			URL:          p.url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    now,
+		}
+		if err := p.cache.store(meta, body); err != nil {
+			log.Printf("⚠️  Failed to cache image catalog: %v", err)
+		}
+	}
+	return &Catalog{Images: images, Source: p.url, FetchedAt: now}, nil
+}
@@ -0,0 +1,118 @@
+package imagecatalog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// catalogServer serves a one-image catalog with an ETag and answers matching revalidations with 304
+func catalogServer(t *testing.T, status *atomic.Int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	revalidated := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"Chroma Case": "https://cdn.example/chroma.png"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, revalidated
+}
+
+func TestHTTPProviderCachesAndRevalidates(t *testing.T) {
+	status := &atomic.Int32{}
+	status.Store(http.StatusOK)
+	server, revalidated := catalogServer(t, status)
+	provider := NewHTTPProvider(server.URL, t.TempDir(), 5*time.Second)
+
+	first, err := provider.Fetch()
+	if err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+	if first.Stale || first.Images["Chroma Case"] != "https://cdn.example/chroma.png" {
+		t.Fatalf("first Fetch = %+v, want a fresh catalog with the case image", first)
+	}
+
+	second, err := provider.Fetch()
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	if revalidated.Load() != 1 {
+		t.Fatalf("server saw %d revalidations, want 1", revalidated.Load())
+	}
+	if second.Stale || second.Images["Chroma Case"] == "" {
+		t.Fatalf("revalidated Fetch = %+v, want the cached images, not stale", second)
+	}
+}
+
+func TestHTTPProviderFallsBackToStaleCache(t *testing.T) {
+	status := &atomic.Int32{}
+	status.Store(http.StatusOK)
+	server, _ := catalogServer(t, status)
+	provider := NewHTTPProvider(server.URL, t.TempDir(), 5*time.Second)
+
+	if _, err := provider.Fetch(); err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+
+	status.Store(http.StatusBadGateway)
+	catalog, err := provider.Fetch()
+	if err != nil {
+		t.Fatalf("Fetch with the source down: %v", err)
+	}
+	if !catalog.Stale || catalog.Images["Chroma Case"] == "" {
+		t.Fatalf("Fetch = %+v, want the cached images marked stale", catalog)
+	}
+}
+
+func TestHTTPProviderFailsWithoutCache(t *testing.T) {
+	status := &atomic.Int32{}
+	status.Store(http.StatusInternalServerError)
+	server, _ := catalogServer(t, status)
+
+	if _, err := NewHTTPProvider(server.URL, "", 5*time.Second).Fetch(); err == nil {
+		t.Fatal("Fetch succeeded against a failing source with no cache")
+	}
+}
+
+func TestHTTPProviderRejectsEmptyCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if _, err := NewHTTPProvider(server.URL, dir, 5*time.Second).Fetch(); err == nil {
+		t.Fatal("Fetch accepted an empty catalog")
+	}
+	if cached, err := NewCache(dir).load(server.URL); err != nil || cached != nil {
+		t.Fatalf("empty catalog was cached: %+v, %v", cached, err)
+	}
+}
+
+func TestHTTPProviderRejectsOversizedCatalog(t *testing.T) {
+	// valid JSON padded with whitespace to one byte over the limit, so only the size check rejects it
+	catalog := `{"Chroma Case": "https://cdn.example/chroma.png"}`
+	body := catalog + strings.Repeat(" ", maxCatalogSize+1-len(catalog))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPProvider(server.URL, "", 5*time.Second).Fetch()
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Fatalf("Fetch of an oversized catalog = %v, want a too large error", err)
+	}
+}
@@ -0,0 +1,161 @@
+package imagecatalog
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wearSuffixes are the wear conditions market hash names end with, best first
+var wearSuffixes = []string{"factory new", "minimal wear", "field-tested", "well-worn", "battle-scarred"}
+
+// variantPrefixes mark StatTrak and Souvenir listings, which share the plain skin's image
+var variantPrefixes = []string{"stattrak™", "stattrak", "souvenir"}
+
+// Matcher resolves skin and case names to catalog images. Names are compared without the
+// ★ knife/glove marker, StatTrak/Souvenir prefixes, wear suffixes, case or extra spacing,
+// and near misses (a typo or a renamed finish spelled slightly differently) still match.
+type Matcher struct {
+	exact map[string]string
+	byKey map[string]candidate
+	keys  []string
+}
+
+// candidate is the preferred image for a normalized name
+type candidate struct {
+	url  string
+	rank int
+}
+
+// NewMatcher indexes a catalog's images
+func NewMatcher(images map[string]string) *Matcher {
+	m := &Matcher{exact: images, byKey: make(map[string]candidate, len(images))}
+	for name, url := range images {
+		if url == "" {
+			continue
+		}
+		key, rank := normalize(name)
+		if key == "" {
+			continue
+		}
+		// the plain listing wins over StatTrak/Souvenir, and better wear over worse
+		current, ok := m.byKey[key]
+		if !ok {
+			m.keys = append(m.keys, key)
+		}
+		if !ok || rank < current.rank || (rank == current.rank && url < current.url) {
+			m.byKey[key] = candidate{url: url, rank: rank}
+		}
+	}
+	return m
+}
+
+// Resolve returns the image for name, or false when nothing in the catalog is close enough
+func (m *Matcher) Resolve(name string) (string, bool) {
+	if url := m.exact[name]; url != "" {
+		return url, true
+	}
+
+	key, _ := normalize(name)
+	if key == "" {
+		return "", false
+	}
+	if match, ok := m.byKey[key]; ok {
+		return match.url, true
+	}
+
+	// allow about one edit per ten characters, and only when a single name is closest
+	limit := len([]rune(key)) / 10
+	if limit == 0 {
+		return "", false
+	}
+	best, bestDistance, tied := "", limit+1, false
+	for _, candidateKey := range m.keys {
+		if abs(len(candidateKey)-len(key)) > limit {
+			continue
+		}
+		distance := levenshtein(key, candidateKey, limit)
+		switch {
+		case distance < bestDistance:
+			best, bestDistance, tied = candidateKey, distance, false
+		case distance == bestDistance:
+			tied = true
+		}
+	}
+	if best == "" || tied {
+		return "", false
+	}
+	return m.byKey[best].url, true
+}
+
+// normalize reduces a market hash name to its comparison key and ranks the listing:
+// 0 for a name without wear, 1-5 by wear, plus 10 for StatTrak and Souvenir listings
+func normalize(name string) (string, int) {
+	s := strings.ToLower(strings.ReplaceAll(name, "★", " "))
+	s = strings.Join(strings.Fields(s), " ")
+
+	rank := 0
+	for _, prefix := range variantPrefixes {
+		if strings.HasPrefix(s, prefix+" ") {
+			s = strings.TrimSpace(strings.TrimPrefix(s, prefix))
+			rank += 10
+			break
+		}
+	}
+	if strings.HasSuffix(s, ")") {
+		if open := strings.LastIndex(s, " ("); open >= 0 {
+			wear := s[open+2 : len(s)-1]
+			for i, suffix := range wearSuffixes {
+				if wear == suffix {
+					s = s[:open]
+					rank += i + 1
+					break
+				}
+			}
+		}
+	}
+
+	// "AK-47|Redline" and "AK-47 | Redline" are the same skin
+	var builder strings.Builder
+	for _, part := range strings.Split(s, "|") {
+		if builder.Len() > 0 {
+			builder.WriteString(" | ")
+		}
+		builder.WriteString(strings.TrimFunc(part, unicode.IsSpace))
+	}
+	return strings.ReplaceAll(builder.String(), "™", ""), rank
+}
+
+// levenshtein returns the edit distance between a and b, or limit+1 once it must exceed limit
+func levenshtein(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+	return min(previous[len(rb)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
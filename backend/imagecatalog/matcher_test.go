@@ -0,0 +1,84 @@
+package imagecatalog
+
+import "testing"
+
+func TestMatcherResolve(t *testing.T) {
+	matcher := NewMatcher(map[string]string{
+		"★ Karambit | Fade (Factory New)":          "karambit-fn",
+		"★ Karambit | Fade (Minimal Wear)":         "karambit-mw",
+		"StatTrak™ AK-47 | Redline (Field-Tested)": "redline-st-ft",
+		"AK-47 | Redline (Field-Tested)":           "redline-ft",
+		"StatTrak™ M4A4 | Howl (Minimal Wear)":     "howl-st-mw",
+		"Chroma Case":                              "chroma",
+		"Fracture":                                 "fracture",
+		"Glock-18 | Fade (Factory New)":            "glock-fade",
+		"AWP | Asiimov (Field-Tested)":             "asiimov",
+		"AWP | Asiimow (Field-Tested)":             "asiimow",
+	})
+
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"Chroma Case", "chroma", true},                           // exact
+		{"★ Karambit | Fade (Minimal Wear)", "karambit-mw", true}, // exact beats the preferred wear
+		{"Karambit | Fade", "karambit-fn", true},                  // no star, best wear
+		{"AK-47 | Redline", "redline-ft", true},                   // plain listing beats StatTrak
+		{"M4A4 | Howl", "howl-st-mw", true},                       // only a StatTrak listing exists
+		{"ak-47|redline", "redline-ft", true},                     // case and spacing
+		{"Chroma  Case", "chroma", true},                          // extra spacing
+		{"Glock-18 | Fadee", "glock-fade", true},                  // one typo
+		{"AWP | Asiimox", "", false},                              // tied between two near misses
+		{"Desert Eagle | Blaze", "", false},                       // unknown
+		{"Fracturo", "", false},                                   // too short for a typo
+		{"", "", false},                                           // empty
+	}
+	for _, tt := range tests {
+		got, ok := matcher.Resolve(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		rank int
+	}{
+		{"AK-47 | Redline", "ak-47 | redline", 0},
+		{"AK-47 | Redline (Factory New)", "ak-47 | redline", 1},
+		{"AK-47 | Redline (Battle-Scarred)", "ak-47 | redline", 5},
+		{"StatTrak™ AK-47 | Redline (Field-Tested)", "ak-47 | redline", 13},
+		{"Souvenir AWP | Dragon Lore (Factory New)", "awp | dragon lore", 11},
+		{"★ StatTrak™ Karambit | Fade (Factory New)", "karambit | fade", 11},
+		{"Sticker | Team (Holo)", "sticker | team (holo)", 0},
+	}
+	for _, tt := range tests {
+		key, rank := normalize(tt.name)
+		if key != tt.key || rank != tt.rank {
+			t.Errorf("normalize(%q) = %q, %d; want %q, %d", tt.name, key, rank, tt.key, tt.rank)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"kitten", "sitting", 5, 3},
+		{"same", "same", 1, 0},
+		{"", "abc", 5, 3},
+		{"kitten", "sitting", 1, 2}, // capped at limit+1
+		{"héllo", "hello", 2, 1},    // runes, not bytes
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("levenshtein(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
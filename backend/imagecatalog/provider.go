@@ -0,0 +1,64 @@
+// Package imagecatalog loads catalogs that map CS2 market hash names to item image URLs
+// and matches skin and case names against them.
+package imagecatalog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SteamAPIsURL is SteamApis' public CS2 image catalog
+const SteamAPIsURL = "https://api.steamapis.com/image/items/730"
+
+// Catalog is a snapshot of an image catalog
+type Catalog struct {
+	// Images maps market hash names such as "★ Karambit | Fade (Factory New)" to image URLs
+	Images map[string]string
+
+	// Source describes where the catalog came from (a URL or a file path)
+	Source    string
+	FetchedAt time.Time
+
+	// Stale is set when the source could not be reached and the cached copy was used
+	Stale bool
+}
+
+// Provider loads an image catalog
+type Provider interface {
+	Fetch() (*Catalog, error)
+}
+
+// Sources selectable through Config.Source
+const (
+	SourceHTTP = "http"
+	SourceFile = "file"
+)
+
+// Config selects and configures a Provider
+type Config struct {
+	Source string
+
+	// http source; the last good catalog is kept in CacheDir (disabled when empty)
+	URL      string
+	CacheDir string
+	Timeout  time.Duration
+
+	// file source
+	File string
+}
+
+// New builds the Provider for the configured source
+func New(cfg Config) (Provider, error) {
+	switch strings.ToLower(cfg.Source) {
+	case "", SourceHTTP:
+		return NewHTTPProvider(cfg.URL, cfg.CacheDir, cfg.Timeout), nil
+	case SourceFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("file image catalog requires a file path")
+		}
+		return NewFileProvider(cfg.File), nil
+	default:
+		return nil, fmt.Errorf("unknown image catalog source %q", cfg.Source)
+	}
+}
@@ -0,0 +1,58 @@
+package imageproxy
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// placeholderBackground matches the dark card background of the frontend
+var placeholderBackground = color.NRGBA{R: 0x11, G: 0x18, B: 0x27, A: 0xff}
+
+// Placeholder draws a width x width PNG in the given "#RRGGBB" color: a glow fading from the
+// top into the dark card background and a solid bar along the bottom, like a rarity card
+func Placeholder(hexColor string, width int) ([]byte, error) {
+	accent, err := parseHexColor(hexColor)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, width))
+	bar := max(2, width/16)
+	for y := 0; y < width; y++ {
+		c := accent
+		if y < width-bar {
+			c = blend(placeholderBackground, accent, 0.45*float64(width-bar-y)/float64(width-bar))
+		}
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// blend mixes amount (0-1) of top over base
+func blend(base, top color.NRGBA, amount float64) color.NRGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*amount)
+	}
+	return color.NRGBA{R: mix(base.R, top.R), G: mix(base.G, top.G), B: mix(base.B, top.B), A: 0xff}
+}
+
+// parseHexColor parses "#RRGGBB"
+func parseHexColor(hexColor string) (color.NRGBA, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hexColor, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hexColor, "#")) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", hexColor)
+	}
+	return color.NRGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}, nil
+}
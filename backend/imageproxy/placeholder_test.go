@@ -0,0 +1,75 @@
+package imageproxy
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+)
+
+func TestPlaceholderUsesRarityColor(t *testing.T) {
+	for _, rarity := range []string{"Mil-Spec", "Classified", "Covert", "Unknown"} {
+		hexColor := (&models.Skin{Rarity: rarity}).GetRarityColor()
+		want, err := parseHexColor(hexColor)
+		if err != nil {
+			t.Fatalf("%s: %v", rarity, err)
+		}
+
+		data, err := Placeholder(hexColor, 64)
+		if err != nil {
+			t.Fatalf("Placeholder(%s): %v", hexColor, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("placeholder is not a PNG: %v", err)
+		}
+		if img.Bounds() != image.Rect(0, 0, 64, 64) {
+			t.Fatalf("placeholder size = %v, want 64x64", img.Bounds())
+		}
+
+		// the bottom bar is the rarity color, the top a glow of it over the card background
+		if got := color.NRGBAModel.Convert(img.At(32, 63)).(color.NRGBA); got != want {
+			t.Errorf("%s bar color = %+v, want %+v", rarity, got, want)
+		}
+		top := color.NRGBAModel.Convert(img.At(32, 0)).(color.NRGBA)
+		if top == want || top == placeholderBackground {
+			t.Errorf("%s top color = %+v, want a blend of the rarity color and the background", rarity, top)
+		}
+	}
+}
+
+func TestPlaceholderRejectsInvalidColors(t *testing.T) {
+	for _, hexColor := range []string{"", "#FFF", "red", "#GGGGGG", "#1234567"} {
+		if _, err := Placeholder(hexColor, 16); err == nil {
+			t.Errorf("Placeholder(%q) succeeded, want an error", hexColor)
+		}
+	}
+}
+
+func TestProxyPlaceholderIsCached(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	proxy := New(store, 0)
+
+	first, err := proxy.Placeholder("#EB4B4B", 0)
+	if err != nil {
+		t.Fatalf("Placeholder: %v", err)
+	}
+	if !first.Placeholder || first.ContentType != "image/png" {
+		t.Fatalf("Placeholder = %+v, want a PNG marked as placeholder", first)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(first.Data))
+	if err != nil || config.Width != placeholderWidth {
+		t.Fatalf("default placeholder width = %d (%v), want %d", config.Width, err, placeholderWidth)
+	}
+
+	second, _ := proxy.Placeholder("#eb4b4b", 0)
+	if second != first {
+		t.Error("the same color and width rendered a new placeholder")
+	}
+}
@@ -0,0 +1,215 @@
+// Package imageproxy serves skin and case images from a local blob store, fetching each
+// upstream image once and keeping resized variants next to the original.
+package imageproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNoSource is returned for items without an image URL
+var ErrNoSource = errors.New("item has no image URL")
+
+// maxImageSize caps how much of an upstream image is read
+const maxImageSize = 10 << 20
+
+// failureBackoff is how long a failed upstream fetch is not retried
+const failureBackoff = 5 * time.Minute
+
+// placeholderWidth is the size of placeholders requested without a width
+const placeholderWidth = 512
+
+// Image is an image ready to be served
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+
+	// Placeholder is set for generated images standing in for a missing upstream image
+	Placeholder bool
+}
+
+// Proxy fetches upstream images into a Store and serves them and their resized variants
+type Proxy struct {
+	store  *Store
+	client *http.Client
+
+	// fetches shares one upstream fetch between concurrent requests for the same blob
+	fetches singleflight.Group
+
+	mu           sync.Mutex
+	failures     map[string]time.Time // pruned once failureBackoff has passed
+	placeholders map[string]*Image
+}
+
+// New creates a proxy storing blobs in store
+func New(store *Store, timeout time.Duration) *Proxy {
+	return &Proxy{
+		store:        store,
+		client:       &http.Client{Timeout: timeout},
+		failures:     map[string]time.Time{},
+		placeholders: map[string]*Image{},
+	}
+}
+
+// Get returns the image at sourceURL for an item, width pixels wide (0 for the original).
+// Blobs are keyed by the source URL, so changing an item's image URL fetches the new image.
+func (p *Proxy) Get(kind, id, sourceURL string, width int) (*Image, error) {
+	if strings.TrimSpace(sourceURL) == "" {
+		return nil, ErrNoSource
+	}
+	sum := sha256.Sum256([]byte(sourceURL))
+	version := hex.EncodeToString(sum[:8])
+	base := fmt.Sprintf("%s/%s/%s", kind, id, version)
+
+	original, err := p.original(base+"/original", sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if width <= 0 {
+		return &Image{Data: original, ContentType: http.DetectContentType(original), ETag: `"` + version + `"`}, nil
+	}
+
+	etag := fmt.Sprintf(`"%s-w%d"`, version, width)
+	variantKey := fmt.Sprintf("%s/w%d", base, width)
+	if data, err := p.store.Get(variantKey); err == nil {
+		return &Image{Data: data, ContentType: http.DetectContentType(data), ETag: etag}, nil
+	}
+
+	resized, err := resizePNG(original, width)
+	if err != nil {
+		// formats the standard library cannot decode, and images too large to resize,
+		// are served at their original size
+		return &Image{Data: original, ContentType: http.DetectContentType(original), ETag: etag}, nil
+	}
+	if err := p.store.Put(variantKey, resized); err != nil {
+		return nil, fmt.Errorf("failed to store resized image: %w", err)
+	}
+	return &Image{Data: resized, ContentType: http.DetectContentType(resized), ETag: etag}, nil
+}
+
+// original returns the stored upstream image, fetching it first if needed.
+// Concurrent requests for the same image wait for a single fetch.
+func (p *Proxy) original(key, sourceURL string) ([]byte, error) {
+	if data, err := p.store.Get(key); err == nil {
+		return data, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	data, err, _ := p.fetches.Do(key, func() (interface{}, error) {
+		// an earlier fetch may have stored it after this request's first check
+		if data, err := p.store.Get(key); err == nil {
+			return data, nil
+		}
+		if p.recentlyFailed(key) {
+			return nil, fmt.Errorf("upstream image recently failed")
+		}
+
+		data, err := p.fetch(sourceURL)
+		p.recordFetch(key, err)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.store.Put(key, data); err != nil {
+			return nil, fmt.Errorf("failed to store image: %w", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.([]byte), nil
+}
+
+// recentlyFailed reports whether the blob's last fetch failed within failureBackoff
+func (p *Proxy) recentlyFailed(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	failedAt, failed := p.failures[key]
+	return failed && time.Since(failedAt) < failureBackoff
+}
+
+// recordFetch remembers a failed fetch, forgets a successful one and drops failures whose
+// backoff has passed, so the map only holds blobs that are currently backed off
+func (p *Proxy) recordFetch(key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for failedKey, failedAt := range p.failures {
+		if now.Sub(failedAt) >= failureBackoff {
+			delete(p.failures, failedKey)
+		}
+	}
+	if err != nil {
+		p.failures[key] = now
+	} else {
+		delete(p.failures, key)
+	}
+}
+
+// fetch downloads an upstream image and checks that it is one
+func (p *Proxy) fetch(sourceURL string) ([]byte, error) {
+	parsed, err := url.Parse(sourceURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("unsupported image URL %q", sourceURL)
+	}
+
+	resp, err := p.client.Get(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream image returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, fmt.Errorf("upstream image is larger than %d bytes", maxImageSize)
+	}
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		return nil, fmt.Errorf("upstream did not return an image")
+	}
+	return data, nil
+}
+
+// Placeholder returns a generated placeholder in the given color, width pixels square
+func (p *Proxy) Placeholder(hexColor string, width int) (*Image, error) {
+	if width <= 0 {
+		width = placeholderWidth
+	}
+	key := fmt.Sprintf("%s-%d", strings.ToLower(hexColor), width)
+
+	p.mu.Lock()
+	cached, ok := p.placeholders[key]
+	p.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	data, err := Placeholder(hexColor, width)
+	if err != nil {
+		return nil, err
+	}
+	placeholder := &Image{Data: data, ContentType: "image/png", ETag: `"placeholder-` + strings.TrimPrefix(key, "#") + `"`, Placeholder: true}
+
+	p.mu.Lock()
+	p.placeholders[key] = placeholder
+	p.mu.Unlock()
+	return placeholder, nil
+}
@@ -0,0 +1,168 @@
+package imageproxy
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// upstreamServer serves test images: /image.png is a 1024x512 PNG, /page returns HTML,
+// /missing a 404. Requests are counted per path.
+func upstreamServer(t *testing.T) (*httptest.Server, map[string]*atomic.Int32) {
+	t.Helper()
+	image := encodePNG(t, 1024, 512, color.NRGBA{B: 255, A: 255})
+	counts := map[string]*atomic.Int32{"/image.png": {}, "/other.png": {}, "/page": {}, "/missing": {}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, ok := counts[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL)
+			return
+		}
+		count.Add(1)
+		switch r.URL.Path {
+		case "/image.png", "/other.png":
+			time.Sleep(10 * time.Millisecond) // give concurrent requests time to pile up
+			w.Write(image)
+		case "/page":
+			w.Write([]byte("<html><body>not an image</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, counts
+}
+
+func newTestProxy(t *testing.T) *Proxy {
+	t.Helper()
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	return New(store, 5*time.Second)
+}
+
+func TestProxyGetFetchesOnceAndServesFromStore(t *testing.T) {
+	server, counts := upstreamServer(t)
+	proxy := newTestProxy(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := proxy.Get("skins", "abc", server.URL+"/image.png", 0); err != nil {
+				t.Errorf("Get: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	image, err := proxy.Get("skins", "abc", server.URL+"/image.png", 0)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := counts["/image.png"].Load(); got != 1 {
+		t.Fatalf("upstream got %d requests, want 1", got)
+	}
+	if image.ContentType != "image/png" || image.Placeholder || image.ETag == "" {
+		t.Fatalf("Get = %s %q placeholder=%v, want the stored PNG with an ETag", image.ContentType, image.ETag, image.Placeholder)
+	}
+}
+
+func TestProxyGetResizesVariants(t *testing.T) {
+	server, counts := upstreamServer(t)
+	proxy := newTestProxy(t)
+
+	original, err := proxy.Get("skins", "abc", server.URL+"/image.png", 0)
+	if err != nil {
+		t.Fatalf("Get original: %v", err)
+	}
+	variant, err := proxy.Get("skins", "abc", server.URL+"/image.png", 256)
+	if err != nil {
+		t.Fatalf("Get variant: %v", err)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(variant.Data))
+	if err != nil || config.Width != 256 || config.Height != 128 {
+		t.Fatalf("variant is %dx%d (%v), want 256x128", config.Width, config.Height, err)
+	}
+	if variant.ETag == original.ETag {
+		t.Error("the variant shares the original's ETag")
+	}
+	if counts["/image.png"].Load() != 1 {
+		t.Error("the variant fetched the upstream image again")
+	}
+}
+
+func TestProxyGetRefetchesWhenSourceChanges(t *testing.T) {
+	server, counts := upstreamServer(t)
+	proxy := newTestProxy(t)
+
+	first, err := proxy.Get("skins", "abc", server.URL+"/image.png", 0)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	second, err := proxy.Get("skins", "abc", server.URL+"/other.png", 0)
+	if err != nil {
+		t.Fatalf("Get with the new URL: %v", err)
+	}
+	if counts["/other.png"].Load() != 1 || first.ETag == second.ETag {
+		t.Fatalf("new source URL: %d fetches, ETags %s and %s; want a fetch and a new ETag",
+			counts["/other.png"].Load(), first.ETag, second.ETag)
+	}
+}
+
+func TestProxyGetRejectsBadUpstreams(t *testing.T) {
+	server, _ := upstreamServer(t)
+	proxy := newTestProxy(t)
+
+	if _, err := proxy.Get("skins", "abc", "", 0); !errors.Is(err, ErrNoSource) {
+		t.Errorf("Get without a URL = %v, want ErrNoSource", err)
+	}
+	for _, source := range []string{server.URL + "/page", server.URL + "/missing", "file:///etc/passwd", "ftp://example.com/a.png"} {
+		if _, err := proxy.Get("skins", "abc", source, 0); err == nil {
+			t.Errorf("Get(%s) succeeded, want an error", source)
+		}
+	}
+}
+
+func TestProxyBacksOffFailedFetches(t *testing.T) {
+	server, counts := upstreamServer(t)
+	proxy := newTestProxy(t)
+	source := server.URL + "/missing"
+
+	for i := 0; i < 3; i++ {
+		if _, err := proxy.Get("skins", "abc", source, 0); err == nil {
+			t.Fatal("Get of a missing image succeeded")
+		}
+	}
+	if got := counts["/missing"].Load(); got != 1 {
+		t.Fatalf("upstream got %d requests during the backoff, want 1", got)
+	}
+}
+
+func TestProxyPrunesExpiredFailures(t *testing.T) {
+	proxy := newTestProxy(t)
+	proxy.failures["skins/old/v/original"] = time.Now().Add(-failureBackoff - time.Second)
+	proxy.failures["skins/recent/v/original"] = time.Now()
+
+	proxy.recordFetch("skins/new/v/original", nil)
+	if _, ok := proxy.failures["skins/old/v/original"]; ok {
+		t.Error("a failure past its backoff was kept")
+	}
+	if _, ok := proxy.failures["skins/recent/v/original"]; !ok {
+		t.Error("a failure still in its backoff was dropped")
+	}
+
+	proxy.recordFetch("skins/recent/v/original", nil)
+	if len(proxy.failures) != 0 {
+		t.Errorf("failures = %v, want none after the fetch succeeded", proxy.failures)
+	}
+}
@@ -0,0 +1,82 @@
+package imageproxy
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	// decoders for the formats upstream CDNs serve
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+// maxResizePixels caps the decoded size of images that get resized. maxImageSize only limits
+// the compressed bytes, and a small PNG can declare enough pixels to allocate gigabytes.
+const maxResizePixels = 4096 * 4096
+
+// resizePNG scales an encoded image down to width (keeping its aspect ratio) and encodes it as PNG.
+// Images already at most width pixels wide are returned unchanged; images over maxResizePixels
+// are refused without being decoded.
+func resizePNG(data []byte, width int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxResizePixels {
+		return nil, fmt.Errorf("image is %dx%d pixels, more than the %d allowed for resizing", config.Width, config.Height, maxResizePixels)
+	}
+	if config.Width <= width {
+		return data, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaleDown(src, width)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown shrinks src to width using an area average, which keeps thin outlines
+// and transparent edges clean without pulling in an imaging library
+func scaleDown(src image.Image, width int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	height := max(1, (srcH*width+srcW/2)/srcW)
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/width)
+
+			// sum premultiplied samples so transparent pixels do not darken the edges
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r * 0xff / a),
+				G: uint8(g * 0xff / a),
+				B: uint8(b * 0xff / a),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,116 @@
+package imageproxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// encodePNG draws a width x height PNG in a solid color
+func encodePNG(t *testing.T, width, height int, c color.NRGBA) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decodeSize(t *testing.T, data []byte) (int, int) {
+	t.Helper()
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("result is not a PNG: %v", err)
+	}
+	return config.Width, config.Height
+}
+
+func TestResizePNGKeepsAspectRatio(t *testing.T) {
+	tests := []struct {
+		width, height, target int
+		wantW, wantH          int
+	}{
+		{1024, 768, 256, 256, 192},
+		{512, 512, 128, 128, 128},
+		{1000, 3, 100, 100, 1}, // never collapses to zero rows
+		{300, 200, 128, 128, 85},
+	}
+	for _, tt := range tests {
+		data := encodePNG(t, tt.width, tt.height, color.NRGBA{R: 200, A: 255})
+		resized, err := resizePNG(data, tt.target)
+		if err != nil {
+			t.Fatalf("resizePNG(%dx%d, %d): %v", tt.width, tt.height, tt.target, err)
+		}
+		if w, h := decodeSize(t, resized); w != tt.wantW || h != tt.wantH {
+			t.Errorf("resizePNG(%dx%d, %d) = %dx%d, want %dx%d", tt.width, tt.height, tt.target, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestResizePNGLeavesSmallImagesAlone(t *testing.T) {
+	data := encodePNG(t, 100, 80, color.NRGBA{G: 200, A: 255})
+	resized, err := resizePNG(data, 256)
+	if err != nil {
+		t.Fatalf("resizePNG: %v", err)
+	}
+	if !bytes.Equal(resized, data) {
+		t.Fatal("an image narrower than the target was re-encoded")
+	}
+}
+
+func TestResizePNGRejectsHugeDimensions(t *testing.T) {
+	// a valid PNG header declaring 50000x50000 pixels; decoding it would allocate ~10 GB
+	header := make([]byte, 13)
+	binary.BigEndian.PutUint32(header[0:], 50000)
+	binary.BigEndian.PutUint32(header[4:], 50000)
+	header[8], header[9] = 8, 6 // 8-bit RGBA
+
+	var data bytes.Buffer
+	data.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&data, binary.BigEndian, uint32(len(header)))
+	chunk := append([]byte("IHDR"), header...)
+	data.Write(chunk)
+	binary.Write(&data, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+
+	if _, err := resizePNG(data.Bytes(), 256); err == nil {
+		t.Fatal("resizePNG accepted a 50000x50000 image")
+	}
+}
+
+func TestResizePNGRejectsNonImages(t *testing.T) {
+	if _, err := resizePNG([]byte("<html>not an image</html>"), 256); err == nil {
+		t.Fatal("resizePNG accepted HTML")
+	}
+}
+
+func TestScaleDownAveragesWithoutDarkeningEdges(t *testing.T) {
+	// left half opaque red, right half fully transparent
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	dst := scaleDown(src, 1)
+	if dst.Bounds().Dx() != 1 || dst.Bounds().Dy() != 1 {
+		t.Fatalf("scaleDown size = %v, want 1x1", dst.Bounds())
+	}
+	got := dst.NRGBAAt(0, 0)
+	if got.R != 255 || got.G != 0 || got.B != 0 {
+		t.Errorf("color = %+v, want pure red (transparent pixels must not darken it)", got)
+	}
+	if got.A < 126 || got.A > 128 {
+		t.Errorf("alpha = %d, want about half", got.A)
+	}
+}
@@ -0,0 +1,72 @@
+package imageproxy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	if _, err := store.Get("skins/abc/v1/original"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Get of a missing blob = %v, want fs.ErrNotExist", err)
+	}
+	if err := store.Put("skins/abc/v1/original", []byte("png")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	data, err := store.Get("skins/abc/v1/original")
+	if err != nil || string(data) != "png" {
+		t.Fatalf("Get = %q, %v; want the stored blob", data, err)
+	}
+
+	// overwriting leaves no temporary files behind
+	if err := store.Put("skins/abc/v1/original", []byte("png2")); err != nil {
+		t.Fatalf("second Put: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(store.dir, "skins", "abc", "v1"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("blob directory has %d entries (%v), want just the blob", len(entries), err)
+	}
+}
+
+func TestStoreRejectsKeysOutsideDirectory(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewStore(filepath.Join(root, "images"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	for _, key := range []string{
+		"",
+		".",
+		"..",
+		"../secret",
+		"skins/../../secret",
+		"/etc/passwd",
+		"skins/../..",
+	} {
+		if err := store.Put(key, []byte("x")); err == nil {
+			t.Errorf("Put(%q) succeeded, want it refused", key)
+		}
+		if _, err := store.Get(key); err == nil || errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Get(%q) = %v, want an invalid key error", key, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "secret")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("a traversing key wrote outside the store")
+	}
+
+	// dots inside a key that stay within the directory are fine
+	if err := store.Put("skins/a/../b/original", []byte("x")); err != nil {
+		t.Errorf("Put of a key that stays inside the store: %v", err)
+	}
+	if _, err := store.Get("skins/b/original"); err != nil {
+		t.Errorf("cleaned key was not stored at its cleaned path: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// System account codes. Every Case Bucks movement moves value between a user wallet and one of these.
+const (
+	AccountCaseSales         = "system:case_sales"
+	AccountSkinBuyback       = "system:skin_buyback"
+	AccountDailyRewards      = "system:daily_rewards"
+	AccountRegistrationBonus = "system:registration_bonus"
+	AccountOpeningBalances   = "system:opening_balances"
+	AccountOperatorGrants    = "system:operator_grants"
+)
+
+// systemAccounts maps each system account code to its display name
+var systemAccounts = map[string]string{
+	AccountCaseSales:         "Case sales",
+	AccountSkinBuyback:       "Skin buyback",
+	AccountDailyRewards:      "Daily rewards",
+	AccountRegistrationBonus: "Registration bonus",
+	AccountOpeningBalances:   "Opening balances",
+	AccountOperatorGrants:    "Operator grants",
+}
+
+// ErrInsufficientFunds is returned when a posting would make a wallet negative
+var ErrInsufficientFunds = errors.New("insufficient Case Bucks")
+
+// Posting describes a single Case Bucks movement for a user
+type Posting struct {
+	Type        models.TransactionType
+	Amount      models.Money // change to the user's wallet (negative for spending)
+	Counterpart string       // system account code on the other side
+	Description string
+	ReferenceID *uuid.UUID
+	PatternTier string // special pattern of the item opened or sold, if any
+}
+
+// EnsureSystemAccounts creates any missing system accounts
+func EnsureSystemAccounts(db *gorm.DB) error {
+	for code, name := range systemAccounts {
+		account := models.LedgerAccount{
+			Code: code,
+			Name: name,
+			Kind: models.LedgerAccountKindSystem,
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+			return fmt.Errorf("failed to create system account %s: %w", code, err)
+		}
+	}
+	return nil
+}
+
+// Post records a balanced movement between the user's wallet and a system account,
+// updates the stored balance and writes the user-facing Transaction row.
+// The caller must hold the user's row lock (database.ForUpdate) inside tx.
+func Post(tx *gorm.DB, user *models.User, posting Posting) (*models.Transaction, error) {
+	balanceBefore := user.Casebucks
+	balanceAfter := balanceBefore + posting.Amount
+	if posting.Amount < 0 && balanceAfter < 0 {
+		return nil, ErrInsufficientFunds
+	}
+
+	if posting.Amount != 0 {
+		if err := tx.Model(user).Update("casebucks", balanceAfter).Error; err != nil {
+			return nil, fmt.Errorf("failed to update balance: %w", err)
+		}
+		user.Casebucks = balanceAfter
+	}
+
+	transaction := models.Transaction{
+		UserID:        user.ID,
+		Type:          posting.Type,
+		Amount:        posting.Amount,
+		BalanceBefore: balanceBefore,
+		BalanceAfter:  balanceAfter,
+		Description:   posting.Description,
+		ReferenceID:   posting.ReferenceID,
+		PatternTier:   posting.PatternTier,
+	}
+	if err := tx.Create(&transaction).Error; err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	// zero-value events (e.g. opening an already paid case) have no money movement to post
+	if posting.Amount == 0 {
+		return &transaction, nil
+	}
+
+	wallet, err := WalletAccount(tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	counterpart, err := systemAccount(tx, posting.Counterpart)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []models.LedgerEntry{
+		{TransactionID: transaction.ID, AccountID: wallet.ID, Amount: posting.Amount},
+		{TransactionID: transaction.ID, AccountID: counterpart.ID, Amount: -posting.Amount},
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to create ledger entries: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// WalletAccount returns the user's wallet account, creating it if needed
+func WalletAccount(tx *gorm.DB, userID uuid.UUID) (*models.LedgerAccount, error) {
+	account := models.LedgerAccount{
+		Code:   "wallet:" + userID.String(),
+		Name:   "User wallet",
+		Kind:   models.LedgerAccountKindUserWallet,
+		UserID: &userID,
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, fmt.Errorf("failed to create wallet account: %w", err)
+	}
+
+	var wallet models.LedgerAccount
+	if err := tx.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+		return nil, fmt.Errorf("failed to load wallet account: %w", err)
+	}
+	return &wallet, nil
+}
+
+// systemAccount loads a system account by code
+func systemAccount(tx *gorm.DB, code string) (*models.LedgerAccount, error) {
+	var account models.LedgerAccount
+	if err := tx.Where("code = ? AND kind = ?", code, models.LedgerAccountKindSystem).First(&account).Error; err != nil {
+		return nil, fmt.Errorf("failed to load system account %s: %w", code, err)
+	}
+	return &account, nil
+}
@@ -0,0 +1,252 @@
+package ledger
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Drift is a user whose stored balance disagrees with the ledger
+type Drift struct {
+	UserID                 uuid.UUID     `json:"user_id"`
+	StoredBalance          models.Money  `json:"stored_balance"`
+	LedgerBalance          models.Money  `json:"ledger_balance"`
+	LastTransactionBalance *models.Money `json:"last_transaction_balance,omitempty"`
+}
+
+// Difference returns how far the stored balance is from the ledger balance
+func (d Drift) Difference() models.Money {
+	return d.StoredBalance - d.LedgerBalance
+}
+
+// Report is the result of a reconciliation run
+type Report struct {
+	UsersChecked           int         `json:"users_checked"`
+	Drifts                 []Drift     `json:"drifts"`
+	UnbalancedTransactions []uuid.UUID `json:"unbalanced_transactions"`
+	Repaired               int         `json:"repaired"`
+	Settled                int         `json:"settled"` // drifts gone by the time the repair locked the user
+	StartedAt              time.Time   `json:"started_at"`
+	FinishedAt             time.Time   `json:"finished_at"`
+}
+
+// HasIssues checks if the run found anything wrong
+func (r *Report) HasIssues() bool {
+	return len(r.Drifts) > 0 || len(r.UnbalancedTransactions) > 0
+}
+
+// balanceRow is a user's stored balance next to the balances derived from the ledger and history
+type balanceRow struct {
+	UserID                 uuid.UUID
+	StoredBalance          models.Money
+	LedgerBalance          models.Money
+	LastTransactionBalance *models.Money
+}
+
+// Bootstrap prepares the ledger: it creates system accounts and gives every user
+// without a wallet an opening balance entry equal to their current stored balance.
+func Bootstrap(db *gorm.DB) error {
+	if err := EnsureSystemAccounts(db); err != nil {
+		return err
+	}
+
+	var users []models.User
+	if err := db.Where("id NOT IN (?)", db.Model(&models.LedgerAccount{}).Select("user_id").Where("user_id IS NOT NULL")).
+		Find(&users).Error; err != nil {
+		return fmt.Errorf("failed to find users without wallets: %w", err)
+	}
+
+	for _, user := range users {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var locked models.User
+			if err := database.ForUpdate(tx).First(&locked, "id = ?", user.ID).Error; err != nil {
+				return err
+			}
+			if _, err := WalletAccount(tx, locked.ID); err != nil {
+				return err
+			}
+			if locked.Casebucks == 0 {
+				return nil
+			}
+
+			// post the existing balance from the opening balances account without changing it
+			opening := locked
+			opening.Casebucks = 0
+			_, err := Post(tx, &opening, Posting{
+				Type:        models.TransactionTypeAdjustment,
+				Amount:      locked.Casebucks,
+				Counterpart: AccountOpeningBalances,
+				Description: "Opening balance",
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to backfill opening balance for user %s: %w", user.ID, err)
+		}
+	}
+
+	if len(users) > 0 {
+		log.Printf("📒 Created ledger wallets for %d existing users", len(users))
+	}
+	return nil
+}
+
+// Reconcile recomputes every user's balance from the ledger and reports drift.
+// With repair enabled the stored balance is reset to the ledger balance and an
+// adjustment transaction is written so the history ends on the corrected balance.
+func Reconcile(db *gorm.DB, repair bool) (*Report, error) {
+	report := &Report{StartedAt: time.Now()}
+
+	var rows []balanceRow
+	err := db.Raw(`
+		SELECT u.id AS user_id,
+			u.casebucks AS stored_balance,
+			COALESCE(SUM(e.amount), 0) AS ledger_balance,
+			(SELECT t.balance_after FROM transactions t
+				WHERE t.user_id = u.id
+				ORDER BY t.created_at DESC LIMIT 1) AS last_transaction_balance
+		FROM users u
+		LEFT JOIN ledger_accounts a ON a.user_id = u.id
+		LEFT JOIN ledger_entries e ON e.account_id = a.id
+		WHERE u.deleted_at IS NULL
+		GROUP BY u.id, u.casebucks`).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute ledger balances: %w", err)
+	}
+	report.UsersChecked = len(rows)
+
+	for _, row := range rows {
+		historyDrift := row.LastTransactionBalance != nil && *row.LastTransactionBalance != row.StoredBalance
+		if row.StoredBalance == row.LedgerBalance && !historyDrift {
+			continue
+		}
+		report.Drifts = append(report.Drifts, Drift{
+			UserID:                 row.UserID,
+			StoredBalance:          row.StoredBalance,
+			LedgerBalance:          row.LedgerBalance,
+			LastTransactionBalance: row.LastTransactionBalance,
+		})
+	}
+
+	if err := db.Raw(`
+		SELECT transaction_id FROM ledger_entries
+		GROUP BY transaction_id
+		HAVING SUM(amount) <> 0`).Scan(&report.UnbalancedTransactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to check ledger balance: %w", err)
+	}
+
+	if repair {
+		for _, drift := range report.Drifts {
+			repaired, err := repairDrift(db, drift.UserID)
+			if err != nil {
+				return report, err
+			}
+			if repaired {
+				report.Repaired++
+			} else {
+				report.Settled++
+			}
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// repairDrift resets the stored balance to the ledger balance under the user's row lock.
+// The drift is measured again once the row is locked: a purchase that was in flight during
+// the unlocked scan may have settled since, and then there is nothing to repair.
+func repairDrift(db *gorm.DB, userID uuid.UUID) (bool, error) {
+	repaired := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+			return fmt.Errorf("failed to lock user %s: %w", userID, err)
+		}
+
+		var ledgerBalance models.Money
+		if err := tx.Model(&models.LedgerEntry{}).
+			Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+			Where("ledger_accounts.user_id = ?", userID).
+			Select("COALESCE(SUM(ledger_entries.amount), 0)").
+			Scan(&ledgerBalance).Error; err != nil {
+			return fmt.Errorf("failed to compute ledger balance for user %s: %w", userID, err)
+		}
+
+		var last []models.Transaction
+		if err := tx.Where("user_id = ?", userID).Order("created_at DESC").Limit(1).Find(&last).Error; err != nil {
+			return fmt.Errorf("failed to load the last transaction for user %s: %w", userID, err)
+		}
+		historyDrift := len(last) == 1 && last[0].BalanceAfter != user.Casebucks
+		if user.Casebucks == ledgerBalance && !historyDrift {
+			return nil
+		}
+
+		if err := tx.Model(&user).Update("casebucks", ledgerBalance).Error; err != nil {
+			return fmt.Errorf("failed to repair balance for user %s: %w", userID, err)
+		}
+
+		// the correction is not a money movement, so it has no ledger entries
+		transaction := models.Transaction{
+			UserID:        userID,
+			Type:          models.TransactionTypeAdjustment,
+			Amount:        ledgerBalance - user.Casebucks,
+			BalanceBefore: user.Casebucks,
+			BalanceAfter:  ledgerBalance,
+			Description:   "Balance corrected by ledger reconciliation",
+		}
+		if err := tx.Create(&transaction).Error; err != nil {
+			return fmt.Errorf("failed to record repair for user %s: %w", userID, err)
+		}
+		repaired = true
+		return nil
+	})
+	return repaired, err
+}
+
+// LogReport writes a summary of a reconciliation run to the log
+func LogReport(report *Report) {
+	log.Printf("📒 Ledger reconciliation checked %d users in %s", report.UsersChecked, report.FinishedAt.Sub(report.StartedAt))
+	for _, drift := range report.Drifts {
+		lastBalance := "none"
+		if drift.LastTransactionBalance != nil {
+			lastBalance = drift.LastTransactionBalance.String()
+		}
+		log.Printf("⚠️  Balance drift for user %s: stored=%s ledger=%s last_transaction=%s",
+			drift.UserID, drift.StoredBalance, drift.LedgerBalance, lastBalance)
+	}
+	for _, transactionID := range report.UnbalancedTransactions {
+		log.Printf("⚠️  Unbalanced ledger transaction %s", transactionID)
+	}
+	if report.Repaired > 0 {
+		log.Printf("🔧 Repaired %d balances", report.Repaired)
+	}
+	if report.Settled > 0 {
+		log.Printf("✅ %d drifts had settled by the time they were repaired", report.Settled)
+	}
+	if !report.HasIssues() {
+		log.Println("✅ Ledger is balanced")
+	}
+}
+
+// StartReconciliationJob runs Reconcile every interval in the background
+func StartReconciliationJob(db *gorm.DB, interval time.Duration, repair bool) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, err := Reconcile(db, repair)
+			if err != nil {
+				log.Printf("❌ Ledger reconciliation failed: %v", err)
+				continue
+			}
+			LogReport(report)
+		}
+	}()
+}
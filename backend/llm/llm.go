@@ -0,0 +1,122 @@
+// Package llm talks to chat completion models that can call tools.
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message is one turn of a conversation. Assistant messages may ask for tool calls;
+// each result comes back as a tool message carrying the call's ID.
+type Message struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// ToolCall is a model's request to run a tool with JSON-encoded arguments
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Tool describes a function the model may call; Parameters is a JSON schema object
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{}
+}
+
+// Request is a conversation to complete and the tools available to the model
+type Request struct {
+	Messages []Message
+	Tools    []Tool
+}
+
+// Usage counts the tokens a completion consumed
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Total is the number of tokens charged for the completion
+func (u Usage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Response is the model's next message
+type Response struct {
+	Message Message
+	Usage   Usage
+}
+
+// Client completes conversations. Implementations must be safe for concurrent use.
+type Client interface {
+	Name() string
+	Complete(req Request) (*Response, error)
+}
+
+// Drivers selectable through Config.Driver
+const (
+	DriverStub   = "stub"
+	DriverOpenAI = "openai"
+)
+
+// Config selects and configures a Client
+type Config struct {
+	Driver string
+
+	// openai driver (any OpenAI-compatible chat completions API)
+	URL     string
+	APIKey  string
+	Model   string
+	Timeout time.Duration
+}
+
+// New builds the Client for the configured driver
+func New(cfg Config) (Client, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverStub:
+		return NewStubClient(), nil
+	case DriverOpenAI:
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("openai client requires a model")
+		}
+		return NewOpenAIClient(cfg.URL, cfg.APIKey, cfg.Model, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown AI driver %q", cfg.Driver)
+	}
+}
+
+// estimateTokens approximates token counts for clients that do not report usage (about four characters a token)
+func estimateTokens(messages ...Message) int {
+	characters := 0
+	for _, message := range messages {
+		characters += len(message.Content)
+		for _, call := range message.ToolCalls {
+			characters += len(call.Name) + len(call.Arguments)
+		}
+	}
+	return (characters + 3) / 4
+}
+
+// mustJSON encodes tool arguments built from plain maps
+func mustJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
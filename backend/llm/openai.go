@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIURL is the OpenAI API; other OpenAI-compatible servers work through Config.URL
+const OpenAIURL = "https://api.openai.com/v1"
+
+// maxResponseSize caps how much of a completion response is read
+const maxResponseSize = 4 << 20
+
+// OpenAIClient calls an OpenAI-compatible /chat/completions endpoint with function tools
+type OpenAIClient struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIClient creates a client for the API at baseURL (OpenAI by default)
+func NewOpenAIClient(baseURL, apiKey, model string, timeout time.Duration) *OpenAIClient {
+	if baseURL == "" {
+		baseURL = OpenAIURL
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &OpenAIClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the client
+func (c *OpenAIClient) Name() string {
+	return DriverOpenAI
+}
+
+// openAIMessage is a message in the chat completions wire format
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    *string          `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description"`
+		Parameters  map[string]interface{} `json:"parameters"`
+	} `json:"function"`
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Tools    []openAITool    `json:"tools,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete sends the conversation and returns the model's next message
+func (c *OpenAIClient) Complete(req Request) (*Response, error) {
+	body := openAIRequest{Model: c.model}
+	for _, message := range req.Messages {
+		wire := openAIMessage{Role: message.Role, ToolCallID: message.ToolCallID}
+		// assistant messages that only call tools carry a null content
+		if message.Content != "" || len(message.ToolCalls) == 0 {
+			content := message.Content
+			wire.Content = &content
+		}
+		for _, call := range message.ToolCalls {
+			wireCall := openAIToolCall{ID: call.ID, Type: "function"}
+			wireCall.Function.Name = call.Name
+			wireCall.Function.Arguments = call.Arguments
+			wire.ToolCalls = append(wire.ToolCalls, wireCall)
+		}
+		body.Messages = append(body.Messages, wire)
+	}
+	for _, tool := range req.Tools {
+		wireTool := openAITool{Type: "function"}
+		wireTool.Function.Name = tool.Name
+		wireTool.Function.Description = tool.Description
+		wireTool.Function.Parameters = tool.Parameters
+		body.Tools = append(body.Tools, wireTool)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("completion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed openAIResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("invalid completion response (%s): %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if parsed.Error != nil {
+			return nil, fmt.Errorf("completion failed (%s): %s", resp.Status, parsed.Error.Message)
+		}
+		return nil, fmt.Errorf("completion failed (%s)", resp.Status)
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("completion returned no choices")
+	}
+
+	wire := parsed.Choices[0].Message
+	message := Message{Role: RoleAssistant}
+	if wire.Content != nil {
+		message.Content = *wire.Content
+	}
+	for _, call := range wire.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+
+	usage := parsed.Usage
+	if usage.Total() == 0 {
+		usage = Usage{PromptTokens: estimateTokens(req.Messages...), CompletionTokens: estimateTokens(message)}
+	}
+	return &Response{Message: message, Usage: usage}, nil
+}
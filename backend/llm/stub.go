@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Tool names the stub knows how to call when they are offered
+const (
+	stubPriceTool     = "price_check"
+	stubInventoryTool = "get_inventory"
+)
+
+// stubConditions are the wear names the stub picks out of a question
+var stubConditions = []string{"Factory New", "Minimal Wear", "Field-Tested", "Well-Worn", "Battle-Scarred"}
+
+// StubClient is a deterministic stand-in for a language model (development and tests).
+// Questions about "my" items call the inventory tool, questions naming a skin as
+// "Weapon | Finish" call the price tool, and tool results are answered with their summaries.
+type StubClient struct{}
+
+// NewStubClient creates a stub client
+func NewStubClient() *StubClient {
+	return &StubClient{}
+}
+
+// Name identifies the client
+func (c *StubClient) Name() string {
+	return DriverStub
+}
+
+// Complete answers the last message of the conversation
+func (c *StubClient) Complete(req Request) (*Response, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("conversation is empty")
+	}
+
+	var reply Message
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role == RoleTool {
+		reply = Message{Role: RoleAssistant, Content: summarizeToolResults(req.Messages)}
+	} else {
+		reply = c.answer(req, last.Content)
+	}
+
+	return &Response{
+		Message: reply,
+		Usage:   Usage{PromptTokens: estimateTokens(req.Messages...), CompletionTokens: estimateTokens(reply)},
+	}, nil
+}
+
+// answer replies to a user message, calling a tool when the question needs one
+func (c *StubClient) answer(req Request, question string) Message {
+	lower := strings.ToLower(question)
+	callID := fmt.Sprintf("call_%d", countToolCalls(req.Messages)+1)
+
+	asksAboutOwnItems := strings.Contains(lower, "inventory") || containsWord(lower, "my") ||
+		strings.Contains(lower, "i own") || strings.Contains(lower, "i have")
+	if asksAboutOwnItems && offersTool(req.Tools, stubInventoryTool) {
+		return Message{Role: RoleAssistant, ToolCalls: []ToolCall{{
+			ID:        callID,
+			Name:      stubInventoryTool,
+			Arguments: mustJSON(map[string]interface{}{"query": question}),
+		}}}
+	}
+
+	if skinName := extractSkinName(question); skinName != "" && offersTool(req.Tools, stubPriceTool) {
+		arguments := map[string]interface{}{"skin_name": skinName}
+		for _, condition := range stubConditions {
+			if strings.Contains(lower, strings.ToLower(condition)) {
+				arguments["condition"] = condition
+			}
+		}
+		if strings.Contains(lower, "stattrak") {
+			arguments["stattrak"] = true
+		}
+		if strings.Contains(lower, "souvenir") {
+			arguments["souvenir"] = true
+		}
+		return Message{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: callID, Name: stubPriceTool, Arguments: mustJSON(arguments)}}}
+	}
+
+	return Message{Role: RoleAssistant, Content: "I can check market prices and what the items in your inventory are worth. " +
+		`Try "What is AWP | Asiimov (Field-Tested) worth?" or "What is my AK-47 Redline worth?"`}
+}
+
+// summarizeToolResults answers with the results of the latest round of tool calls,
+// using each result's "summary" field when it has one
+func summarizeToolResults(messages []Message) string {
+	start := len(messages)
+	for start > 0 && messages[start-1].Role == RoleTool {
+		start--
+	}
+
+	lines := []string{}
+	for _, message := range messages[start:] {
+		var result struct {
+			Summary string `json:"summary"`
+			Error   string `json:"error"`
+		}
+		switch {
+		case json.Unmarshal([]byte(message.Content), &result) != nil:
+			lines = append(lines, message.Content)
+		case result.Error != "":
+			lines = append(lines, "Sorry, "+result.Error)
+		case result.Summary != "":
+			lines = append(lines, result.Summary)
+		default:
+			lines = append(lines, message.Content)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// extractSkinName finds a "Weapon | Finish" name: up to two capitalized words before the bar
+// and the capitalized words after it, e.g. "Desert Eagle | Blaze" or "★ Karambit | Fade"
+func extractSkinName(text string) string {
+	bar := strings.Index(text, "|")
+	if bar < 0 {
+		return ""
+	}
+
+	before := strings.Fields(text[:bar])
+	weapon := []string{}
+	for i := len(before) - 1; i >= 0 && len(weapon) < 2; i-- {
+		if !isNameWord(before[i]) || isVariantWord(before[i]) {
+			break
+		}
+		weapon = append([]string{before[i]}, weapon...)
+	}
+	if len(before) > len(weapon) && before[len(before)-len(weapon)-1] == "★" {
+		weapon = append([]string{"★"}, weapon...)
+	}
+
+	finish := []string{}
+	for _, word := range strings.Fields(text[bar+1:]) {
+		trimmed := strings.TrimRight(word, "?!.,")
+		if !isNameWord(trimmed) {
+			break
+		}
+		finish = append(finish, trimmed)
+		if trimmed != word {
+			break
+		}
+	}
+
+	if len(weapon) == 0 || len(finish) == 0 {
+		return ""
+	}
+	return strings.Join(weapon, " ") + " | " + strings.Join(finish, " ")
+}
+
+// isNameWord reports whether a word can be part of a skin name (capitalized or a number like "08")
+func isNameWord(word string) bool {
+	for _, r := range word {
+		return unicode.IsUpper(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+// isVariantWord reports whether a word is a StatTrak or Souvenir prefix rather than part of the weapon
+func isVariantWord(word string) bool {
+	word = strings.ToLower(strings.TrimSuffix(word, "™"))
+	return word == "stattrak" || word == "souvenir"
+}
+
+// containsWord reports whether text contains word on its own
+func containsWord(text, word string) bool {
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if field == word {
+			return true
+		}
+	}
+	return false
+}
+
+// offersTool reports whether the request lets the model call the tool
+func offersTool(tools []Tool, name string) bool {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// countToolCalls counts the tool calls made so far, for unique call IDs
+func countToolCalls(messages []Message) int {
+	count := 0
+	for _, message := range messages {
+		count += len(message.ToolCalls)
+	}
+	return count
+}
@@ -3,7 +3,10 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
 	"github.com/TyronOdame/CS-OPN/backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -46,10 +49,34 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// make sure the user still exists, hasn't logged out everywhere, and the session is still live
+		var user models.User
+		if err := database.DB.Select("id", "token_version").First(&user, "id = ?", claims.UserID).Error; err != nil || user.TokenVersion != claims.TokenVersion {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired token",
+			})
+			c.Abort()
+			return
+		}
+
+		var activeSessions int64
+		if err := database.DB.Model(&models.Session{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", claims.SessionID, claims.UserID, time.Now()).
+			Count(&activeSessions).Error; err != nil || activeSessions == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Session has been revoked",
+			})
+			c.Abort()
+			return
+		}
+
 		// token is valid! store user info in context for handlers to use
 		c.Set("userID", claims.UserID)
+		c.Set("sessionID", claims.SessionID)
 		c.Set("userEmail", claims.Email)
 		c.Set("username", claims.Username)
+		c.Set("userRole", claims.Role)
+		c.Set("userPermissions", claims.Permissions)
 
 		// continue to the next handler
 		c.Next()
@@ -70,3 +97,12 @@ func GetUserID(c * gin.Context) (uuid.UUID, error) {
 	return userID.(uuid.UUID), nil
 }
 
+
+// GetSessionID returns the session the request's access token belongs to
+func GetSessionID(c *gin.Context) uuid.UUID {
+	sessionID, exists := c.Get("sessionID")
+	if !exists {
+		return uuid.Nil
+	}
+	return sessionID.(uuid.UUID)
+}
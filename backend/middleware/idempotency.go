@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayHeader marks responses that were replayed from a stored result
+const IdempotentReplayHeader = "Idempotent-Replayed"
+
+// responseRecorder captures the response body while still writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency stores the first response per user + Idempotency-Key and replays it on retries.
+// It must run after AuthMiddleware. Requests without the header pass through untouched.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		userID, err := GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		requestHash, err := hashRequest(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to read request body",
+			})
+			c.Abort()
+			return
+		}
+
+		// drop an expired record so the key can be used again
+		database.DB.Where("user_id = ? AND key = ? AND expires_at < ?", userID, key, time.Now()).
+			Delete(&models.IdempotencyKey{})
+
+		// claim the key; the unique index makes concurrent retries lose this race
+		record := models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+		}
+		result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to store idempotency key",
+			})
+			c.Abort()
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			replayStoredResponse(c, userID.String(), key, requestHash)
+			return
+		}
+
+		// release the claim if the handler panics, otherwise retries would get 409 until the key expires
+		completed := false
+		defer func() {
+			if !completed {
+				database.DB.Delete(&record)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+
+		// server errors are not stored so the client can retry the request for real
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		now := time.Now()
+		completed = database.DB.Model(&record).Updates(map[string]interface{}{
+			"status_code":   status,
+			"response_body": recorder.body.Bytes(),
+			"completed_at":  now,
+		}).Error == nil
+	}
+}
+
+// replayStoredResponse answers a retried request from the stored record
+func replayStoredResponse(c *gin.Context, userID, key, requestHash string) {
+	var existing models.IdempotencyKey
+	if err := database.DB.Where("user_id = ? AND key = ?", userID, key).First(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load idempotency key",
+		})
+		c.Abort()
+		return
+	}
+
+	if existing.RequestHash != requestHash {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Idempotency-Key was already used for a different request",
+		})
+		c.Abort()
+		return
+	}
+
+	if !existing.IsCompleted() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "A request with this Idempotency-Key is still being processed",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Header(IdempotentReplayHeader, "true")
+	c.Data(existing.StatusCode, "application/json; charset=utf-8", existing.ResponseBody)
+	c.Abort()
+}
+
+// hashRequest fingerprints the method, path and body so a key cannot be reused for another request.
+// The body is restored for the handler.
+func hashRequest(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Request.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission only lets through users whose token grants every listed permission.
+// It must run after AuthMiddleware.
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, permission := range permissions {
+			if !HasPermission(c, permission) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":      "You do not have permission to do this",
+					"permission": permission,
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission checks if the authenticated user's token grants the permission
+func HasPermission(c *gin.Context, permission models.Permission) bool {
+	granted, exists := c.Get("userPermissions")
+	if !exists {
+		return false
+	}
+
+	for _, p := range granted.([]string) {
+		if models.Permission(p) == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUserRole is a helper function to extract the user's role from context
+func GetUserRole(c *gin.Context) models.Role {
+	return models.Role(c.GetString("userRole"))
+}
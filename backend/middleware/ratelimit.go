@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKey picks what a rate limit is counted against
+type RateLimitKey int
+
+const (
+	RateLimitByIP   RateLimitKey = iota
+	RateLimitByUser              // falls back to the IP for unauthenticated requests
+)
+
+// RateLimit rejects requests over the limit with 429 and reports the bucket state in
+// X-RateLimit-* headers. name separates the buckets of different route groups.
+// Keying by user must run after AuthMiddleware.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit, by RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := name + ":ip:" + c.ClientIP()
+		if by == RateLimitByUser {
+			if userID, err := GetUserID(c); err == nil {
+				key = name + ":user:" + userID.String()
+			}
+		}
+
+		result, err := store.Take(key, limit, time.Now())
+		if err != nil {
+			// fail open so a broken store does not take the API down with it
+			log.Printf("⚠️ Rate limit store error: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       fmt.Sprintf("Too many requests. Try again in %d seconds", retryAfter),
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitedRouter serves /ping behind a one-request-per-hour IP limit
+func rateLimitedRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatalf("SetTrustedProxies failed: %v", err)
+	}
+	limit := ratelimit.Limit{Rate: 1, Period: time.Hour, Burst: 1}
+	router.GET("/ping", RateLimit(ratelimit.NewMemoryStore(0), "test", limit, RateLimitByIP), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func ping(router *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestRateLimitByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	router := rateLimitedRouter(t, nil)
+
+	if status := ping(router, "203.0.113.7:5000", "198.51.100.1"); status != http.StatusOK {
+		t.Fatalf("first request status = %d, want %d", status, http.StatusOK)
+	}
+	// a new X-Forwarded-For from the same connection must not buy a fresh bucket
+	if status := ping(router, "203.0.113.7:5000", "198.51.100.2"); status != http.StatusTooManyRequests {
+		t.Errorf("request with a rotated X-Forwarded-For status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+}
+
+func TestRateLimitByIPUsesForwardedForFromTrustedProxy(t *testing.T) {
+	router := rateLimitedRouter(t, []string{"10.0.0.0/8"})
+
+	if status := ping(router, "10.0.0.5:5000", "198.51.100.1"); status != http.StatusOK {
+		t.Fatalf("first client status = %d, want %d", status, http.StatusOK)
+	}
+	// behind the proxy, each forwarded client has its own bucket
+	if status := ping(router, "10.0.0.5:5000", "198.51.100.2"); status != http.StatusOK {
+		t.Errorf("second client status = %d, want %d", status, http.StatusOK)
+	}
+	if status := ping(router, "10.0.0.5:5000", "198.51.100.1"); status != http.StatusTooManyRequests {
+		t.Errorf("repeat client status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+}
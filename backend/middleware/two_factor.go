@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/twofactor"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFreshTwoFactor guards sensitive actions: users with 2FA on must send a current code
+// (or a recovery code) in the X-2FA-Code header. Wrong codes are counted per user, so guessing
+// is locked out no matter where the requests come from. Must run after AuthMiddleware.
+func RequireFreshTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !CheckFreshTwoFactor(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// CheckFreshTwoFactor verifies the X-2FA-Code header for the current user.
+// On failure it writes the response, aborts the request and returns false.
+func CheckFreshTwoFactor(c *gin.Context) bool {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Abort()
+		return false
+	}
+
+	err = twofactor.CheckFresh(database.DB, userID, c.GetHeader(twofactor.CodeHeader))
+	var locked *twofactor.LockedError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &locked):
+		RespondTwoFactorLocked(c, locked.Until)
+	case errors.Is(err, twofactor.ErrCodeRequired):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":               "A two-factor code is required for this action",
+			"two_factor_required": true,
+		})
+	case errors.Is(err, twofactor.ErrInvalidCode):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":               "Invalid two-factor code",
+			"two_factor_required": true,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify two-factor code"})
+	}
+	c.Abort()
+	return false
+}
+
+// RespondTwoFactorLocked writes the 429 sent while a user's two-factor codes are locked out
+func RespondTwoFactorLocked(c *gin.Context, until time.Time) {
+	retryAfter := int(math.Ceil(time.Until(until).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       fmt.Sprintf("Too many invalid two-factor codes. Try again in %d seconds", retryAfter),
+		"retry_after": retryAfter,
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+)
+
+// runMigrateCommand manages the versioned schema migrations.
+// Usage: backend migrate up | down [-steps N] | status | create <name>
+func runMigrateCommand(cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [-steps N] | status | create <name>")
+	}
+
+	// creating files is the only subcommand that works without a database
+	if args[0] != "create" {
+		err := database.ConnectDB(cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+		if err != nil {
+			return fmt.Errorf("database connection failed: %w", err)
+		}
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp()
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ Applied %d migrations", applied)
+		return nil
+
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to revert")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		reverted, err := database.MigrateDown(*steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ Reverted %d migrations", reverted)
+		return nil
+
+	case "status":
+		states, err := database.GetMigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", state.Version, state.Name, applied)
+		}
+		return nil
+
+	case "create":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: migrate create <name>")
+		}
+		paths, err := database.CreateMigration(database.MigrationsDir, args[1])
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			log.Printf("📝 Created %s", path)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
+
+// ensureSchema brings the schema up to date when MIGRATE_ON_START is set and
+// otherwise refuses to start against a database with pending migrations
+func ensureSchema(migrateOnStart bool) error {
+	if migrateOnStart {
+		applied, err := database.MigrateUp()
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			log.Printf("✅ Applied %d migrations", applied)
+		}
+		return nil
+	}
+
+	pending, err := database.PendingMigrations()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migrations are pending (next: %04d_%s); run `go run . migrate up` or set MIGRATE_ON_START=true",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
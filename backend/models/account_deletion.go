@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountDeletion records a soft-deleted account. The user row is anonymized straight away;
+// the original email and username are kept here so the owner can restore the account during
+// the grace period, after which the purge job clears them and removes unsold inventory and
+// unopened cases. Ledger history is kept.
+type AccountDeletion struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	OriginalEmail    string     `gorm:"index" json:"-"`
+	OriginalUsername string     `gorm:"index" json:"-"`
+	RequestedAt      time.Time  `gorm:"not null" json:"requested_at"`
+	PurgeAfter       time.Time  `gorm:"not null;index" json:"purge_after"`
+	RestoredAt       *time.Time `json:"restored_at,omitempty"`
+	PurgedAt         *time.Time `json:"purged_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate hook runs before creating a new account deletion
+func (d *AccountDeletion) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsPending checks if the account can still be restored
+func (d *AccountDeletion) IsPending() bool {
+	return d.RestoredAt == nil && d.PurgedAt == nil && time.Now().Before(d.PurgeAfter)
+}
+
+// ToJSON converts AccountDeletion to a JSON-compatible map
+func (d *AccountDeletion) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"requested_at": d.RequestedAt,
+		"purge_after":  d.PurgeAfter,
+		"restored_at":  d.RestoredAt,
+	}
+}
+
+// AnonymizedEmail is the placeholder address a deleted user's row holds
+func AnonymizedEmail(userID uuid.UUID) string {
+	return fmt.Sprintf("deleted-%s@deleted.invalid", userID)
+}
+
+// AnonymizedUsername is the placeholder username a deleted user's row holds
+func AnonymizedUsername(userID uuid.UUID) string {
+	return fmt.Sprintf("deleted-%s", userID)
+}
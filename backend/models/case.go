@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,21 +11,53 @@ import (
 // Case represents a case item in the database
 type Case struct {
 	ID          uuid.UUID 	`gorm:"type:uuid;primaryKey" json:"id"`
+	Slug        string      `gorm:"type:varchar(100);not null;uniqueIndex" json:"slug"` // stable key used by catalog files
 	Name        string    	`gorm:"not null" json:"name"`
-	Price  	    float64   	`gorm:"not null" json:"price"`
+	Price  	    Money     	`gorm:"type:bigint;not null" json:"price"`
 	ImageURL    string      `gorm:"not null" json:"image_url"`
 	Description string    	`gorm:"type:text" json:"description"`
 	IsActive	bool      	`gorm:"default:true" json:"is_active"`
+	BulkDiscounts []BulkDiscount `gorm:"type:jsonb;serializer:json" json:"bulk_discounts"`
+	StatTrakChance float64   `gorm:"not null;default:0.1" json:"stattrak_chance"`
+	IsSouvenirPackage bool   `gorm:"not null;default:false" json:"is_souvenir_package"`
 	CreatedAt   time.Time   `json:"created_at"`
 	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
+// BulkDiscount takes PercentOff off the whole order when buying at least MinQuantity cases
+type BulkDiscount struct {
+	MinQuantity int     `json:"min_quantity"`
+	PercentOff  float64 `json:"percent_off"`
+}
+
+// ValidateBulkDiscounts checks that tiers start at 2 cases, discount between 0 and 100 percent,
+// and don't repeat a quantity
+func ValidateBulkDiscounts(tiers []BulkDiscount) error {
+	seen := make(map[int]bool, len(tiers))
+	for _, tier := range tiers {
+		if tier.MinQuantity < 2 {
+			return fmt.Errorf("bulk discount min_quantity must be at least 2")
+		}
+		if tier.PercentOff <= 0 || tier.PercentOff >= 100 {
+			return fmt.Errorf("bulk discount percent_off must be between 0 and 100")
+		}
+		if seen[tier.MinQuantity] {
+			return fmt.Errorf("duplicate bulk discount tier for %d cases", tier.MinQuantity)
+		}
+		seen[tier.MinQuantity] = true
+	}
+	return nil
+}
+
 // BeforeCreate hook runs before creating a new skin
 func (c *Case) BeforeCreate(tx *gorm.DB) error {
 	// Generate a new UUID for the case
 	if c.ID == uuid.Nil {
 		c.ID = uuid.New()
 	}
+	if c.Slug == "" {
+		c.Slug = Slugify(c.Name)
+	}
 	return nil
 }
 
@@ -32,11 +65,15 @@ func (c *Case) BeforeCreate(tx *gorm.DB) error {
 func (c *Case) ToJSON() map[string]interface{} {
 	return map[string]interface{}{
 		"id":          c.ID,
+		"slug":        c.Slug,
 		"name":        c.Name,
 		"price":       c.Price,
 		"image_url":   c.ImageURL,
 		"description": c.Description,
 		"is_active":   c.IsActive,
+		"bulk_discounts": c.bulkDiscountsJSON(),
+		"stattrak_chance": c.StatTrakChance,
+		"is_souvenir_package": c.IsSouvenirPackage,
 		"created_at":  c.CreatedAt,
 		"updated_at":  c.UpdatedAt,
 	}
@@ -45,4 +82,32 @@ func (c *Case) ToJSON() map[string]interface{} {
 // CanBeOpened checks if the case is active and can be opened
 func (c *Case) CanBeOpened() bool {
 	return c.IsActive && c.Price > 0
-}
\ No newline at end of file
+}
+
+// DiscountFor returns the best bulk discount tier reached by quantity (zero value when none applies)
+func (c *Case) DiscountFor(quantity int) BulkDiscount {
+	var best BulkDiscount
+	for _, tier := range c.BulkDiscounts {
+		if quantity >= tier.MinQuantity && tier.PercentOff > best.PercentOff {
+			best = tier
+		}
+	}
+	return best
+}
+
+// PriceFor returns the undiscounted subtotal, the discount and the total for buying quantity cases
+func (c *Case) PriceFor(quantity int) (subtotal, discount, total Money) {
+	subtotal = c.Price * Money(quantity)
+	if tier := c.DiscountFor(quantity); tier.PercentOff > 0 {
+		discount = subtotal.MulFloat(tier.PercentOff / 100)
+	}
+	return subtotal, discount, subtotal - discount
+}
+
+// bulkDiscountsJSON returns the tiers, never nil so clients always get a list
+func (c *Case) bulkDiscountsJSON() []BulkDiscount {
+	if c.BulkDiscounts == nil {
+		return []BulkDiscount{}
+	}
+	return c.BulkDiscounts
+}
@@ -11,6 +11,7 @@ import (
 type CaseContent struct {
 	ID            uuid.UUID     `gorm:"type:uuid;primaryKey" json:"id"`
 	CaseID        uuid.UUID     `gorm:"type:uuid;not null;index" json:"case_id"`
+	DropTableID   *uuid.UUID    `gorm:"type:uuid;index" json:"drop_table_id,omitempty"`
 	SkinID        uuid.UUID     `gorm:"type:uuid;not null;index" json:"skin_id"`
 	DropChance    float64       `gorm:"not null" json:"drop_chance"`
 	CreatedAt     time.Time     `json:"created_at"`
@@ -37,6 +38,7 @@ func (cc *CaseContent) ToJSON() map[string]interface{} {
 	return map[string]interface{}{
 		"id":          cc.ID,
 		"case_id":    cc.CaseID,
+		"drop_table_id": cc.DropTableID,
 		"skin_id":    cc.SkinID,
 		"drop_chance": cc.DropChance,
 		"created_at":  cc.CreatedAt,
@@ -70,4 +72,14 @@ func (cc *CaseContent) GetDropPercentage() float64 {
 // IsRare checks this is a rare drop (less than 1% chance)
 func (cc *CaseContent) IsRare() bool {
 	return cc.DropChance < 0.01
-}
\ No newline at end of file
+}
+
+// ValidateCaseContents checks a drop table's contents can be served: it must not be empty
+// and its chances must sum to 1
+func ValidateCaseContents(contents []CaseContent) error {
+	chances := make([]float64, 0, len(contents))
+	for _, content := range contents {
+		chances = append(chances, content.DropChance)
+	}
+	return ValidateDropChances(chances)
+}
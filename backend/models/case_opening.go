@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CaseOpening records the provably fair inputs and outcome of a single case opening
+type CaseOpening struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CaseID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"case_id"`
+	DropTableID    *uuid.UUID `gorm:"type:uuid;index" json:"drop_table_id,omitempty"`
+	InventoryID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"inventory_id"`
+	FairnessSeedID uuid.UUID  `gorm:"type:uuid;not null;index" json:"fairness_seed_id"`
+	ServerSeedHash string     `gorm:"not null" json:"server_seed_hash"`
+	ClientSeed     string     `gorm:"not null" json:"client_seed"`
+	Nonce          int64      `gorm:"not null" json:"nonce"`
+	SkinRoll       float64    `gorm:"not null" json:"skin_roll"`
+	FloatRoll      float64    `gorm:"not null" json:"float_roll"`
+	FloatMethod    string     `gorm:"type:varchar(20);not null;default:'uniform'" json:"float_method"`
+	MinFloat       float64    `gorm:"not null;default:0" json:"min_float"`
+	MaxFloat       float64    `gorm:"not null;default:1" json:"max_float"`
+	StatTrakRoll   *float64   `json:"stattrak_roll"` // nil for openings made before StatTrak drops
+	StatTrakChance float64    `gorm:"not null;default:0" json:"stattrak_chance"`
+	IsStatTrak     bool       `gorm:"not null;default:false" json:"is_stattrak"`
+	IsSouvenir     bool       `gorm:"not null;default:false" json:"is_souvenir"`
+	PatternRoll    *float64   `json:"pattern_roll"` // nil for openings made before pattern seeds
+	PatternSeed    *int       `json:"pattern_seed"`
+	PatternTier    string     `gorm:"type:varchar(50)" json:"pattern_tier"`
+	SkinID         uuid.UUID  `gorm:"type:uuid;not null" json:"skin_id"`
+	Float          float64    `gorm:"not null" json:"float"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Relationships
+	User         User         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	FairnessSeed FairnessSeed `gorm:"foreignKey:FairnessSeedID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new case opening
+func (co *CaseOpening) BeforeCreate(tx *gorm.DB) error {
+	if co.ID == uuid.Nil {
+		co.ID = uuid.New()
+	}
+	return nil
+}
+
+// ToJSON converts CaseOpening to a JSON-compatible map
+func (co *CaseOpening) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"id":               co.ID,
+		"case_id":          co.CaseID,
+		"drop_table_id":    co.DropTableID,
+		"inventory_id":     co.InventoryID,
+		"fairness_seed_id": co.FairnessSeedID,
+		"server_seed_hash": co.ServerSeedHash,
+		"client_seed":      co.ClientSeed,
+		"nonce":            co.Nonce,
+		"skin_roll":        co.SkinRoll,
+		"float_roll":       co.FloatRoll,
+		"float_method":     co.FloatMethod,
+		"min_float":        co.MinFloat,
+		"max_float":        co.MaxFloat,
+		"stattrak_roll":    co.StatTrakRoll,
+		"stattrak_chance":  co.StatTrakChance,
+		"skin_id":          co.SkinID,
+		"float":            co.Float,
+		"is_stattrak":      co.IsStatTrak,
+		"is_souvenir":      co.IsSouvenir,
+		"pattern_roll":     co.PatternRoll,
+		"pattern_seed":     co.PatternSeed,
+		"pattern_tier":     co.PatternTier,
+		"created_at":       co.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Chat message roles, as sent to the language model
+const (
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+	ChatRoleTool      = "tool"
+)
+
+// ChatThread is a conversation between a user and the price-check assistant
+type ChatThread struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Title     string    `gorm:"type:varchar(120);not null" json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new chat thread
+func (t *ChatThread) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// ChatToolCall is a tool the assistant called while answering
+type ChatToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatMessage is one message of a thread. Tool calls and tool results are kept
+// so follow-up questions reach the model with the full context.
+type ChatMessage struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	ThreadID   uuid.UUID      `gorm:"type:uuid;not null;index:idx_chat_messages_thread_created,priority:1" json:"thread_id"`
+	Role       string         `gorm:"type:varchar(20);not null" json:"role"`
+	Content    string         `gorm:"type:text;not null" json:"content"`
+	ToolCalls  []ChatToolCall `gorm:"type:jsonb;serializer:json" json:"tool_calls,omitempty"`
+	ToolCallID string         `gorm:"type:varchar(100)" json:"tool_call_id,omitempty"`
+	CreatedAt  time.Time      `gorm:"index:idx_chat_messages_thread_created,priority:2" json:"created_at"`
+
+	// Relationships
+	Thread ChatThread `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new chat message
+func (m *ChatMessage) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// ChatUsage counts a user's assistant messages and model tokens per UTC day for quotas
+type ChatUsage struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Day       time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Messages  int       `gorm:"not null;default:0" json:"messages"`
+	Tokens    int       `gorm:"not null;default:0" json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DropChanceTolerance is how far a drop table's chances may be from summing to 1.0
+const DropChanceTolerance = 0.0001
+
+// DropTable is one published version of a case's contents.
+// Editing the odds publishes a new version; old versions are retired but kept so
+// past openings can still be verified against the odds they were rolled with.
+type DropTable struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	CaseID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_drop_tables_case_version" json:"case_id"`
+	Version     int        `gorm:"not null;uniqueIndex:idx_drop_tables_case_version" json:"version"`
+	Notes       string     `gorm:"type:text" json:"notes"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	PublishedAt time.Time  `gorm:"not null" json:"published_at"`
+	RetiredAt   *time.Time `json:"retired_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	Case     Case          `gorm:"foreignKey:CaseID;constraint:OnDelete:CASCADE" json:"-"`
+	Contents []CaseContent `gorm:"foreignKey:DropTableID" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new drop table
+func (dt *DropTable) BeforeCreate(tx *gorm.DB) error {
+	if dt.ID == uuid.Nil {
+		dt.ID = uuid.New()
+	}
+	if dt.PublishedAt.IsZero() {
+		dt.PublishedAt = time.Now()
+	}
+	return nil
+}
+
+// IsActive checks if this version is the one currently used for openings
+func (dt *DropTable) IsActive() bool {
+	return dt.RetiredAt == nil
+}
+
+// ToJSON converts DropTable to a JSON-compatible map
+func (dt *DropTable) ToJSON() map[string]interface{} {
+	response := map[string]interface{}{
+		"id":           dt.ID,
+		"case_id":      dt.CaseID,
+		"version":      dt.Version,
+		"notes":        dt.Notes,
+		"is_active":    dt.IsActive(),
+		"published_at": dt.PublishedAt,
+		"created_at":   dt.CreatedAt,
+	}
+	if dt.RetiredAt != nil {
+		response["retired_at"] = dt.RetiredAt
+	}
+	if dt.CreatedBy != nil {
+		response["created_by"] = dt.CreatedBy
+	}
+	return response
+}
+
+// ValidateDropChances checks every chance is in (0, 1] and that they sum to 1.0 within tolerance
+func ValidateDropChances(chances []float64) error {
+	if len(chances) == 0 {
+		return fmt.Errorf("drop table has no contents")
+	}
+
+	var total float64
+	for _, chance := range chances {
+		if chance <= 0 || chance > 1 {
+			return fmt.Errorf("drop chance %v must be greater than 0 and at most 1", chance)
+		}
+		total += chance
+	}
+
+	if math.Abs(total-1.0) > DropChanceTolerance {
+		return fmt.Errorf("drop chances sum to %.6f, expected 1.0 (±%v)", total, DropChanceTolerance)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FairnessSeed is a user's provably fair seed pair.
+// The server seed stays secret (only its hash is shown) until it is rotated out.
+type FairnessSeed struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_fairness_seeds_active_user,where:revealed_at IS NULL" json:"user_id"`
+	ServerSeed     string     `gorm:"not null" json:"-"`
+	ServerSeedHash string     `gorm:"not null;index" json:"server_seed_hash"`
+	ClientSeed     string     `gorm:"not null" json:"client_seed"`
+	Nonce          int64      `gorm:"not null;default:0" json:"nonce"`
+	RevealedAt     *time.Time `json:"revealed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new fairness seed
+func (fs *FairnessSeed) BeforeCreate(tx *gorm.DB) error {
+	if fs.ID == uuid.Nil {
+		fs.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsRevealed checks if the server seed has been rotated out and can be shown
+func (fs *FairnessSeed) IsRevealed() bool {
+	return fs.RevealedAt != nil
+}
+
+// ToJSON converts FairnessSeed to a JSON-compatible map.
+// The server seed is only included once it has been revealed.
+func (fs *FairnessSeed) ToJSON() map[string]interface{} {
+	response := map[string]interface{}{
+		"id":               fs.ID,
+		"server_seed_hash": fs.ServerSeedHash,
+		"client_seed":      fs.ClientSeed,
+		"nonce":            fs.Nonce,
+		"created_at":       fs.CreatedAt,
+	}
+
+	if fs.IsRevealed() {
+		response["server_seed"] = fs.ServerSeed
+		response["revealed_at"] = fs.RevealedAt
+	}
+	return response
+}
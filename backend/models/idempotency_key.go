@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyTTL is how long a stored response can be replayed
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyKey stores the first response for a user's Idempotency-Key so retries replay it
+type IdempotencyKey struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Key          string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	RequestHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	StatusCode   int        `gorm:"not null;default:0" json:"status_code"`
+	ResponseBody []byte     `gorm:"type:bytea" json:"-"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new idempotency key
+func (k *IdempotencyKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	if k.ExpiresAt.IsZero() {
+		k.ExpiresAt = time.Now().Add(IdempotencyKeyTTL)
+	}
+	return nil
+}
+
+// IsCompleted checks if the original request finished and its response was stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
@@ -15,7 +15,11 @@ type Inventory struct {
 	SkinID          uuid.UUID    `gorm:"type:uuid;not null;index" json:"skin_id"`
 	Float           float64      `gorm:"not null" json:"float"`
 	AcquiredFrom    string       `gorm:"not null" json:"acquired_from"`
-	Value           float64      `gorm:"not null" json:"value"`
+	Value           Money        `gorm:"type:bigint;not null" json:"value"`
+	IsStatTrak      bool         `gorm:"not null;default:false" json:"is_stattrak"`
+	IsSouvenir      bool         `gorm:"not null;default:false" json:"is_souvenir"`
+	PatternSeed     int          `gorm:"not null;default:0" json:"pattern_seed"`
+	PatternTier     string       `gorm:"type:varchar(50)" json:"pattern_tier"`
 	IsSold          bool         `gorm:"not null;default:false" json:"is_sold"`
 	SoldAt          *time.Time   `json:"sold_at"`
 	CreatedAt       time.Time    `json:"created_at"`
@@ -46,13 +50,22 @@ func (i *Inventory) ToJSON() map[string] interface{} {
 		"user_id":      i.UserID,
 		"skin_id":      i.SkinID,
 		"float":        i.Float,
+		"condition":    i.GetCondition(),
 		"acquired_from": i.AcquiredFrom,
 		"value":        i.Value,
+		"is_stattrak":  i.IsStatTrak,
+		"is_souvenir":  i.IsSouvenir,
+		"pattern_seed": i.PatternSeed,
 		"is_sold":      i.IsSold,
 		"created_at":   i.CreatedAt,
 		"updated_at":   i.UpdatedAt,
 	}
 
+	// only include pattern_tier for special patterns
+	if i.PatternTier != "" {
+		response["pattern_tier"] = i.PatternTier
+	}
+
 	// only include sold_at if the item has been sold
 	if i.SoldAt != nil {
 		response["sold_at"] = i.SoldAt
@@ -65,22 +78,23 @@ func (i *Inventory) ToJSON() map[string] interface{} {
 func (i *Inventory) ToJSONWithSkin() map[string] interface{} {
 	response := i.ToJSON()
 	response["skin"] = i.Skin.ToJSON()
+	response["name"] = i.DisplayName()
 	return response
 }
 
 // GetCondition returns the war condition base on the float value
 func (i *Inventory) GetCondition() string {
-	if i.Float < 0.07 {
-		return "Factory New"
-	} else if i.Float < 0.15 {
-		return "Minimal Wear"
-	} else if i.Float < 0.38 {
-		return "Field-Tested"
-	} else if i.Float < 0.45 {
-		return "Well-Worn"
-	} else {
-		return "Battle-Scarred"
-	}
+	return ConditionForFloat(i.Float)
+}
+
+// ValueMultiplier returns the premium the item's variant adds to the skin's condition price
+func (i *Inventory) ValueMultiplier() float64 {
+	return VariantMultiplier(i.IsStatTrak, i.IsSouvenir)
+}
+
+// DisplayName returns the skin name with the item's variant prefix (requires Skin to be loaded)
+func (i *Inventory) DisplayName() string {
+	return VariantName(i.Skin.Name, i.IsStatTrak, i.IsSouvenir)
 }
 
 // CanBeSold checks if the inventory item can be sold
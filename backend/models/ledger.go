@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LedgerAccountKind separates user wallets from the house's system accounts
+type LedgerAccountKind string
+
+const (
+	LedgerAccountKindUserWallet LedgerAccountKind = "user_wallet"
+	LedgerAccountKindSystem     LedgerAccountKind = "system"
+)
+
+// LedgerAccount is an account in the double-entry ledger.
+// A wallet's balance is the sum of its entries.
+type LedgerAccount struct {
+	ID        uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	Code      string            `gorm:"type:varchar(100);uniqueIndex;not null" json:"code"`
+	Name      string            `gorm:"not null" json:"name"`
+	Kind      LedgerAccountKind `gorm:"type:varchar(20);not null;index" json:"kind"`
+	UserID    *uuid.UUID        `gorm:"type:uuid;uniqueIndex" json:"user_id,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:RESTRICT" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new ledger account
+func (a *LedgerAccount) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// LedgerEntry is one side of a posting. The entries of a transaction always sum to zero;
+// a positive amount increases the account, a negative amount decreases it.
+type LedgerEntry struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;index" json:"transaction_id"`
+	AccountID     uuid.UUID `gorm:"type:uuid;not null;index" json:"account_id"`
+	Amount        Money     `gorm:"type:bigint;not null" json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relationships
+	Transaction Transaction   `gorm:"foreignKey:TransactionID;constraint:OnDelete:RESTRICT" json:"-"`
+	Account     LedgerAccount `gorm:"foreignKey:AccountID;constraint:OnDelete:RESTRICT" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new ledger entry
+func (e *LedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of Case Bucks stored as integer cents.
+// It is persisted as a bigint column and encoded to JSON as a decimal number (e.g. 12.34).
+type Money int64
+
+const (
+	Cent     Money = 1
+	CaseBuck Money = 100
+)
+
+// MoneyFromFloat converts a decimal amount to Money.
+// Every float-derived amount goes through here so rounding is the same everywhere:
+// round to the nearest cent, halves away from zero.
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// Float64 returns the amount as a decimal number (for display and ratios only)
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// MulFloat scales the amount by a factor, rounding the result to the nearest cent
+func (m Money) MulFloat(factor float64) Money {
+	return MoneyFromFloat(m.Float64() * factor)
+}
+
+// Abs returns the absolute value of the amount
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// String formats the amount with exactly two decimals (e.g. -2.50)
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes the amount as a decimal JSON number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a decimal JSON number or a quoted decimal string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "null" || raw == "" {
+		*m = 0
+		return nil
+	}
+
+	amount, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fmt.Errorf("invalid money amount %q: %w", raw, err)
+	}
+	*m = MoneyFromFloat(amount)
+	return nil
+}
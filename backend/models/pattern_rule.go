@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxPatternSeed is the highest pattern seed a drop can roll (seeds run 0-999 as in CS2)
+const MaxPatternSeed = 999
+
+// PatternRule names a range of pattern seeds of a skin (e.g. "Blue Gem", "Phase 2")
+// and the value multiplier those seeds carry
+type PatternRule struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SkinID     uuid.UUID `gorm:"type:uuid;not null;index" json:"skin_id"`
+	SeedMin    int       `gorm:"not null" json:"seed_min"`
+	SeedMax    int       `gorm:"not null" json:"seed_max"`
+	Tier       string    `gorm:"type:varchar(50);not null" json:"tier"`
+	Multiplier float64   `gorm:"not null;default:1" json:"multiplier"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relationships
+	Skin Skin `gorm:"foreignKey:SkinID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new pattern rule
+func (r *PatternRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// ToJSON converts PatternRule to a JSON-compatible map
+func (r *PatternRule) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         r.ID,
+		"skin_id":    r.SkinID,
+		"seed_min":   r.SeedMin,
+		"seed_max":   r.SeedMax,
+		"tier":       r.Tier,
+		"multiplier": r.Multiplier,
+		"created_at": r.CreatedAt,
+		"updated_at": r.UpdatedAt,
+	}
+}
+
+// Validate checks the seed range and multiplier of the rule
+func (r *PatternRule) Validate() error {
+	if r.SeedMin < 0 || r.SeedMax > MaxPatternSeed {
+		return fmt.Errorf("pattern seeds must be between 0 and %d", MaxPatternSeed)
+	}
+	if r.SeedMin > r.SeedMax {
+		return fmt.Errorf("seed_min cannot be greater than seed_max")
+	}
+	if r.Tier == "" {
+		return fmt.Errorf("tier is required")
+	}
+	if r.Multiplier <= 0 {
+		return fmt.Errorf("multiplier must be greater than 0")
+	}
+	return nil
+}
+
+// Matches checks if a pattern seed falls within the rule's range
+func (r *PatternRule) Matches(seed int) bool {
+	return seed >= r.SeedMin && seed <= r.SeedMax
+}
+
+// Overlaps checks if two rules share any pattern seed
+func (r *PatternRule) Overlaps(other PatternRule) bool {
+	return r.SeedMin <= other.SeedMax && other.SeedMin <= r.SeedMax
+}
+
+// MatchPatternRule returns the rule covering the seed, or nil when the pattern is unremarkable.
+// Rules of a skin never overlap, so at most one can match.
+func MatchPatternRule(rules []PatternRule, seed int) *PatternRule {
+	for i := range rules {
+		if rules[i].Matches(seed) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
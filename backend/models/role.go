@@ -0,0 +1,63 @@
+package models
+
+// Role is a user's coarse access level
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Permission is a fine-grained capability checked by middleware.RequirePermission
+type Permission string
+
+const (
+	PermissionViewUsers     Permission = "users:view"
+	PermissionManageUsers   Permission = "users:manage"
+	PermissionManageRoles   Permission = "roles:manage"
+	PermissionManageCatalog Permission = "catalog:manage"
+	PermissionManageLedger  Permission = "ledger:manage"
+	PermissionViewOpenings  Permission = "openings:view"
+)
+
+// rolePermissions lists the permissions every user with a role gets
+var rolePermissions = map[Role][]Permission{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionViewUsers,
+		PermissionViewOpenings,
+	},
+	RoleAdmin: {
+		PermissionViewUsers,
+		PermissionManageUsers,
+		PermissionManageRoles,
+		PermissionManageCatalog,
+		PermissionManageLedger,
+		PermissionViewOpenings,
+	},
+}
+
+// Roles lists every role from least to most privileged
+var Roles = []Role{RoleUser, RoleModerator, RoleAdmin}
+
+// IsValid checks if the role is one of the known roles
+func (r Role) IsValid() bool {
+	_, exists := rolePermissions[r]
+	return exists
+}
+
+// Permissions returns the permissions granted by the role
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// IsValidPermission checks if the permission is one of the known permissions
+func IsValidPermission(permission Permission) bool {
+	for _, granted := range rolePermissions[RoleAdmin] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
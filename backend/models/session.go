@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is a logged-in device. It holds the hash of the current refresh token,
+// which is rotated on every refresh; the previous hash is kept to detect token reuse.
+type Session struct {
+	ID                       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID                   uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	RefreshTokenHash         string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	PreviousRefreshTokenHash *string    `gorm:"type:varchar(64);index" json:"-"`
+	UserAgent                string     `gorm:"type:text" json:"user_agent"`
+	IPAddress                string     `gorm:"type:varchar(64)" json:"ip_address"`
+	ExpiresAt                time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt               time.Time  `gorm:"not null" json:"last_used_at"`
+	RevokedAt                *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason            string     `gorm:"type:varchar(50)" json:"revoked_reason,omitempty"`
+	CreatedAt                time.Time  `json:"created_at"`
+	UpdatedAt                time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// Reasons a session was revoked
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedLogoutAll      = "logout_all"
+	SessionRevokedByUser         = "revoked_by_user"
+	SessionRevokedTokenReuse     = "refresh_token_reuse"
+	SessionRevokedPasswordReset  = "password_reset"
+	SessionRevokedPasswordChange = "password_change"
+	SessionRevokedAccountDeleted = "account_deleted"
+)
+
+// BeforeCreate hook runs before creating a new session
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.LastUsedAt.IsZero() {
+		s.LastUsedAt = time.Now()
+	}
+	return nil
+}
+
+// IsActive checks if the session can still be used to refresh tokens
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// ToJSON converts Session to a JSON-compatible map
+func (s *Session) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"id":           s.ID,
+		"user_agent":   s.UserAgent,
+		"ip_address":   s.IPAddress,
+		"expires_at":   s.ExpiresAt,
+		"last_used_at": s.LastUsedAt,
+		"created_at":   s.CreatedAt,
+		"is_active":    s.IsActive(),
+	}
+}
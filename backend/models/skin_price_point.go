@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SkinPricePoint is the market price of a skin in one wear condition at a point in time.
+// Prices are stored in cents of the provider's currency (one USD is one Case Buck).
+type SkinPricePoint struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SkinID         uuid.UUID `gorm:"type:uuid;not null;index:idx_skin_price_points_skin_recorded,priority:1" json:"skin_id"`
+	Condition      string    `gorm:"type:varchar(20);not null" json:"condition"`
+	MarketHashName string    `gorm:"type:varchar(255);not null" json:"market_hash_name"`
+	Price          Money     `gorm:"type:bigint;not null" json:"price"`
+	Quantity       int       `gorm:"not null;default:0" json:"quantity"`
+	Currency       string    `gorm:"type:varchar(3);not null" json:"currency"`
+	Provider       string    `gorm:"type:varchar(50);not null" json:"provider"`
+	RecordedAt     time.Time `gorm:"not null;index:idx_skin_price_points_skin_recorded,priority:2" json:"recorded_at"`
+
+	// Relationships
+	Skin Skin `gorm:"foreignKey:SkinID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new price point
+func (p *SkinPricePoint) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
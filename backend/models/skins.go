@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,14 +13,24 @@ import (
 // Skin represents a skin item in the database
 type Skin struct {
 	ID    		uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	Slug        string       `gorm:"type:varchar(100);not null;uniqueIndex" json:"slug"` // stable key used by catalog files
 	Name  		string       `gorm:"not null" json:"name"`
 	WeaponType  string	     `gorm:"not null" json:"weapon_type"`	
 	Rarity      string       `gorm:"not null" json:"rarity"`
-	Float       float64      `gorm:"default:0.5" json:"float"`
+	MinFloat    float64      `gorm:"not null;default:0" json:"min_float"`
+	MaxFloat    float64      `gorm:"not null;default:1" json:"max_float"`
 	ImageURL    string       `gorm:"not null" json:"image_url"`
-	MinValue    float64      `gorm:"not null" json:"min_value"`
-	MaxValue    float64      `gorm:"not null" json:"max_value"`
+	MinValue    Money        `gorm:"type:bigint;not null" json:"min_value"`
+	MaxValue    Money        `gorm:"type:bigint;not null" json:"max_value"`
+
+	// price points per wear condition; MinValue and MaxValue are derived from these
+	FactoryNewPrice    Money `gorm:"type:bigint;not null;default:0" json:"factory_new_price"`
+	MinimalWearPrice   Money `gorm:"type:bigint;not null;default:0" json:"minimal_wear_price"`
+	FieldTestedPrice   Money `gorm:"type:bigint;not null;default:0" json:"field_tested_price"`
+	WellWornPrice      Money `gorm:"type:bigint;not null;default:0" json:"well_worn_price"`
+	BattleScarredPrice Money `gorm:"type:bigint;not null;default:0" json:"battle_scarred_price"`
 	Description string       `gorm:"type:text" json:"description"`
+	IsActive    bool         `gorm:"default:true" json:"is_active"`
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
 }
@@ -29,6 +41,21 @@ func (s *Skin) BeforeCreate(tx *gorm.DB) error {
 	if s.ID == uuid.Nil {
 		s.ID = uuid.New()
 	}
+	if s.Slug == "" {
+		s.Slug = Slugify(s.Name)
+	}
+	return nil
+}
+
+// BeforeSave is a GORM hook that keeps the float cap and value range consistent with the condition prices
+func (s *Skin) BeforeSave(tx *gorm.DB) error {
+	if s.MaxFloat == 0 {
+		s.MaxFloat = 1
+	}
+	if !s.HasConditionPrices() && s.MaxValue > 0 {
+		s.DeriveConditionPrices(s.MinValue, s.MaxValue)
+	}
+	s.SyncValueRange()
 	return nil
 }
 
@@ -36,24 +63,194 @@ func (s *Skin) BeforeCreate(tx *gorm.DB) error {
 func (s *Skin) ToJSON() map[string]interface{} {
 	return map[string]interface{}{
 		"id":          s.ID,
+		"slug":        s.Slug,
 		"name":        s.Name,
 		"weapon_type": s.WeaponType,
 		"rarity":      s.Rarity,
-		"float":       s.Float,
+		"min_float":   s.MinFloat,
+		"max_float":   s.MaxFloat,
 		"image_url":   s.ImageURL,
 		"min_value":   s.MinValue,
 		"max_value":   s.MaxValue,
+		"prices":      s.ConditionPrices(),
+		"conditions":  s.PossibleConditions(),
 		"description": s.Description,
+		"is_active":   s.IsActive,
 		"created_at":  s.CreatedAt,
 		"updated_at":  s.UpdatedAt,
 	}
 }
 
 // GetAverageValue calculates the average value of the skin based on its min and max values
-func (s *Skin) GetAverageValue() float64 {
+func (s *Skin) GetAverageValue() Money {
 	return (s.MinValue + s.MaxValue) / 2
 }
 
+// ValidateFloatRange checks that a float cap lies within 0-1 and is not empty
+func ValidateFloatRange(minFloat, maxFloat float64) error {
+	if minFloat < 0 || maxFloat > 1 {
+		return errors.New("min_float and max_float must be between 0 and 1")
+	}
+	if minFloat >= maxFloat {
+		return errors.New("min_float must be less than max_float")
+	}
+	return nil
+}
+
+// IsValidCondition checks if the condition is one of the known wear conditions
+func IsValidCondition(condition string) bool {
+	for _, known := range Conditions {
+		if known == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionPrice returns a pointer to the price field of a wear condition
+func (s *Skin) conditionPrice(condition string) *Money {
+	switch condition {
+	case ConditionFactoryNew:
+		return &s.FactoryNewPrice
+	case ConditionMinimalWear:
+		return &s.MinimalWearPrice
+	case ConditionFieldTested:
+		return &s.FieldTestedPrice
+	case ConditionWellWorn:
+		return &s.WellWornPrice
+	case ConditionBattleScarred:
+		return &s.BattleScarredPrice
+	}
+	return nil
+}
+
+// SetConditionPrice sets the price of a wear condition, ignoring unknown conditions
+func (s *Skin) SetConditionPrice(condition string, price Money) {
+	if field := s.conditionPrice(condition); field != nil {
+		*field = price
+	}
+}
+
+// ConditionPrices returns the price of every wear condition, keyed by condition
+func (s *Skin) ConditionPrices() map[string]Money {
+	prices := make(map[string]Money, len(Conditions))
+	for _, condition := range Conditions {
+		prices[condition] = *s.conditionPrice(condition)
+	}
+	return prices
+}
+
+// HasConditionPrices reports whether any wear condition has a price set
+func (s *Skin) HasConditionPrices() bool {
+	for _, condition := range Conditions {
+		if *s.conditionPrice(condition) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DeriveConditionPrices spreads a single value range across the wear conditions the float cap
+// can produce, pricing each condition at the midpoint of its wear bucket (better wear is worth more).
+func (s *Skin) DeriveConditionPrices(minValue, maxValue Money) {
+	span := s.MaxFloat - s.MinFloat
+	for _, bucket := range WearBuckets {
+		low := math.Max(bucket.MinFloat, s.MinFloat)
+		high := math.Min(bucket.MaxFloat, s.MaxFloat)
+
+		position := 1.0
+		if span > 0 {
+			position = ((low+high)/2 - s.MinFloat) / span
+		}
+		position = math.Min(math.Max(position, 0), 1)
+		s.SetConditionPrice(bucket.Condition, minValue+(maxValue-minValue).MulFloat(1-position))
+	}
+}
+
+// PossibleConditions lists the wear conditions the skin's float cap can produce
+func (s *Skin) PossibleConditions() []string {
+	conditions := []string{}
+	for _, bucket := range WearBuckets {
+		if bucket.MinFloat < s.MaxFloat && bucket.MaxFloat > s.MinFloat {
+			conditions = append(conditions, bucket.Condition)
+		}
+	}
+	return conditions
+}
+
+// PriceForCondition returns the price of a wear condition.
+// A condition without a price falls back to the nearest condition that has one.
+func (s *Skin) PriceForCondition(condition string) Money {
+	index := -1
+	for i, known := range Conditions {
+		if known == condition {
+			index = i
+		}
+	}
+	if index < 0 {
+		return 0
+	}
+
+	for distance := 0; distance < len(Conditions); distance++ {
+		// prefer the better condition on a tie so a missing price never undervalues a drop
+		for _, i := range []int{index - distance, index + distance} {
+			if i < 0 || i >= len(Conditions) {
+				continue
+			}
+			if price := *s.conditionPrice(Conditions[i]); price > 0 {
+				return price
+			}
+		}
+	}
+	return 0
+}
+
+// ValueForFloat returns the skin's value at the given wear float
+func (s *Skin) ValueForFloat(f float64) Money {
+	return s.PriceForCondition(ConditionForFloat(f))
+}
+
+// SyncValueRange derives MinValue and MaxValue from the conditions the float cap can produce
+func (s *Skin) SyncValueRange() {
+	if !s.HasConditionPrices() {
+		return
+	}
+
+	first := true
+	for _, condition := range s.PossibleConditions() {
+		price := s.PriceForCondition(condition)
+		if first || price < s.MinValue {
+			s.MinValue = price
+		}
+		if first || price > s.MaxValue {
+			s.MaxValue = price
+		}
+		first = false
+	}
+}
+
+// Rarities lists every rarity a skin can have, from most to least common
+var Rarities = []string{
+	"Consumer Grade",
+	"Industrial Grade",
+	"Mil-Spec",
+	"Restricted",
+	"Classified",
+	"Covert",
+	"Rare Special",
+	"Exceedingly Rare",
+}
+
+// IsValidRarity checks if the rarity is one of the known skin rarities
+func IsValidRarity(rarity string) bool {
+	for _, known := range Rarities {
+		if known == rarity {
+			return true
+		}
+	}
+	return false
+}
+
 //GetRarityColor returns a color code based on the skin's rarity
 func (s *Skin) GetRarityColor() string {
 	rarityColors := map[string]string{
@@ -63,6 +260,7 @@ func (s *Skin) GetRarityColor() string {
 		"Restricted": "#8847FF",
 		"Classified": "#D32CE6",
 		"Covert": "#EB4B4B",
+		"Rare Special": "#FFD700",
 		"Exceedingly Rare": "#FFD700",
 	}
 
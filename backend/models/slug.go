@@ -0,0 +1,36 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxSlugLength is the longest slug a skin or case can have
+const MaxSlugLength = 100
+
+var (
+	slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+	slugPattern    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// Slugify derives a slug from a display name, e.g. "★ Karambit | Fade" becomes "karambit-fade".
+// Migration 0007 backfills existing rows with the same rule.
+func Slugify(name string) string {
+	slug := strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	return slug
+}
+
+// ValidateSlug checks that a slug is lowercase words of letters and digits joined by hyphens
+func ValidateSlug(slug string) error {
+	if len(slug) > MaxSlugLength {
+		return fmt.Errorf("slug must be at most %d characters", MaxSlugLength)
+	}
+	if !slugPattern.MatchString(slug) {
+		return fmt.Errorf("slug %q must be lowercase letters and digits separated by hyphens", slug)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ const (
 	TransactionTypeDailyLogin    TransactionType = "daily_login"
 	TransactionTypeRegistration  TransactionType = "registration"
 	TransactionTypeRefund        TransactionType = "refund"
+	TransactionTypeAdjustment    TransactionType = "adjustment"
 )
 
 // Transaction represents a CaseBucks transaction
@@ -27,11 +28,12 @@ type Transaction struct {
 	ID             uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
 	UserID         uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
 	Type           TransactionType  `gorm:"type:varchar(50);not null" json:"type"`
-	Amount         float64          `gorm:"not null" json:"amount"`
-	BalanceBefore  float64          `gorm:"not null" json:"balance_before"`
-	BalanceAfter   float64          `gorm:"not null" json:"balance_after"`
+	Amount         Money            `gorm:"type:bigint;not null" json:"amount"`
+	BalanceBefore  Money            `gorm:"type:bigint;not null" json:"balance_before"`
+	BalanceAfter   Money            `gorm:"type:bigint;not null" json:"balance_after"`
 	Description    string           `gorm:"type:text" json:"description"`
 	ReferenceID    *uuid.UUID       `gorm:"type:uuid;index" json:"reference_id,omitempty"`
+	PatternTier    string           `gorm:"type:varchar(50)" json:"pattern_tier,omitempty"`
 	CreatedAt      time.Time        `json:"created_at"`
 	UpdatedAt      time.Time        `json:"updated_at"`
 
@@ -68,6 +70,11 @@ func (t *Transaction) ToJSON() map[string]interface{} {
 	if t.ReferenceID != nil {
 		response["reference_id"] = t.ReferenceID
 	}
+
+	// Only include pattern_tier for items with a special pattern
+	if t.PatternTier != "" {
+		response["pattern_tier"] = t.PatternTier
+	}
 	return response
 }
 
@@ -82,9 +89,6 @@ func (t *Transaction) IsCredit() bool {
 }
 
 // GetAbsoluteAmount returns the absolute value of the transaction amount 
-func (t *Transaction) GetAbsoluteAmount() float64 {
-	if t.Amount < 0 {
-		return -t.Amount
-	}
-	return t.Amount
+func (t *Transaction) GetAbsoluteAmount() Money {
+	return t.Amount.Abs()
 }
\ No newline at end of file
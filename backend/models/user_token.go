@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TokenPurpose says what a single-use emailed token can be redeemed for
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeTwoFactorLogin    TokenPurpose = "two_factor_login" // issued after the password step of a 2FA login
+)
+
+// How long emailed tokens stay valid
+const (
+	EmailVerificationTokenTTL = 48 * time.Hour
+	PasswordResetTokenTTL     = time.Hour
+	TwoFactorChallengeTTL     = 5 * time.Minute
+)
+
+// MaxTwoFactorAttempts is how many wrong codes a login challenge accepts before it is burned,
+// and how many in a row sensitive actions accept before they are locked for TwoFactorLockout
+const MaxTwoFactorAttempts = 5
+
+// UserToken is a single-use token sent to a user by email. Only its hash is stored.
+type UserToken struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID    `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   TokenPurpose `gorm:"type:varchar(30);not null" json:"purpose"`
+	TokenHash string       `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Email     string       `gorm:"not null" json:"email"` // address the token was sent to
+	ExpiresAt time.Time    `gorm:"not null" json:"expires_at"`
+	Attempts  int          `gorm:"not null;default:0" json:"attempts"` // failed redemptions so far
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook runs before creating a new user token
+func (t *UserToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsUsable checks if the token has not been redeemed and has not expired
+func (t *UserToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
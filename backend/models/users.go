@@ -8,14 +8,39 @@ import (
 	"gorm.io/gorm"
 )
 
+// StartingCasebucks is the registration bonus every new account receives
+const StartingCasebucks = 100 * CaseBuck
+
+// Progressive login lockout: after LoginLockoutThreshold failed passwords in a row the account
+// is locked for LoginLockoutBase, doubling with every further failure up to LoginLockoutMax
+const (
+	LoginLockoutThreshold = 5
+	LoginLockoutBase      = time.Minute
+	LoginLockoutMax       = time.Hour
+)
+
+// TwoFactorLockout is how long sensitive actions refuse 2FA codes after MaxTwoFactorAttempts wrong ones in a row
+const TwoFactorLockout = 15 * time.Minute
+
 //This will be the user model for the application
 type User struct {
 	ID			uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
 	Email   	string         `gorm:"uniqueIndex;not null" json:"email"`
 	Username 	string        `gorm:"uniqueIndex;not null" json:"username"`
 	Password    string        `gorm:"not null" json:"-"`
-	Casebucks   float64       `gorm:"default:0" json:"casebucks"`
+	Casebucks   Money         `gorm:"type:bigint;default:0" json:"casebucks"`
+	Role        Role          `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
+	Permissions []Permission  `gorm:"type:jsonb;serializer:json" json:"permissions"` // granted on top of the role
+	TokenVersion int          `gorm:"not null;default:0" json:"-"` // bumped to invalidate every issued access token
+	EmailVerifiedAt *time.Time   `json:"email_verified_at,omitempty"`
+	TOTPSecret  string           `gorm:"type:varchar(64)" json:"-"` // set at enrollment, active once TwoFactorEnabledAt is set
+	TOTPLastUsedStep int64       `gorm:"not null;default:0" json:"-"` // stops a TOTP code being used twice
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at,omitempty"`
 	LastDailyRewardAt *time.Time `json:"last_daily_reward_at,omitempty"`
+	FailedLoginCount int         `gorm:"not null;default:0" json:"-"` // failed passwords since the last successful login
+	LockedUntil *time.Time       `json:"-"`
+	FailedTwoFactorCount int     `gorm:"not null;default:0" json:"-"` // wrong codes on sensitive actions since the last correct one
+	TwoFactorLockedUntil *time.Time `json:"-"`
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -30,9 +55,8 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == uuid.Nil {
 		u.ID = uuid.New()
 	}
-	// Users start with 100 CBs
-	if u.Casebucks == 0 {
-		u.Casebucks = 100
+	if u.Role == "" {
+		u.Role = RoleUser
 	}
 	return nil
 }
@@ -63,8 +87,73 @@ func (u *User) ToJSON() map[string]interface{} {
 		"email":      u.Email,
 		"username":   u.Username,
 		"casebucks":  u.Casebucks,
+		"role":       u.Role,
+		"permissions": u.EffectivePermissions(),
+		"email_verified": u.IsEmailVerified(),
+		"email_verified_at": u.EmailVerifiedAt,
+		"two_factor_enabled": u.IsTwoFactorEnabled(),
 		"last_daily_reward_at": u.LastDailyRewardAt,
 		"created_at": u.CreatedAt,
 		"updated_at": u.UpdatedAt,
 	}
 }
+
+// IsEmailVerified checks if the user has confirmed their current email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsTwoFactorLocked checks if 2FA codes for sensitive actions are currently refused after too many wrong ones
+func (u *User) IsTwoFactorLocked() bool {
+	return u.TwoFactorLockedUntil != nil && time.Now().Before(*u.TwoFactorLockedUntil)
+}
+
+// IsLocked checks if logins are currently blocked after too many failed passwords
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
+// LockoutDuration returns how long to lock the account after failedLogins failures in a row
+func LockoutDuration(failedLogins int) time.Duration {
+	if failedLogins < LoginLockoutThreshold {
+		return 0
+	}
+	duration := LoginLockoutBase
+	for i := LoginLockoutThreshold; i < failedLogins && duration < LoginLockoutMax; i++ {
+		duration *= 2
+	}
+	if duration > LoginLockoutMax {
+		duration = LoginLockoutMax
+	}
+	return duration
+}
+
+// IsTwoFactorEnabled checks if the user has confirmed a TOTP authenticator
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil && u.TOTPSecret != ""
+}
+
+// EffectivePermissions returns the role's permissions plus any individually granted ones
+func (u *User) EffectivePermissions() []Permission {
+	permissions := make([]Permission, 0, len(u.Role.Permissions())+len(u.Permissions))
+	seen := make(map[Permission]bool)
+	for _, group := range [][]Permission{u.Role.Permissions(), u.Permissions} {
+		for _, permission := range group {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+// HasPermission checks if the user's role or individual grants include the permission
+func (u *User) HasPermission(permission Permission) bool {
+	for _, granted := range u.EffectivePermissions() {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultStatTrakChance is how often a case drop is StatTrak unless the case says otherwise
+const DefaultStatTrakChance = 0.10
+
+// Value multipliers applied on top of the condition price for item variants
+const (
+	StatTrakValueMultiplier = 1.5
+	SouvenirValueMultiplier = 1.25
+)
+
+// ValidateStatTrakChance checks that a StatTrak probability lies within 0-1
+func ValidateStatTrakChance(chance float64) error {
+	if chance < 0 || chance > 1 {
+		return fmt.Errorf("stattrak_chance must be between 0 and 1")
+	}
+	return nil
+}
+
+// VariantMultiplier returns the value multiplier for an item's variant attributes
+func VariantMultiplier(statTrak, souvenir bool) float64 {
+	multiplier := 1.0
+	if statTrak {
+		multiplier *= StatTrakValueMultiplier
+	}
+	if souvenir {
+		multiplier *= SouvenirValueMultiplier
+	}
+	return multiplier
+}
+
+// VariantName prefixes a skin name with its variant, keeping the ★ of knives and gloves in front
+func VariantName(name string, statTrak, souvenir bool) string {
+	prefix := ""
+	if statTrak {
+		prefix = "StatTrak™ "
+	} else if souvenir {
+		prefix = "Souvenir "
+	}
+	if prefix == "" {
+		return name
+	}
+
+	if rest, ok := strings.CutPrefix(name, "★ "); ok {
+		return "★ " + prefix + rest
+	}
+	return prefix + name
+}
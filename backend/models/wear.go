@@ -0,0 +1,69 @@
+package models
+
+// Wear conditions, from best to worst
+const (
+	ConditionFactoryNew    = "Factory New"
+	ConditionMinimalWear   = "Minimal Wear"
+	ConditionFieldTested   = "Field-Tested"
+	ConditionWellWorn      = "Well-Worn"
+	ConditionBattleScarred = "Battle-Scarred"
+)
+
+// WearBucket is the float range of a wear condition and how often a drop lands in it
+type WearBucket struct {
+	Condition string
+	MinFloat  float64
+	MaxFloat  float64
+	Weight    float64
+}
+
+// WearBuckets follows the CS2 wear distribution. Weights sum to 1 and buckets are
+// ordered by float so a single roll can be mapped across them.
+var WearBuckets = []WearBucket{
+	{Condition: ConditionFactoryNew, MinFloat: 0.00, MaxFloat: 0.07, Weight: 0.03},
+	{Condition: ConditionMinimalWear, MinFloat: 0.07, MaxFloat: 0.15, Weight: 0.24},
+	{Condition: ConditionFieldTested, MinFloat: 0.15, MaxFloat: 0.38, Weight: 0.33},
+	{Condition: ConditionWellWorn, MinFloat: 0.38, MaxFloat: 0.45, Weight: 0.24},
+	{Condition: ConditionBattleScarred, MinFloat: 0.45, MaxFloat: 1.00, Weight: 0.16},
+}
+
+// Conditions lists every wear condition, from best to worst
+var Conditions = []string{
+	ConditionFactoryNew,
+	ConditionMinimalWear,
+	ConditionFieldTested,
+	ConditionWellWorn,
+	ConditionBattleScarred,
+}
+
+// ConditionForFloat returns the wear condition a float falls into
+func ConditionForFloat(f float64) string {
+	for _, bucket := range WearBuckets[:len(WearBuckets)-1] {
+		if f < bucket.MaxFloat {
+			return bucket.Condition
+		}
+	}
+	return ConditionBattleScarred
+}
+
+// WearFromRoll maps a roll in [0, 1) onto the wear buckets: the roll picks a bucket
+// by weight and the remainder of the roll picks a float uniformly inside it.
+func WearFromRoll(roll float64) float64 {
+	var cumulative float64
+	for _, bucket := range WearBuckets {
+		if roll < cumulative+bucket.Weight {
+			position := (roll - cumulative) / bucket.Weight
+			return bucket.MinFloat + position*(bucket.MaxFloat-bucket.MinFloat)
+		}
+		cumulative += bucket.Weight
+	}
+
+	// only reachable through float rounding of the weights
+	return WearBuckets[len(WearBuckets)-1].MaxFloat
+}
+
+// FloatFromRoll draws a wear float from the buckets and scales it into a skin's float cap,
+// the same way CS2 remaps a generic wear value onto a skin's min/max float.
+func FloatFromRoll(roll, minFloat, maxFloat float64) float64 {
+	return minFloat + WearFromRoll(roll)*(maxFloat-minFloat)
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWearBucketWeightsSumToOne(t *testing.T) {
+	var total float64
+	for i, bucket := range WearBuckets {
+		total += bucket.Weight
+		if i > 0 && bucket.MinFloat != WearBuckets[i-1].MaxFloat {
+			t.Errorf("bucket %s starts at %v, previous ends at %v", bucket.Condition, bucket.MinFloat, WearBuckets[i-1].MaxFloat)
+		}
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Fatalf("bucket weights sum to %v, want 1", total)
+	}
+}
+
+func TestConditionForFloat(t *testing.T) {
+	tests := []struct {
+		float float64
+		want  string
+	}{
+		{0, ConditionFactoryNew},
+		{0.0699, ConditionFactoryNew},
+		{0.07, ConditionMinimalWear},
+		{0.15, ConditionFieldTested},
+		{0.3799, ConditionFieldTested},
+		{0.38, ConditionWellWorn},
+		{0.45, ConditionBattleScarred},
+		{1, ConditionBattleScarred},
+	}
+	for _, tt := range tests {
+		if got := ConditionForFloat(tt.float); got != tt.want {
+			t.Errorf("ConditionForFloat(%v) = %s, want %s", tt.float, got, tt.want)
+		}
+	}
+}
+
+func TestWearFromRollMapsBucketBoundaries(t *testing.T) {
+	tests := []struct {
+		roll float64
+		want float64
+	}{
+		{0, 0},
+		{0.015, 0.035},   // middle of Factory New (3%)
+		{0.03, 0.07},     // start of Minimal Wear
+		{0.27, 0.15},     // start of Field-Tested
+		{0.435, 0.265},   // middle of Field-Tested
+		{0.60, 0.38},     // start of Well-Worn
+		{0.84, 0.45},     // start of Battle-Scarred
+		{0.92, 0.725},    // middle of Battle-Scarred
+		{0.999999, 0.99}, // just below the top
+	}
+	for _, tt := range tests {
+		if got := WearFromRoll(tt.roll); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("WearFromRoll(%v) = %v, want about %v", tt.roll, got, tt.want)
+		}
+	}
+}
+
+func TestWearFromRollFollowsBucketWeights(t *testing.T) {
+	const rolls = 100000
+	counts := map[string]int{}
+	for i := 0; i < rolls; i++ {
+		counts[ConditionForFloat(WearFromRoll((float64(i)+0.5)/rolls))]++
+	}
+	for _, bucket := range WearBuckets {
+		share := float64(counts[bucket.Condition]) / rolls
+		if math.Abs(share-bucket.Weight) > 0.001 {
+			t.Errorf("%s got %.4f of the rolls, want %.4f", bucket.Condition, share, bucket.Weight)
+		}
+	}
+}
+
+func TestFloatFromRollStaysInFloatCap(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		roll := float64(i) / 1000
+		got := FloatFromRoll(roll, 0.18, 0.6)
+		if got < 0.18 || got > 0.6 {
+			t.Fatalf("FloatFromRoll(%v, 0.18, 0.6) = %v, outside the float cap", roll, got)
+		}
+	}
+	if got := FloatFromRoll(0, 0.18, 0.6); got != 0.18 {
+		t.Errorf("FloatFromRoll(0) = %v, want the min float", got)
+	}
+}
+
+func TestPossibleConditions(t *testing.T) {
+	skin := Skin{MinFloat: 0.18, MaxFloat: 0.42}
+	got := skin.PossibleConditions()
+	want := []string{ConditionFieldTested, ConditionWellWorn}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("PossibleConditions = %v, want %v", got, want)
+	}
+}
+
+func TestPriceForConditionFallsBackToNearestBetterPrice(t *testing.T) {
+	skin := Skin{MinFloat: 0, MaxFloat: 1}
+	skin.SetConditionPrice(ConditionMinimalWear, MoneyFromFloat(30))
+	skin.SetConditionPrice(ConditionBattleScarred, MoneyFromFloat(10))
+
+	tests := map[string]float64{
+		ConditionFactoryNew:    30,
+		ConditionMinimalWear:   30,
+		ConditionFieldTested:   30, // tie between MW and WW; WW has no price, so MW
+		ConditionWellWorn:      10,
+		ConditionBattleScarred: 10,
+	}
+	for condition, want := range tests {
+		if got := skin.PriceForCondition(condition); got != MoneyFromFloat(want) {
+			t.Errorf("PriceForCondition(%s) = %v, want %v", condition, got, want)
+		}
+	}
+	if got := skin.PriceForCondition("Unknown"); got != 0 {
+		t.Errorf("PriceForCondition(Unknown) = %v, want 0", got)
+	}
+}
+
+func TestDeriveConditionPricesAndSyncValueRange(t *testing.T) {
+	skin := Skin{MinFloat: 0, MaxFloat: 1}
+	skin.DeriveConditionPrices(MoneyFromFloat(10), MoneyFromFloat(100))
+
+	previous := Money(math.MaxInt64)
+	for _, condition := range Conditions {
+		price := skin.PriceForCondition(condition)
+		if price < MoneyFromFloat(10) || price > MoneyFromFloat(100) {
+			t.Errorf("%s price %v is outside 10-100", condition, price)
+		}
+		if price > previous {
+			t.Errorf("%s price %v is higher than a better condition's %v", condition, price, previous)
+		}
+		previous = price
+	}
+
+	skin.SyncValueRange()
+	if skin.MaxValue != skin.PriceForCondition(ConditionFactoryNew) || skin.MinValue != skin.PriceForCondition(ConditionBattleScarred) {
+		t.Errorf("value range = %v-%v, want the Battle-Scarred to Factory New prices", skin.MinValue, skin.MaxValue)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+)
+
+// runPricesCommand records market prices into the price history and reprices skins from it.
+// Usage: backend prices snapshot [-recompute] | recompute [-window 168h]
+func runPricesCommand(cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: prices snapshot [-recompute] | recompute [-window 168h]")
+	}
+
+	switch args[0] {
+	case "snapshot":
+		flags := flag.NewFlagSet("prices snapshot", flag.ContinueOnError)
+		recompute := flags.Bool("recompute", false, "reprice skins from the recent history afterwards")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		provider, err := newPriceProvider(cfg)
+		if err != nil {
+			return err
+		}
+		if err := connectDatabase(cfg); err != nil {
+			return err
+		}
+
+		report, err := pricing.Snapshot(database.DB, provider)
+		if report != nil {
+			log.Printf("💲 Recorded %d prices from %s (%d missing)", report.Recorded, provider.Name(), report.Missing)
+		}
+		if err != nil {
+			return err
+		}
+		if *recompute {
+			return recomputeSkinValues(provider.Name(), cfg.PriceRecomputeWindow)
+		}
+		return nil
+
+	case "recompute":
+		flags := flag.NewFlagSet("prices recompute", flag.ContinueOnError)
+		window := flags.Duration("window", cfg.PriceRecomputeWindow, "how much recent history to price from")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *window <= 0 {
+			return fmt.Errorf("window must be a positive duration")
+		}
+
+		provider, err := newPriceProvider(cfg)
+		if err != nil {
+			return err
+		}
+		if err := connectDatabase(cfg); err != nil {
+			return err
+		}
+		return recomputeSkinValues(provider.Name(), *window)
+	}
+
+	return fmt.Errorf("unknown prices command %q", args[0])
+}
+
+// recomputeSkinValues reprices skins from the provider's price history and logs how many changed
+func recomputeSkinValues(provider string, window time.Duration) error {
+	updated, err := pricing.RecomputeSkinValues(database.DB, provider, window)
+	if err != nil {
+		return err
+	}
+	log.Printf("💲 Repriced %d skins from the last %s of %s market data", updated, window, provider)
+	return nil
+}
@@ -0,0 +1,86 @@
+package pricing
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Breaker is a circuit breaker around a provider. After maxFailures consecutive errors it
+// fails fast with ErrUnavailable for the cooldown, then lets a single trial call through:
+// success closes it again, another failure restarts the cooldown.
+// ErrNotFound is an answer, not a failure, and never trips it.
+type Breaker struct {
+	provider    Provider
+	maxFailures int
+	cooldown    time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewBreaker wraps provider in a circuit breaker
+func NewBreaker(provider Provider, maxFailures int, cooldown time.Duration) *Breaker {
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	return &Breaker{provider: provider, maxFailures: maxFailures, cooldown: cooldown}
+}
+
+// Name is the wrapped provider's name
+func (b *Breaker) Name() string {
+	return b.provider.Name()
+}
+
+// Quote calls the provider unless the breaker is open
+func (b *Breaker) Quote(marketHashName string) (*Quote, error) {
+	if !b.allow() {
+		return nil, ErrUnavailable
+	}
+
+	quote, err := b.provider.Quote(marketHashName)
+	b.record(err == nil || errors.Is(err, ErrNotFound))
+	return quote, err
+}
+
+// allow reports whether a call may go through, claiming the trial call once the cooldown passed
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.maxFailures {
+		return true
+	}
+	if b.trial || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// record updates the breaker with the outcome of a call
+func (b *Breaker) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.failures >= b.maxFailures
+	b.trial = false
+	if ok {
+		if wasOpen {
+			log.Printf("✅ Price provider %s recovered", b.provider.Name())
+		}
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.maxFailures {
+		if !wasOpen {
+			log.Printf("⚠️  Price provider %s failed %d times in a row, pausing calls for %s", b.provider.Name(), b.failures, b.cooldown)
+		}
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,77 @@
+package pricing
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a quote, or a confirmed miss when quote is nil
+type cacheEntry struct {
+	quote     *Quote
+	expiresAt time.Time
+}
+
+// Cache reuses quotes for a TTL. When the provider fails, the last quote for the item is
+// returned marked stale instead of the error, so a provider outage does not blank out prices.
+type Cache struct {
+	provider Provider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewCache wraps provider in a cache keeping quotes for ttl
+func NewCache(provider Provider, ttl time.Duration) *Cache {
+	return &Cache{provider: provider, ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+// Name is the wrapped provider's name
+func (c *Cache) Name() string {
+	return c.provider.Name()
+}
+
+// Quote returns the cached quote while it is fresh and asks the provider otherwise
+func (c *Cache) Quote(marketHashName string) (*Quote, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[marketHashName]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		if entry.quote == nil {
+			return nil, ErrNotFound
+		}
+		return copyQuote(entry.quote), nil
+	}
+
+	quote, err := c.provider.Quote(marketHashName)
+	switch {
+	case err == nil:
+		c.store(marketHashName, quote)
+		return copyQuote(quote), nil
+
+	case errors.Is(err, ErrNotFound):
+		c.store(marketHashName, nil)
+		return nil, err
+
+	case ok && entry.quote != nil:
+		stale := copyQuote(entry.quote)
+		stale.Stale = true
+		return stale, nil
+	}
+	return nil, err
+}
+
+// store remembers a quote (or a miss) until the TTL passes
+func (c *Cache) store(marketHashName string, quote *Quote) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[marketHashName] = cacheEntry{quote: quote, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// copyQuote keeps callers from modifying cached quotes
+func copyQuote(quote *Quote) *Quote {
+	copied := *quote
+	return &copied
+}
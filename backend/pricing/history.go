@@ -0,0 +1,189 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecomputeCurrency is the only currency skins are repriced from, since one USD is one Case Buck
+const RecomputeCurrency = "USD"
+
+// ErrMockPrices is returned when asked to record or reprice from the mock provider,
+// whose made-up prices would otherwise end up in the history and in sell values
+var ErrMockPrices = errors.New("the mock price provider's prices are made up; set PRICE_PROVIDER to a real provider")
+
+// SnapshotReport is the result of a price snapshot run
+type SnapshotReport struct {
+	Recorded int `json:"recorded"`
+	Missing  int `json:"missing"` // conditions the provider has no fresh price for
+}
+
+// Snapshot records the current market price of every active skin in each wear condition
+// its float cap can produce. Stale cached quotes are skipped so history only holds fresh data.
+// When the provider fails midway, the prices collected so far are still recorded.
+func Snapshot(db *gorm.DB, provider Provider) (*SnapshotReport, error) {
+	if provider.Name() == DriverMock {
+		return nil, ErrMockPrices
+	}
+
+	var skins []models.Skin
+	if err := db.Where("is_active = ?", true).Order("name ASC").Find(&skins).Error; err != nil {
+		return nil, fmt.Errorf("failed to load skins: %w", err)
+	}
+
+	report := &SnapshotReport{}
+	recordedAt := time.Now()
+	points := []models.SkinPricePoint{}
+	var providerErr error
+
+collect:
+	for _, skin := range skins {
+		for _, condition := range skin.PossibleConditions() {
+			name := MarketHashName(skin.Name, condition, false, false)
+			quote, err := provider.Quote(name)
+			if errors.Is(err, ErrNotFound) || (err == nil && quote.Stale) {
+				report.Missing++
+				continue
+			}
+			if err != nil {
+				providerErr = fmt.Errorf("price lookup for %s failed: %w", name, err)
+				break collect
+			}
+
+			points = append(points, models.SkinPricePoint{
+				SkinID:         skin.ID,
+				Condition:      condition,
+				MarketHashName: name,
+				Price:          models.MoneyFromFloat(quote.Suggested),
+				Quantity:       quote.Quantity,
+				Currency:       quote.Currency,
+				Provider:       quote.Provider,
+				RecordedAt:     recordedAt,
+			})
+		}
+	}
+
+	if len(points) > 0 {
+		if err := db.CreateInBatches(&points, 500).Error; err != nil {
+			return nil, fmt.Errorf("failed to record prices: %w", err)
+		}
+	}
+	report.Recorded = len(points)
+	return report, providerErr
+}
+
+// RecomputeSkinValues reprices every skin with the named provider's USD market data from the
+// last window: each wear condition gets the median recorded price, and MinValue/MaxValue (and so
+// sell values) follow. Points from other providers or currencies are ignored, and conditions
+// without recent data keep their current price. It returns how many skins changed.
+func RecomputeSkinValues(db *gorm.DB, provider string, window time.Duration) (int, error) {
+	if provider == DriverMock {
+		return 0, ErrMockPrices
+	}
+
+	var points []models.SkinPricePoint
+	err := db.Select("skin_id", "condition", "price").
+		Where("provider = ? AND currency = ? AND recorded_at >= ?", provider, RecomputeCurrency, time.Now().Add(-window)).
+		Find(&points).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load price history: %w", err)
+	}
+
+	samples := map[uuid.UUID]map[string][]models.Money{}
+	for _, point := range points {
+		if samples[point.SkinID] == nil {
+			samples[point.SkinID] = map[string][]models.Money{}
+		}
+		samples[point.SkinID][point.Condition] = append(samples[point.SkinID][point.Condition], point.Price)
+	}
+
+	updated := 0
+	for skinID, byCondition := range samples {
+		changed := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var skin models.Skin
+			if err := database.ForUpdate(tx).First(&skin, "id = ?", skinID).Error; err != nil {
+				return err
+			}
+
+			before := skin.ConditionPrices()
+			for condition, prices := range byCondition {
+				if price := median(prices); price > 0 {
+					skin.SetConditionPrice(condition, price)
+				}
+			}
+			for condition, price := range skin.ConditionPrices() {
+				if before[condition] != price {
+					changed = true
+				}
+			}
+			if !changed {
+				return nil
+			}
+			return tx.Save(&skin).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return updated, fmt.Errorf("failed to reprice skin %s: %w", skinID, err)
+		}
+		if changed {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// median returns the middle price (the mean of the two middle prices for an even count)
+func median(prices []models.Money) models.Money {
+	if len(prices) == 0 {
+		return 0
+	}
+	sorted := append([]models.Money(nil), prices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+// StartSnapshotJob records market prices on the given interval and, with recompute set,
+// reprices skins from the last recomputeWindow of history after every snapshot
+func StartSnapshotJob(db *gorm.DB, provider Provider, interval time.Duration, recompute bool, recomputeWindow time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, err := Snapshot(db, provider)
+			if err != nil {
+				log.Printf("❌ Price snapshot failed: %v", err)
+			}
+			if report == nil {
+				continue
+			}
+			log.Printf("💲 Price snapshot recorded %d prices (%d missing)", report.Recorded, report.Missing)
+
+			if !recompute {
+				continue
+			}
+			updated, err := RecomputeSkinValues(db, provider.Name(), recomputeWindow)
+			if err != nil {
+				log.Printf("❌ Skin value recompute failed: %v", err)
+				continue
+			}
+			log.Printf("💲 Repriced %d skins from market data", updated)
+		}
+	}()
+}
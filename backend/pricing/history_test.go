@@ -0,0 +1,50 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []models.Money
+		want   models.Money
+	}{
+		{"empty", nil, 0},
+		{"single", []models.Money{700}, 700},
+		{"odd count, unsorted", []models.Money{300, 100, 200}, 200},
+		{"even count averages the middle two", []models.Money{400, 100, 300, 200}, 250},
+		{"outlier does not move it", []models.Money{100, 110, 120, 100000}, 115},
+		{"duplicates", []models.Money{5, 5, 5, 9}, 5},
+	}
+	for _, tt := range tests {
+		if got := median(tt.prices); got != tt.want {
+			t.Errorf("%s: median(%v) = %v, want %v", tt.name, tt.prices, got, tt.want)
+		}
+	}
+}
+
+func TestMedianKeepsInputOrder(t *testing.T) {
+	prices := []models.Money{3, 1, 2}
+	median(prices)
+	if prices[0] != 3 || prices[1] != 1 || prices[2] != 2 {
+		t.Fatalf("median reordered its input: %v", prices)
+	}
+}
+
+func TestSnapshotAndRecomputeRefuseMockPrices(t *testing.T) {
+	// both refuse before touching the database
+	if _, err := Snapshot(nil, NewMockProvider()); !errors.Is(err, ErrMockPrices) {
+		t.Errorf("Snapshot with the mock provider = %v, want ErrMockPrices", err)
+	}
+	if _, err := Snapshot(nil, NewCache(NewMockProvider(), time.Minute)); !errors.Is(err, ErrMockPrices) {
+		t.Errorf("Snapshot with a cached mock provider = %v, want ErrMockPrices", err)
+	}
+	if _, err := RecomputeSkinValues(nil, DriverMock, time.Hour); !errors.Is(err, ErrMockPrices) {
+		t.Errorf("RecomputeSkinValues for the mock provider = %v, want ErrMockPrices", err)
+	}
+}
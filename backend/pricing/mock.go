@@ -0,0 +1,38 @@
+package pricing
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// MockProvider derives a stable pseudo-price from the item name (development and UI demos)
+type MockProvider struct{}
+
+// NewMockProvider creates a mock provider
+func NewMockProvider() *MockProvider {
+	return &MockProvider{}
+}
+
+// Name identifies the provider in quotes
+func (p *MockProvider) Name() string {
+	return DriverMock
+}
+
+// Quote returns a price between 5.00 and 154.99 USD that only depends on the name
+func (p *MockProvider) Quote(marketHashName string) (*Quote, error) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(marketHashName))
+	price := 5.0 + float64(h.Sum32()%15000)/100.0
+
+	return &Quote{
+		MarketHashName: marketHashName,
+		Currency:       "USD",
+		Suggested:      price,
+		Min:            math.Round(price*90) / 100,
+		Max:            math.Round(price*115) / 100,
+		Median:         price,
+		Provider:       p.Name(),
+		FetchedAt:      time.Now(),
+	}, nil
+}
@@ -0,0 +1,112 @@
+// Package pricing looks up market prices of CS2 items by market hash name
+// from a configurable provider, with caching and a circuit breaker around it.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/models"
+)
+
+// ErrNotFound is returned when the provider has no price for an item
+var ErrNotFound = errors.New("no market price for item")
+
+// ErrUnavailable is returned while the circuit breaker keeps calls away from a failing provider
+var ErrUnavailable = errors.New("price provider unavailable")
+
+// Quote is the market price of one item in USD
+type Quote struct {
+	MarketHashName string    `json:"market_hash_name"`
+	Currency       string    `json:"currency"`
+	Suggested      float64   `json:"suggested"`
+	Min            float64   `json:"min,omitempty"`
+	Max            float64   `json:"max,omitempty"`
+	Median         float64   `json:"median,omitempty"`
+	Quantity       int       `json:"quantity,omitempty"`
+	Provider       string    `json:"provider"`
+	FetchedAt      time.Time `json:"fetched_at"`
+
+	// Stale is set when the provider failed and an expired cached quote was returned
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Provider looks up market prices. Implementations must be safe for concurrent use.
+type Provider interface {
+	Name() string
+	Quote(marketHashName string) (*Quote, error)
+}
+
+// MarketHashName builds the market name of a skin in a wear condition, e.g.
+// "StatTrak™ AK-47 | Redline (Field-Tested)". An empty condition leaves the name without wear.
+func MarketHashName(skinName, condition string, statTrak, souvenir bool) string {
+	name := models.VariantName(skinName, statTrak, souvenir)
+	if condition == "" {
+		return name
+	}
+	return name + " (" + condition + ")"
+}
+
+// QuoteSkin prices a skin by name. Market listings carry a wear, so without a condition
+// the skin is priced in its best condition that has a listing.
+func QuoteSkin(provider Provider, skinName, condition string, statTrak, souvenir bool) (*Quote, error) {
+	quote, err := provider.Quote(MarketHashName(skinName, condition, statTrak, souvenir))
+	if condition != "" || !errors.Is(err, ErrNotFound) {
+		return quote, err
+	}
+
+	for _, wear := range models.Conditions {
+		quote, err = provider.Quote(MarketHashName(skinName, wear, statTrak, souvenir))
+		if !errors.Is(err, ErrNotFound) {
+			break
+		}
+	}
+	return quote, err
+}
+
+// Drivers selectable through Config.Driver
+const (
+	DriverMock     = "mock"
+	DriverSkinport = "skinport"
+)
+
+// Config selects and configures a Provider
+type Config struct {
+	Driver string
+
+	// skinport driver
+	URL      string
+	Currency string
+	Timeout  time.Duration
+
+	// CacheTTL is how long quotes are reused (0 disables the cache)
+	CacheTTL time.Duration
+
+	// The breaker opens after BreakerFailures consecutive errors and retries after BreakerCooldown
+	// (0 failures disables it)
+	BreakerFailures int
+	BreakerCooldown time.Duration
+}
+
+// New builds the Provider for the configured driver wrapped in the cache and circuit breaker
+func New(cfg Config) (Provider, error) {
+	var provider Provider
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverMock:
+		provider = NewMockProvider()
+	case DriverSkinport:
+		provider = NewSkinportProvider(cfg.URL, cfg.Currency, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown price provider %q", cfg.Driver)
+	}
+
+	if cfg.BreakerFailures > 0 {
+		provider = NewBreaker(provider, cfg.BreakerFailures, cfg.BreakerCooldown)
+	}
+	if cfg.CacheTTL > 0 {
+		provider = NewCache(provider, cfg.CacheTTL)
+	}
+	return provider, nil
+}
@@ -0,0 +1,212 @@
+package pricing
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errProviderDown = errors.New("provider down")
+
+// fakeProvider answers from a price table and fails while down is set
+type fakeProvider struct {
+	mu     sync.Mutex
+	prices map[string]float64
+	down   bool
+	calls  int
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) Quote(marketHashName string) (*Quote, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.down {
+		return nil, errProviderDown
+	}
+	price, ok := p.prices[marketHashName]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &Quote{MarketHashName: marketHashName, Currency: "USD", Suggested: price, Provider: p.Name()}, nil
+}
+
+func (p *fakeProvider) setDown(down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = down
+}
+
+func (p *fakeProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestMarketHashName(t *testing.T) {
+	tests := []struct {
+		skin, condition    string
+		statTrak, souvenir bool
+		want               string
+	}{
+		{"AK-47 | Redline", "Field-Tested", false, false, "AK-47 | Redline (Field-Tested)"},
+		{"AK-47 | Redline", "Field-Tested", true, false, "StatTrak™ AK-47 | Redline (Field-Tested)"},
+		{"AWP | Dragon Lore", "Factory New", false, true, "Souvenir AWP | Dragon Lore (Factory New)"},
+		{"AK-47 | Redline", "", false, false, "AK-47 | Redline"},
+	}
+	for _, tt := range tests {
+		if got := MarketHashName(tt.skin, tt.condition, tt.statTrak, tt.souvenir); got != tt.want {
+			t.Errorf("MarketHashName(%q, %q, %v, %v) = %q, want %q", tt.skin, tt.condition, tt.statTrak, tt.souvenir, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteSkinFallsBackToBestListedCondition(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{
+		"AK-47 | Redline (Field-Tested)":   12,
+		"AK-47 | Redline (Battle-Scarred)": 8,
+	}}
+
+	quote, err := QuoteSkin(provider, "AK-47 | Redline", "", false, false)
+	if err != nil {
+		t.Fatalf("QuoteSkin: %v", err)
+	}
+	if quote.MarketHashName != "AK-47 | Redline (Field-Tested)" {
+		t.Errorf("quoted %s, want the Field-Tested listing", quote.MarketHashName)
+	}
+
+	if _, err := QuoteSkin(provider, "AK-47 | Redline", "Factory New", false, false); !errors.Is(err, ErrNotFound) {
+		t.Errorf("QuoteSkin with an unlisted condition = %v, want ErrNotFound", err)
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"item": 1}, down: true}
+	breaker := NewBreaker(provider, 3, 50*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		if _, err := breaker.Quote("item"); !errors.Is(err, errProviderDown) {
+			t.Fatalf("call %d = %v, want the provider error", i+1, err)
+		}
+	}
+	if _, err := breaker.Quote("item"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("call while open = %v, want ErrUnavailable", err)
+	}
+	if provider.callCount() != 3 {
+		t.Fatalf("provider got %d calls, want 3 (none while open)", provider.callCount())
+	}
+
+	// a failed trial call after the cooldown opens it again
+	time.Sleep(60 * time.Millisecond)
+	if _, err := breaker.Quote("item"); !errors.Is(err, errProviderDown) {
+		t.Fatalf("trial call = %v, want the provider error", err)
+	}
+	if _, err := breaker.Quote("item"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("call after a failed trial = %v, want ErrUnavailable", err)
+	}
+
+	// a successful trial closes it
+	provider.setDown(false)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := breaker.Quote("item"); err != nil {
+		t.Fatalf("trial call after recovery: %v", err)
+	}
+	if _, err := breaker.Quote("item"); err != nil {
+		t.Fatalf("call after closing: %v", err)
+	}
+}
+
+func TestBreakerIgnoresNotFound(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{}}
+	breaker := NewBreaker(provider, 2, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		if _, err := breaker.Quote("missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("call %d = %v, want ErrNotFound", i+1, err)
+		}
+	}
+}
+
+func TestBreakerAllowsSingleTrialCall(t *testing.T) {
+	provider := &fakeProvider{down: true}
+	breaker := NewBreaker(provider, 1, 10*time.Millisecond)
+	breaker.Quote("item")
+	time.Sleep(20 * time.Millisecond)
+
+	if !breaker.allow() {
+		t.Fatal("first call after the cooldown was refused")
+	}
+	if breaker.allow() {
+		t.Fatal("a second call got through while the trial is running")
+	}
+}
+
+func TestCacheReusesQuotesAndMisses(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"item": 5}}
+	cache := NewCache(provider, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		quote, err := cache.Quote("item")
+		if err != nil || quote.Suggested != 5 {
+			t.Fatalf("Quote = %+v, %v", quote, err)
+		}
+		if _, err := cache.Quote("missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Quote(missing) = %v, want ErrNotFound", err)
+		}
+	}
+	if provider.callCount() != 2 {
+		t.Fatalf("provider got %d calls, want 2", provider.callCount())
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	cache := NewCache(&fakeProvider{prices: map[string]float64{"item": 5}}, time.Minute)
+
+	quote, _ := cache.Quote("item")
+	quote.Suggested = 999
+	if again, _ := cache.Quote("item"); again.Suggested != 5 {
+		t.Fatalf("cached quote was modified through a returned copy: %v", again.Suggested)
+	}
+}
+
+func TestCacheServesStaleQuoteWhileProviderFails(t *testing.T) {
+	provider := &fakeProvider{prices: map[string]float64{"item": 5}}
+	cache := NewCache(provider, 10*time.Millisecond)
+
+	if _, err := cache.Quote("item"); err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	provider.setDown(true)
+	time.Sleep(20 * time.Millisecond)
+
+	quote, err := cache.Quote("item")
+	if err != nil {
+		t.Fatalf("Quote with the provider down: %v", err)
+	}
+	if !quote.Stale || quote.Suggested != 5 {
+		t.Fatalf("Quote = %+v, want the last price marked stale", quote)
+	}
+
+	if _, err := cache.Quote("never-cached"); !errors.Is(err, errProviderDown) {
+		t.Fatalf("Quote of an uncached item = %v, want the provider error", err)
+	}
+}
+
+func TestNewWrapsProvider(t *testing.T) {
+	provider, err := New(Config{Driver: "MOCK", CacheTTL: time.Minute, BreakerFailures: 3})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, ok := provider.(*Cache); !ok {
+		t.Errorf("New returned %T, want the cache on the outside", provider)
+	}
+	if provider.Name() != DriverMock {
+		t.Errorf("Name = %q, want %q", provider.Name(), DriverMock)
+	}
+
+	if _, err := New(Config{Driver: "steam"}); err == nil {
+		t.Error("New accepted an unknown driver")
+	}
+}
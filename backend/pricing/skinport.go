@@ -0,0 +1,137 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SkinportURL is the public Skinport API
+const SkinportURL = "https://api.skinport.com"
+
+// skinportListTTL is how long one download of the item list answers lookups;
+// Skinport itself only refreshes the list every five minutes
+const skinportListTTL = 5 * time.Minute
+
+// maxListSize caps how much of the item list response is read
+const maxListSize = 64 << 20
+
+// skinportItem is one entry of GET /v1/items. Prices are null when nothing is listed.
+type skinportItem struct {
+	MarketHashName string   `json:"market_hash_name"`
+	Currency       string   `json:"currency"`
+	SuggestedPrice *float64 `json:"suggested_price"`
+	MinPrice       *float64 `json:"min_price"`
+	MaxPrice       *float64 `json:"max_price"`
+	MedianPrice    *float64 `json:"median_price"`
+	Quantity       int      `json:"quantity"`
+}
+
+// SkinportProvider reads prices from a Skinport-style API, which returns every CS2 item in one
+// list. The list is downloaded at most once per skinportListTTL and indexed by market hash name.
+type SkinportProvider struct {
+	baseURL  string
+	currency string
+	client   *http.Client
+
+	mu        sync.Mutex
+	items     map[string]skinportItem
+	fetchedAt time.Time
+}
+
+// NewSkinportProvider creates a provider for the API at baseURL (Skinport by default)
+func NewSkinportProvider(baseURL, currency string, timeout time.Duration) *SkinportProvider {
+	if baseURL == "" {
+		baseURL = SkinportURL
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &SkinportProvider{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		currency: strings.ToUpper(currency),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the provider in quotes
+func (p *SkinportProvider) Name() string {
+	return DriverSkinport
+}
+
+// Quote looks the item up in the current item list
+func (p *SkinportProvider) Quote(marketHashName string) (*Quote, error) {
+	// holding the lock during the download makes concurrent lookups share one request
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.items == nil || time.Since(p.fetchedAt) >= skinportListTTL {
+		items, err := p.fetchItems()
+		if err != nil {
+			return nil, err
+		}
+		p.items, p.fetchedAt = items, time.Now()
+	}
+
+	item, ok := p.items[marketHashName]
+	if !ok || item.SuggestedPrice == nil {
+		return nil, ErrNotFound
+	}
+	return &Quote{
+		MarketHashName: item.MarketHashName,
+		Currency:       p.currency,
+		Suggested:      *item.SuggestedPrice,
+		Min:            valueOf(item.MinPrice),
+		Max:            valueOf(item.MaxPrice),
+		Median:         valueOf(item.MedianPrice),
+		Quantity:       item.Quantity,
+		Provider:       p.Name(),
+		FetchedAt:      p.fetchedAt,
+	}, nil
+}
+
+// fetchItems downloads and indexes the item list
+func (p *SkinportProvider) fetchItems() (map[string]skinportItem, error) {
+	query := url.Values{"app_id": {"730"}, "currency": {p.currency}}
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+"/v1/items?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("price request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price provider returned %s", resp.Status)
+	}
+
+	var list []skinportItem
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxListSize)).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid price list: %w", err)
+	}
+
+	items := make(map[string]skinportItem, len(list))
+	for _, item := range list {
+		items[item.MarketHashName] = item
+	}
+	return items, nil
+}
+
+// valueOf returns the price or 0 when the provider has none
+func valueOf(price *float64) float64 {
+	if price == nil {
+		return 0
+	}
+	return *price
+}
@@ -0,0 +1,128 @@
+package pricing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// skinportStub serves a small /v1/items list and counts the downloads
+func skinportStub(t *testing.T, status *atomic.Int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v1/items" || r.URL.Query().Get("app_id") != "730" || r.URL.Query().Get("currency") != "EUR" {
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"market_hash_name": "AK-47 | Redline (Field-Tested)", "currency": "EUR",
+			 "suggested_price": 12.5, "min_price": 11.2, "max_price": 30, "median_price": 12.1, "quantity": 418},
+			{"market_hash_name": "AWP | Dragon Lore (Factory New)", "currency": "EUR",
+			 "suggested_price": null, "min_price": null, "max_price": null, "median_price": null, "quantity": 0}
+		]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestSkinportProviderQuote(t *testing.T) {
+	status := &atomic.Int32{}
+	status.Store(http.StatusOK)
+	server, requests := skinportStub(t, status)
+	provider := NewSkinportProvider(server.URL+"/", "eur", 5*time.Second)
+
+	quote, err := provider.Quote("AK-47 | Redline (Field-Tested)")
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	want := Quote{
+		MarketHashName: "AK-47 | Redline (Field-Tested)",
+		Currency:       "EUR",
+		Suggested:      12.5,
+		Min:            11.2,
+		Max:            30,
+		Median:         12.1,
+		Quantity:       418,
+		Provider:       DriverSkinport,
+		FetchedAt:      quote.FetchedAt,
+	}
+	if *quote != want {
+		t.Fatalf("Quote = %+v, want %+v", *quote, want)
+	}
+
+	// unlisted items and items without a price are not found, from the same download
+	for _, name := range []string{"AWP | Dragon Lore (Factory New)", "M4A4 | Howl (Minimal Wear)"} {
+		if _, err := provider.Quote(name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Quote(%q) = %v, want ErrNotFound", name, err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("stub got %d requests, want the item list downloaded once", requests.Load())
+	}
+}
+
+func TestSkinportProviderErrors(t *testing.T) {
+	status := &atomic.Int32{}
+	status.Store(http.StatusTooManyRequests)
+	server, requests := skinportStub(t, status)
+	provider := NewSkinportProvider(server.URL, "EUR", 5*time.Second)
+
+	if _, err := provider.Quote("AK-47 | Redline (Field-Tested)"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Quote with the API failing = %v, want a provider error", err)
+	}
+
+	// a failed download is retried on the next lookup
+	status.Store(http.StatusOK)
+	if _, err := provider.Quote("AK-47 | Redline (Field-Tested)"); err != nil {
+		t.Fatalf("Quote after the API recovered: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("stub got %d requests, want 2", requests.Load())
+	}
+}
+
+func TestSkinportProviderRejectsInvalidList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors": [{"message": "maintenance"}]}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewSkinportProvider(server.URL, "USD", 5*time.Second).Quote("anything"); err == nil {
+		t.Fatal("Quote accepted a response that is not an item list")
+	}
+}
+
+func TestSkinportProviderBehindBreakerAndCache(t *testing.T) {
+	status := &atomic.Int32{}
+	status.Store(http.StatusOK)
+	server, _ := skinportStub(t, status)
+
+	provider, err := New(Config{
+		Driver:          DriverSkinport,
+		URL:             server.URL,
+		Currency:        "EUR",
+		Timeout:         5 * time.Second,
+		CacheTTL:        time.Minute,
+		BreakerFailures: 1,
+		BreakerCooldown: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	quote, err := provider.Quote("AK-47 | Redline (Field-Tested)")
+	if err != nil || quote.Suggested != 12.5 || quote.Stale {
+		t.Fatalf("Quote = %+v, %v", quote, err)
+	}
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: Burst requests at once, refilled at Rate requests per Period
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// PerMinute is a limit of n requests per minute with a burst of n
+func PerMinute(n int) Limit {
+	return Limit{Rate: n, Period: time.Minute, Burst: n}
+}
+
+// ParseLimit reads a limit written as "<rate>/<period>" with an optional ":<burst>",
+// e.g. "300/1m" or "5/1h:3". Without a burst the bucket holds rate requests.
+func ParseLimit(text string) (Limit, error) {
+	spec, burstText, hasBurst := strings.Cut(strings.TrimSpace(text), ":")
+	rateText, periodText, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q must look like 60/1m or 60/1m:20", text)
+	}
+
+	rate, err := strconv.Atoi(rateText)
+	if err != nil || rate <= 0 {
+		return Limit{}, fmt.Errorf("limit %q needs a positive rate", text)
+	}
+	period, err := time.ParseDuration(periodText)
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("limit %q needs a positive period", text)
+	}
+	limit := Limit{Rate: rate, Period: period, Burst: rate}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burstText); err != nil || limit.Burst <= 0 {
+			return Limit{}, fmt.Errorf("limit %q needs a positive burst", text)
+		}
+	}
+	return limit, nil
+}
+
+// Result describes the outcome of taking a token
+type Result struct {
+	Allowed    bool
+	Limit      int           // bucket size
+	Remaining  int           // whole tokens left after this request
+	RetryAfter time.Duration // time until a token is available when not allowed
+	ResetAfter time.Duration // time until the bucket is full again
+}
+
+// Store keeps bucket state. The in-memory store works for a single instance;
+// a shared store (e.g. Redis) can implement this to limit across instances.
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is the state of one key in the memory store
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time // when the bucket will have refilled completely
+}
+
+// MemoryStore is an in-process token bucket store
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryStore creates a memory store and starts sweeping out refilled buckets on the given interval
+func NewMemoryStore(sweepInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{buckets: make(map[string]*bucket)}
+	if sweepInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(sweepInterval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				s.sweep(now)
+			}
+		}()
+	}
+	return s
+}
+
+// Take refills the key's bucket for the time elapsed and takes one token if available
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	burst := float64(limit.Burst)
+	perToken := limit.Period / time.Duration(limit.Rate)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updated)
+	if elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+float64(elapsed)/float64(perToken))
+		b.updated = now
+	}
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = time.Duration((burst - b.tokens) * float64(perToken))
+	b.fullAt = now.Add(result.ResetAfter)
+	return result, nil
+}
+
+// sweep drops buckets that have refilled, since a missing bucket starts out full anyway
+func (s *MemoryStore) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		text string
+		want Limit
+	}{
+		{"300/1m", Limit{Rate: 300, Period: time.Minute, Burst: 300}},
+		{"5/1h:3", Limit{Rate: 5, Period: time.Hour, Burst: 3}},
+		{" 60/1m:20 ", Limit{Rate: 60, Period: time.Minute, Burst: 20}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.text)
+		if err != nil {
+			t.Errorf("ParseLimit(%q) failed: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+
+	for _, text := range []string{"", "300", "0/1m", "-1/1m", "10/0s", "10/abc", "10/1m:0", "10/1m:x"} {
+		if _, err := ParseLimit(text); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestMemoryStoreBurstAndRefill(t *testing.T) {
+	store := NewMemoryStore(0)
+	limit := Limit{Rate: 60, Period: time.Minute, Burst: 3}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < limit.Burst; i++ {
+		result, err := store.Take("user", limit, now)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request %d was refused: %+v, %v", i+1, result, err)
+		}
+	}
+
+	result, _ := store.Take("user", limit, now)
+	if result.Allowed {
+		t.Fatalf("request over the burst was allowed")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %s, want 1s", result.RetryAfter)
+	}
+
+	// other keys have their own bucket
+	if result, _ := store.Take("other", limit, now); !result.Allowed {
+		t.Errorf("a different key was refused")
+	}
+
+	// one token comes back per second at 60 per minute
+	if result, _ := store.Take("user", limit, now.Add(time.Second)); !result.Allowed {
+		t.Errorf("request after the refill was refused")
+	}
+	if result, _ := store.Take("user", limit, now.Add(time.Second)); result.Allowed {
+		t.Errorf("second request after a single refill was allowed")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
+)
+
+// runReconcileCommand runs a one-off ledger reconciliation.
+// Usage: backend reconcile [-repair]
+func runReconcileCommand(cfg *Config, args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "reset drifted balances to the ledger balance")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := connectDatabase(cfg); err != nil {
+		return err
+	}
+	// every user needs a wallet before balances can be compared
+	if err := ledger.Bootstrap(database.DB); err != nil {
+		return fmt.Errorf("ledger bootstrap failed: %w", err)
+	}
+
+	report, err := ledger.Reconcile(database.DB, *repair)
+	if err != nil {
+		return err
+	}
+	ledger.LogReport(report)
+
+	// a non-zero exit lets cron/CI alert on unrepaired drift
+	if report.HasIssues() && report.Repaired+report.Settled < len(report.Drifts) {
+		return fmt.Errorf("found %d drifted balances and %d unbalanced transactions", len(report.Drifts), len(report.UnbalancedTransactions))
+	}
+	if len(report.UnbalancedTransactions) > 0 {
+		return fmt.Errorf("found %d unbalanced transactions", len(report.UnbalancedTransactions))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/TyronOdame/CS-OPN/backend/imagecatalog"
+	"github.com/TyronOdame/CS-OPN/backend/seed"
+)
+
+// runSeedCommand imports the sample catalog into an empty database.
+// Usage: backend seed
+func runSeedCommand(cfg *Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("seed takes no arguments")
+	}
+	if err := connectDatabase(cfg); err != nil {
+		return err
+	}
+
+	return seed.SeedDatabase()
+}
+
+// runSyncImagesCommand backfills missing or broken case and skin image URLs
+// and lists the names the image catalog has no match for.
+// Usage: backend sync-images [-file catalog.json]
+func runSyncImagesCommand(cfg *Config, args []string) error {
+	flags := flag.NewFlagSet("sync-images", flag.ContinueOnError)
+	file := flags.String("file", "", "read the image catalog from a local JSON file instead of the configured source")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: sync-images [-file catalog.json]")
+	}
+
+	provider, err := imageCatalogProvider(cfg, *file)
+	if err != nil {
+		return err
+	}
+	if err := connectDatabase(cfg); err != nil {
+		return err
+	}
+
+	report, err := seed.SyncImageURLs(provider)
+	if err != nil {
+		return fmt.Errorf("image sync failed: %w", err)
+	}
+
+	fmt.Printf("Updated %d skins and %d cases from %s\n", report.SkinsUpdated, report.CasesUpdated, report.Source)
+	if report.Stale {
+		fmt.Println("The catalog source was unreachable; the cached copy was used.")
+	}
+	if len(report.Unresolved) > 0 {
+		fmt.Printf("%d names have no catalog image:\n", len(report.Unresolved))
+		for _, name := range report.Unresolved {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	return nil
+}
+
+// imageCatalogProvider builds the configured image catalog provider; a file path overrides the source
+func imageCatalogProvider(cfg *Config, file string) (imagecatalog.Provider, error) {
+	catalogConfig := imagecatalog.Config{
+		Source:   cfg.ImageCatalogSource,
+		URL:      cfg.ImageCatalogURL,
+		CacheDir: cfg.ImageCatalogCacheDir,
+		Timeout:  cfg.ImageCatalogTimeout,
+		File:     cfg.ImageCatalogFile,
+	}
+	if file != "" {
+		catalogConfig.Source = imagecatalog.SourceFile
+		catalogConfig.File = file
+	}
+	return imagecatalog.New(catalogConfig)
+}
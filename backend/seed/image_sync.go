@@ -1,142 +1,110 @@
 package seed
 
 import (
-	"encoding/json"
 	"log"
-	"net/http"
+	"net/url"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/imagecatalog"
 	"github.com/TyronOdame/CS-OPN/backend/models"
 )
 
-const steamAPISImageCatalogURL = "https://api.steamapis.com/image/items/730"
-
-var preferredWearOrder = []string{
-	"Factory New",
-	"Minimal Wear",
-	"Field-Tested",
-	"Well-Worn",
-	"Battle-Scarred",
+// staleImageHosts serve Steam economy images under URLs that have stopped resolving;
+// images pointing there are replaced from the catalog
+var staleImageHosts = map[string]bool{
+	"community.cloudflare.steamstatic.com": true,
+	"steamcommunity-a.akamaihd.net":        true,
+	"cdn.steamcommunity.com":               true,
 }
 
-// SyncImageURLs backfills broken/missing case and skin image URLs.
-// It resolves names against SteamApis' CS2 image catalog.
-func SyncImageURLs() {
-	log.Println("🖼️  Syncing image URLs from SteamApis...")
-
-	imageMap, err := fetchSteamImageCatalog()
-	if err != nil {
-		log.Printf("⚠️  Image sync skipped (catalog fetch failed): %v", err)
-		return
-	}
-
-	skinsUpdated := syncSkinImages(imageMap)
-	casesUpdated := syncCaseImages(imageMap)
+// ImageSyncReport is the result of an image sync run
+type ImageSyncReport struct {
+	Source       string
+	Stale        bool
+	SkinsUpdated int
+	CasesUpdated int
 
-	log.Printf("🖼️  Image sync complete. skins=%d, cases=%d", skinsUpdated, casesUpdated)
+	// Unresolved lists the skins and cases that needed an image but had no match in the catalog
+	Unresolved []string
 }
 
-func fetchSteamImageCatalog() (map[string]string, error) {
-	client := &http.Client{Timeout: 12 * time.Second}
-	resp, err := client.Get(steamAPISImageCatalogURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// SyncImageURLs backfills broken/missing case and skin image URLs
+// by resolving their names against the provider's image catalog.
+func SyncImageURLs(provider imagecatalog.Provider) (*ImageSyncReport, error) {
+	log.Println("🖼️  Syncing image URLs...")
 
-	var imageMap map[string]string
-	if err := json.NewDecoder(resp.Body).Decode(&imageMap); err != nil {
+	catalog, err := provider.Fetch()
+	if err != nil {
 		return nil, err
 	}
-	return imageMap, nil
-}
+	matcher := imagecatalog.NewMatcher(catalog.Images)
+	report := &ImageSyncReport{Source: catalog.Source, Stale: catalog.Stale}
 
-func syncSkinImages(imageMap map[string]string) int {
 	var skins []models.Skin
 	if err := database.DB.Find(&skins).Error; err != nil {
-		log.Printf("⚠️  Failed loading skins for image sync: %v", err)
-		return 0
+		return nil, err
 	}
-
-	updated := 0
 	for _, skin := range skins {
 		if !shouldReplaceImageURL(skin.ImageURL) {
 			continue
 		}
-
-		newURL := resolveImageByName(imageMap, skin.Name)
-		if newURL == "" {
+		newURL, ok := matcher.Resolve(skin.Name)
+		if !ok {
+			report.Unresolved = append(report.Unresolved, skin.Name)
+			continue
+		}
+		if newURL == skin.ImageURL {
 			continue
 		}
-
 		if err := database.DB.Model(&skin).Update("image_url", newURL).Error; err != nil {
 			log.Printf("⚠️  Failed updating skin image (%s): %v", skin.Name, err)
 			continue
 		}
-		updated++
+		report.SkinsUpdated++
 	}
 
-	return updated
-}
-
-func syncCaseImages(imageMap map[string]string) int {
 	var cases []models.Case
 	if err := database.DB.Find(&cases).Error; err != nil {
-		log.Printf("⚠️  Failed loading cases for image sync: %v", err)
-		return 0
+		return nil, err
 	}
-
-	updated := 0
 	for _, caseItem := range cases {
 		if !shouldReplaceImageURL(caseItem.ImageURL) {
 			continue
 		}
-
-		newURL := resolveImageByName(imageMap, caseItem.Name)
-		if newURL == "" {
+		newURL, ok := matcher.Resolve(caseItem.Name)
+		if !ok {
+			report.Unresolved = append(report.Unresolved, caseItem.Name)
+			continue
+		}
+		if newURL == caseItem.ImageURL {
 			continue
 		}
-
 		if err := database.DB.Model(&caseItem).Update("image_url", newURL).Error; err != nil {
 			log.Printf("⚠️  Failed updating case image (%s): %v", caseItem.Name, err)
 			continue
 		}
-		updated++
+		report.CasesUpdated++
 	}
 
-	return updated
-}
-
-func shouldReplaceImageURL(url string) bool {
-	if strings.TrimSpace(url) == "" {
-		return true
+	sort.Strings(report.Unresolved)
+	log.Printf("🖼️  Image sync complete. skins=%d, cases=%d, unresolved=%d (source: %s)",
+		report.SkinsUpdated, report.CasesUpdated, len(report.Unresolved), report.Source)
+	for _, name := range report.Unresolved {
+		log.Printf("⚠️  No catalog image for %q", name)
 	}
-	lowerURL := strings.ToLower(url)
-	return strings.Contains(lowerURL, "community.cloudflare.steamstatic.com")
+	return report, nil
 }
 
-func resolveImageByName(imageMap map[string]string, name string) string {
-	if exact := imageMap[name]; exact != "" {
-		return exact
-	}
-
-	// Try common wear variants for skins stored without wear suffix.
-	for _, wear := range preferredWearOrder {
-		candidate := name + " (" + wear + ")"
-		if imageMap[candidate] != "" {
-			return imageMap[candidate]
-		}
+// shouldReplaceImageURL reports whether an image URL is missing, malformed or on a stale host
+func shouldReplaceImageURL(imageURL string) bool {
+	if strings.TrimSpace(imageURL) == "" {
+		return true
 	}
-
-	// Fallback: any catalog entry that starts with "<name> (".
-	prefix := name + " ("
-	for marketHashName, imageURL := range imageMap {
-		if strings.HasPrefix(marketHashName, prefix) {
-			return imageURL
-		}
+	parsed, err := url.Parse(imageURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return true
 	}
-
-	return ""
+	return staleImageHosts[strings.ToLower(parsed.Hostname())]
 }
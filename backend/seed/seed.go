@@ -0,0 +1,50 @@
+package seed
+
+import (
+	_ "embed"
+	"fmt"
+	"log"
+
+	"github.com/TyronOdame/CS-OPN/backend/catalog"
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+)
+
+// defaultCatalog is the sample catalog of skins and cases seeded into an empty database
+//
+//go:embed catalog.yaml
+var defaultCatalog []byte
+
+// DefaultCatalog parses the embedded sample catalog
+func DefaultCatalog() (*catalog.Catalog, error) {
+	return catalog.Parse(defaultCatalog, "yaml")
+}
+
+// SeedDatabase imports the sample catalog when the database has no cases yet
+func SeedDatabase() error {
+	log.Println("🌱 Seeding database with sample data...")
+
+	// Check if data already exists
+	var caseCount int64
+	if err := database.DB.Model(&models.Case{}).Count(&caseCount).Error; err != nil {
+		return fmt.Errorf("failed to count cases: %w", err)
+	}
+	if caseCount > 0 {
+		log.Println("⏭️  Database already seeded, skipping...")
+		return nil
+	}
+
+	sample, err := DefaultCatalog()
+	if err != nil {
+		return fmt.Errorf("invalid sample catalog: %w", err)
+	}
+
+	// the import runs in one transaction, so a failed seed is retried from scratch on the next start
+	diff, err := catalog.Import(database.DB, sample, false)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✅ Database seeded successfully! (%d changes)", len(diff.Changes))
+	return nil
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/assistant"
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/handlers"
+	"github.com/TyronOdame/CS-OPN/backend/imageproxy"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
+	"github.com/TyronOdame/CS-OPN/backend/llm"
+	"github.com/TyronOdame/CS-OPN/backend/mailer"
+	"github.com/TyronOdame/CS-OPN/backend/middleware"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/pricing"
+	"github.com/TyronOdame/CS-OPN/backend/ratelimit"
+	"github.com/TyronOdame/CS-OPN/backend/seed"
+	"github.com/TyronOdame/CS-OPN/backend/twofactor"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// runServeCommand prepares the database and runs the HTTP API until it exits.
+// Usage: backend serve
+func runServeCommand(cfg *Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("serve takes no arguments")
+	}
+	if err := connectDatabase(cfg); err != nil {
+		return err
+	}
+
+	// promote the configured first admin if there is none yet
+	if err := database.BootstrapAdmin(cfg.BootstrapAdminEmail); err != nil {
+		return fmt.Errorf("admin bootstrap failed: %w", err)
+	}
+
+	// make sure every user has a ledger wallet before serving requests
+	if err := ledger.Bootstrap(database.DB); err != nil {
+		return fmt.Errorf("ledger bootstrap failed: %w", err)
+	}
+
+	// Seed sample data on boot for local development; operators run `seed` and `sync-images` instead
+	if cfg.RunSeedOnStart {
+		if err := seed.SeedDatabase(); err != nil {
+			return fmt.Errorf("database seeding failed: %w", err)
+		}
+		if cfg.SyncImagesOnStart {
+			// a missing image is not worth refusing to start over
+			provider, err := imageCatalogProvider(cfg, "")
+			if err == nil {
+				_, err = seed.SyncImageURLs(provider)
+			}
+			if err != nil {
+				log.Printf("⚠️  Image sync skipped: %v", err)
+			}
+		}
+	}
+
+	// version any case contents that were seeded outside the admin drop table editor
+	if err := database.EnsureDropTables(); err != nil {
+		return fmt.Errorf("drop table versioning failed: %w", err)
+	}
+
+	// report cases whose odds are broken; they are refused at request time until fixed
+	if err := database.CheckDropTables(); err != nil {
+		return fmt.Errorf("drop table integrity check failed: %w", err)
+	}
+
+	// Nightly ledger reconciliation
+	ledger.StartReconciliationJob(database.DB, cfg.ReconcileInterval, cfg.ReconcileRepair)
+
+	// Finalize deleted accounts once their grace period has passed
+	database.StartAccountPurgeJob(time.Hour)
+
+	// Create HTTP server
+	router := gin.Default()
+
+	// only listed proxies may set the client IP that IP rate limits key on
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	allowedOrigins := []string{"http://localhost:3000"}
+	if cfg.frontendURL != "" {
+		originsFromEnv := strings.Split(cfg.frontendURL, ",")
+		parsedOrigins := make([]string, 0, len(originsFromEnv))
+		for _, origin := range originsFromEnv {
+			trimmedOrigin := strings.TrimSpace(origin)
+			if trimmedOrigin != "" {
+				parsedOrigins = append(parsedOrigins, trimmedOrigin)
+			}
+		}
+		if len(parsedOrigins) > 0 {
+			allowedOrigins = parsedOrigins
+		}
+	}
+
+	// CORS
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     allowedOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.IdempotencyKeyHeader, twofactor.CodeHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.IdempotentReplayHeader, "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
+		AllowCredentials: true,
+		MaxAge:           12 * 3600, // 12 hours
+	}))
+
+	// Health Check Endpoints
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":   "ok",
+			"database": "connected",
+			"message":  "CS:OPN backend is running!",
+		})
+	})
+
+	// Skin and case images, fetched once from their upstream CDN and served from the local store
+	imageStore, err := imageproxy.NewStore(cfg.ImageStoreDir)
+	if err != nil {
+		return fmt.Errorf("failed to set up image store: %w", err)
+	}
+	router.GET("/images/:kind/:id", handlers.ServeImage(imageproxy.New(imageStore, 15*time.Second)))
+
+	// market prices for the price check
+	priceProvider, err := newPriceProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up price provider: %w", err)
+	}
+	log.Printf("💲 Price provider: %s", priceProvider.Name())
+
+	// Record market prices for the price history (and reprice skins from them when enabled).
+	// The mock provider's made-up prices are never recorded.
+	if cfg.PriceSnapshotInterval > 0 && priceProvider.Name() == pricing.DriverMock {
+		log.Println("💲 Price snapshots skipped: the mock provider has no real prices")
+	} else if cfg.PriceSnapshotInterval > 0 {
+		pricing.StartSnapshotJob(database.DB, priceProvider, cfg.PriceSnapshotInterval, cfg.PriceRecompute, cfg.PriceRecomputeWindow)
+	}
+
+	// language model behind the price-check assistant
+	chatClient, err := llm.New(llm.Config{
+		Driver:  cfg.AIProvider,
+		URL:     cfg.AIAPIURL,
+		APIKey:  cfg.AIAPIKey,
+		Model:   cfg.AIModel,
+		Timeout: cfg.AITimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up AI client: %w", err)
+	}
+	log.Printf("🤖 AI provider: %s", chatClient.Name())
+	chatConfig := handlers.ChatConfig{
+		Assistant:     assistant.New(chatClient, priceProvider, database.DB),
+		DailyMessages: cfg.AIChatDailyMessages,
+		DailyTokens:   cfg.AIChatDailyTokens,
+	}
+
+	// verification and password reset emails
+	mail, err := mailer.New(mailer.Config{
+		Driver:       cfg.MailDriver,
+		From:         cfg.MailFrom,
+		Dir:          cfg.MailDir,
+		SMTPHost:     cfg.SMTPHost,
+		SMTPPort:     cfg.SMTPPort,
+		SMTPUsername: cfg.SMTPUsername,
+		SMTPPassword: cfg.SMTPPassword,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up mailer: %w", err)
+	}
+	log.Printf("📧 Mail driver: %s", cfg.MailDriver)
+
+	authConfig := handlers.AuthConfig{
+		JWTSecret:       cfg.JWTSecret,
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+		Mailer:          mail,
+		AppURL:          cfg.AppURL,
+
+		AccountDeletionGrace: cfg.AccountDeletionGrace,
+	}
+
+	// Rate limits per route group. Credential endpoints are keyed by IP; gameplay by user.
+	rateLimitStore := ratelimit.NewMemoryStore(time.Minute)
+	apiLimit := middleware.RateLimit(rateLimitStore, "api", cfg.RateLimitAPI, middleware.RateLimitByIP)
+	authLimit := middleware.RateLimit(rateLimitStore, "auth", cfg.RateLimitAuth, middleware.RateLimitByIP)
+	registerLimit := middleware.RateLimit(rateLimitStore, "register", cfg.RateLimitRegister, middleware.RateLimitByIP)
+	gameplayLimit := middleware.RateLimit(rateLimitStore, "gameplay", cfg.RateLimitGameplay, middleware.RateLimitByUser)
+	aiLimit := middleware.RateLimit(rateLimitStore, "ai", cfg.RateLimitAI, middleware.RateLimitByUser)
+	router.Use(apiLimit)
+
+	//Auth routes
+	authRoutes := router.Group("/auth")
+	authRoutes.Use(authLimit)
+	{
+		authRoutes.POST("/register", registerLimit, handlers.RegisterHandler(authConfig))
+		authRoutes.POST("/login", handlers.Login(authConfig))
+		authRoutes.POST("/2fa/verify", handlers.VerifyTwoFactorLogin(authConfig))
+		authRoutes.POST("/refresh", handlers.Refresh(authConfig))
+		authRoutes.POST("/logout", middleware.AuthMiddleware(cfg.JWTSecret), handlers.Logout)
+		authRoutes.POST("/verify-email", handlers.VerifyEmail)
+		authRoutes.POST("/forgot-password", handlers.ForgotPassword(authConfig))
+		authRoutes.POST("/reset-password", handlers.ResetPassword)
+		authRoutes.POST("/restore-account", handlers.RestoreAccount(authConfig))
+	}
+
+	// User routes (protected - requires JWT token)
+	userRoutes := router.Group("/user")
+	userRoutes.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	{
+		// sensitive actions need a fresh 2FA code from users who have it enabled
+		userRoutes.DELETE("", middleware.RequireFreshTwoFactor(), handlers.DeleteAccount(authConfig))
+		userRoutes.GET("/profile", handlers.GetProfile)
+		userRoutes.PUT("/profile", handlers.UpdateProfile(authConfig))
+		userRoutes.POST("/verify-email/resend", handlers.ResendVerificationEmail(authConfig))
+		userRoutes.PUT("/password", middleware.RequireFreshTwoFactor(), handlers.ChangePassword(authConfig))
+		userRoutes.POST("/2fa/enroll", handlers.EnrollTwoFactor)
+		userRoutes.POST("/2fa/confirm", handlers.ConfirmTwoFactor)
+		userRoutes.POST("/2fa/disable", middleware.RequireFreshTwoFactor(), handlers.DisableTwoFactor)
+		userRoutes.POST("/2fa/recovery-codes", middleware.RequireFreshTwoFactor(), handlers.RegenerateRecoveryCodes)
+		userRoutes.GET("/sessions", handlers.GetSessions)
+		userRoutes.DELETE("/sessions", handlers.RevokeAllSessions)
+		userRoutes.DELETE("/sessions/:id", handlers.RevokeSession)
+	}
+
+	// Case routes
+	caseRoutes := router.Group("/cases")
+	{
+		// public routes
+		caseRoutes.GET("", handlers.GetAllCases)
+		caseRoutes.GET("/:id", handlers.GetCaseByID)
+
+		// protected routes
+		// balance-mutating routes accept an Idempotency-Key header for safe retries
+		caseRoutes.POST("/:id/buy", middleware.AuthMiddleware(cfg.JWTSecret), gameplayLimit, middleware.Idempotency(), handlers.BuyCase(cfg.MaxBatchOpen))
+		caseRoutes.POST("/:id/open", middleware.AuthMiddleware(cfg.JWTSecret), gameplayLimit, middleware.Idempotency(), handlers.OpenCase)
+	}
+
+	// Skin routes (public)
+	skinRoutes := router.Group("/skins")
+	{
+		skinRoutes.GET("/:id/price-history", handlers.GetSkinPriceHistory(priceProvider.Name(), cfg.PriceCurrency))
+	}
+
+	// Inventory routes (protected)
+	inventoryRoutes := router.Group("/inventory")
+	inventoryRoutes.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	{
+		inventoryRoutes.GET("", handlers.GetUserInventory)
+		inventoryRoutes.POST("/:id/sell", gameplayLimit, middleware.Idempotency(), handlers.SellInventoryItem)
+		inventoryRoutes.GET("/cases", handlers.GetUserCases)
+		inventoryRoutes.POST("/cases/:id/open", gameplayLimit, middleware.Idempotency(), handlers.OpenPurchasedCase)
+		inventoryRoutes.POST("/cases/open-batch", gameplayLimit, middleware.Idempotency(), handlers.OpenPurchasedCasesBatch(cfg.MaxBatchOpen))
+	}
+
+	// Transaction routes (protected)
+	transactionRoutes := router.Group("/transactions")
+	transactionRoutes.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	{
+		transactionRoutes.GET("", handlers.GetUserTransactions)
+
+	}
+
+	// Provably fair routes
+	fairnessRoutes := router.Group("/fairness")
+	{
+		// public routes
+		fairnessRoutes.GET("/verify/:id", handlers.VerifyOpening)
+		fairnessRoutes.POST("/verify", handlers.VerifyRoll)
+
+		// protected routes
+		fairnessRoutes.GET("/seeds", middleware.AuthMiddleware(cfg.JWTSecret), handlers.GetFairnessSeeds)
+		fairnessRoutes.PUT("/client-seed", middleware.AuthMiddleware(cfg.JWTSecret), handlers.UpdateClientSeed)
+		fairnessRoutes.POST("/rotate", middleware.AuthMiddleware(cfg.JWTSecret), handlers.RotateServerSeed)
+	}
+
+	// Admin routes (protected - requires JWT token and the matching permission)
+	adminRoutes := router.Group("/admin")
+	adminRoutes.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	{
+		adminRoutes.GET("/users", middleware.RequirePermission(models.PermissionViewUsers), handlers.AdminListUsers)
+		adminRoutes.PUT("/users/:id/role", middleware.RequirePermission(models.PermissionManageRoles), handlers.AdminUpdateUserRole)
+	}
+
+	catalogRoutes := adminRoutes.Group("")
+	catalogRoutes.Use(middleware.RequirePermission(models.PermissionManageCatalog))
+	{
+		catalogRoutes.GET("/cases", handlers.AdminListCases)
+		catalogRoutes.POST("/cases", handlers.AdminCreateCase)
+		catalogRoutes.PUT("/cases/:id", handlers.AdminUpdateCase)
+		catalogRoutes.DELETE("/cases/:id", handlers.AdminDeactivateCase)
+		catalogRoutes.GET("/cases/:id/contents", handlers.AdminGetCaseContents)
+		catalogRoutes.PUT("/cases/:id/contents", handlers.AdminUpdateCaseContents)
+
+		catalogRoutes.GET("/skins", handlers.AdminListSkins)
+		catalogRoutes.POST("/skins", handlers.AdminCreateSkin)
+		catalogRoutes.PUT("/skins/:id", handlers.AdminUpdateSkin)
+		catalogRoutes.DELETE("/skins/:id", handlers.AdminDeactivateSkin)
+		catalogRoutes.GET("/skins/:id/patterns", handlers.AdminListPatternRules)
+		catalogRoutes.POST("/skins/:id/patterns", handlers.AdminCreatePatternRule)
+		catalogRoutes.DELETE("/skins/:id/patterns/:ruleId", handlers.AdminDeletePatternRule)
+	}
+
+	// AI price check against the configured market price provider, and the price-check assistant
+	aiRoutes := router.Group("/ai")
+	aiRoutes.Use(middleware.AuthMiddleware(cfg.JWTSecret), aiLimit)
+	{
+		aiRoutes.POST("/price-check", handlers.PriceCheck(priceProvider))
+		aiRoutes.POST("/chat", handlers.Chat(chatConfig))
+		aiRoutes.GET("/chat/threads", handlers.GetChatThreads(chatConfig))
+		aiRoutes.GET("/chat/threads/:id", handlers.GetChatThread)
+		aiRoutes.DELETE("/chat/threads/:id", handlers.DeleteChatThread)
+	}
+
+	// Start server
+	log.Printf("🚀 Server starting on port %s", cfg.ServerPort)
+	log.Printf("📍 Health check: http://localhost:%s/health", cfg.ServerPort)
+	log.Printf("🎁 Cases: GET http://localhost:%s/cases", cfg.ServerPort)
+	log.Printf("👤 Profile: GET http://localhost:%s/user/profile (protected)", cfg.ServerPort)
+	log.Printf("✏️  Update: PUT http://localhost:%s/user/profile (protected)", cfg.ServerPort)
+	log.Printf("🔐 Register: POST http://localhost:%s/auth/register", cfg.ServerPort)
+	return router.Run(":" + cfg.ServerPort)
+}
+
+// newPriceProvider builds the configured market price provider
+func newPriceProvider(cfg *Config) (pricing.Provider, error) {
+	return pricing.New(pricing.Config{
+		Driver:          cfg.PriceProvider,
+		URL:             cfg.PriceAPIURL,
+		Currency:        cfg.PriceCurrency,
+		Timeout:         cfg.PriceTimeout,
+		CacheTTL:        cfg.PriceCacheTTL,
+		BreakerFailures: cfg.PriceBreakerFailures,
+		BreakerCooldown: cfg.PriceBreakerCooldown,
+	})
+}
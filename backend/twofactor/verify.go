@@ -0,0 +1,146 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"github.com/TyronOdame/CS-OPN/backend/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CodeHeader carries a fresh 2FA code on requests for sensitive actions
+const CodeHeader = "X-2FA-Code"
+
+// RecoveryCodeCount is how many recovery codes are generated at a time
+const RecoveryCodeCount = 10
+
+var (
+	// ErrCodeRequired is returned when 2FA is enabled but no code was given
+	ErrCodeRequired = errors.New("two-factor code required")
+	// ErrInvalidCode is returned for a wrong, reused or expired code
+	ErrInvalidCode = errors.New("invalid two-factor code")
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Verify checks a TOTP code or an unused recovery code for the user and consumes it.
+// The caller must hold the user row lock so a code cannot be redeemed twice concurrently.
+func Verify(tx *gorm.DB, user *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrCodeRequired
+	}
+	if user.TOTPSecret == "" {
+		return ErrInvalidCode
+	}
+
+	if step, ok := Validate(user.TOTPSecret, code, time.Now(), user.TOTPLastUsedStep); ok {
+		user.TOTPLastUsedStep = step
+		return tx.Model(user).Update("totp_last_used_step", step).Error
+	}
+
+	// fall back to recovery codes
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// LockedError is returned by CheckFresh while codes are refused after too many wrong ones
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return "too many invalid two-factor codes"
+}
+
+// CheckFresh verifies a code for a sensitive action. Users without 2FA pass straight through.
+// After MaxTwoFactorAttempts wrong codes in a row further codes are refused for TwoFactorLockout.
+func CheckFresh(db *gorm.DB, userID uuid.UUID, code string) error {
+	var verifyErr error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := database.ForUpdate(tx).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if !user.IsTwoFactorEnabled() {
+			return nil
+		}
+		if user.IsTwoFactorLocked() {
+			verifyErr = &LockedError{Until: *user.TwoFactorLockedUntil}
+			return nil
+		}
+
+		verifyErr = Verify(tx, &user, code)
+		switch {
+		case verifyErr == nil:
+			if user.FailedTwoFactorCount == 0 && user.TwoFactorLockedUntil == nil {
+				return nil
+			}
+			return tx.Model(&user).Updates(map[string]interface{}{
+				"failed_two_factor_count": 0,
+				"two_factor_locked_until": nil,
+			}).Error
+		case errors.Is(verifyErr, ErrInvalidCode):
+			// the failed attempt has to commit, so it is not returned from the transaction
+			updates := map[string]interface{}{"failed_two_factor_count": user.FailedTwoFactorCount + 1}
+			if user.FailedTwoFactorCount+1 >= models.MaxTwoFactorAttempts {
+				updates["failed_two_factor_count"] = 0
+				updates["two_factor_locked_until"] = time.Now().Add(models.TwoFactorLockout)
+			}
+			return tx.Model(&user).Updates(updates).Error
+		default:
+			return verifyErr
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return verifyErr
+}
+
+// GenerateRecoveryCodes replaces the user's recovery codes and returns the new ones in plain text
+func GenerateRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, RecoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(buf))[:10]
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode normalizes a recovery code so dashes, spaces and case don't matter, then hashes it
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return utils.HashToken(normalized)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/TyronOdame/CS-OPN/backend/database"
+	"github.com/TyronOdame/CS-OPN/backend/ledger"
+	"github.com/TyronOdame/CS-OPN/backend/models"
+	"gorm.io/gorm"
+)
+
+// runUserCommand manages user accounts from the command line.
+// Usage: backend user promote [-role admin] <email>
+//
+//	backend user grant-casebucks [-reason text] <email> <amount>
+func runUserCommand(cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: user promote [-role admin] <email> | grant-casebucks [-reason text] <email> <amount>")
+	}
+
+	switch args[0] {
+	case "promote":
+		return runUserPromote(cfg, args[1:])
+	case "grant-casebucks":
+		return runUserGrantCasebucks(cfg, args[1:])
+	}
+	return fmt.Errorf("unknown user command %q", args[0])
+}
+
+// runUserPromote sets a user's role. Access tokens with the old role stop working; refreshing picks up the new one.
+func runUserPromote(cfg *Config, args []string) error {
+	flags := flag.NewFlagSet("user promote", flag.ContinueOnError)
+	role := flags.String("role", string(models.RoleAdmin), "role to give the user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: user promote [-role admin] <email>")
+	}
+	if !models.Role(*role).IsValid() {
+		return fmt.Errorf("unknown role %q (roles: %v)", *role, models.Roles)
+	}
+
+	if err := connectDatabase(cfg); err != nil {
+		return err
+	}
+
+	user, err := findUserByEmail(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	// bumping the token version drops access tokens carrying the old role
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"role":          models.Role(*role),
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	log.Printf("👑 %s is now %s", user.Email, *role)
+	return nil
+}
+
+// runUserGrantCasebucks credits (or with a negative amount, debits) a user's wallet through the ledger
+func runUserGrantCasebucks(cfg *Config, args []string) error {
+	flags := flag.NewFlagSet("user grant-casebucks", flag.ContinueOnError)
+	reason := flags.String("reason", "Case Bucks granted by an operator", "description shown in the user's transaction history")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: user grant-casebucks [-reason text] <email> <amount>")
+	}
+
+	dollars, err := strconv.ParseFloat(flags.Arg(1), 64)
+	if err != nil {
+		return fmt.Errorf("amount must be a number of Case Bucks (e.g. 25.50)")
+	}
+	amount := models.MoneyFromFloat(dollars)
+	if amount == 0 {
+		return fmt.Errorf("amount must not be zero")
+	}
+
+	if err := connectDatabase(cfg); err != nil {
+		return err
+	}
+	if err := ledger.EnsureSystemAccounts(database.DB); err != nil {
+		return err
+	}
+
+	user, err := findUserByEmail(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var transaction *models.Transaction
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var locked models.User
+		if err := database.ForUpdate(tx).First(&locked, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+
+		transaction, err = ledger.Post(tx, &locked, ledger.Posting{
+			Type:        models.TransactionTypeAdjustment,
+			Amount:      amount,
+			Counterpart: ledger.AccountOperatorGrants,
+			Description: *reason,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to grant Case Bucks: %w", err)
+	}
+
+	log.Printf("💰 Granted %s Case Bucks to %s (balance %s)", amount, user.Email, transaction.BalanceAfter)
+	return nil
+}
+
+// findUserByEmail loads a user by email, ignoring case
+func findUserByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("no user with email %s", email)
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return &user, nil
+}
@@ -4,56 +4,69 @@ import (
 	"fmt"
 	"time"
 
-	
+	"github.com/TyronOdame/CS-OPN/backend/models"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 // JWTClams represents the data stored in the JWT
 type JWTClaims struct {
-	UserID 	 uuid.UUID `json:"user_id"`
-	Email  	 string `json:"email"`
-	Username string `json:"username"`
+	UserID       uuid.UUID `json:"user_id"`
+	Email        string    `json:"email"`
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	Permissions  []string  `json:"permissions"`
+	SessionID    uuid.UUID `json:"session_id"`
+	TokenVersion int       `json:"token_version"`
 	jwt.RegisteredClaims
 }
 
-// this generates a JWT for a given user ID and email
-func GenerateJWT(userID uuid.UUID, email, username, secret string) (string, error) {
+// this generates a short-lived access token for a user's session, embedding their role and effective permissions
+func GenerateJWT(user *models.User, sessionID uuid.UUID, secret string, ttl time.Duration) (string, error) {
+	permissions := make([]string, 0, len(user.EffectivePermissions()))
+	for _, permission := range user.EffectivePermissions() {
+		permissions = append(permissions, string(permission))
+	}
+
 	claims := JWTClaims{
-		UserID: userID,
-		Email:  email,
-		Username: username,
+		UserID:       user.ID,
+		Email:        user.Email,
+		Username:     user.Username,
+		Role:         string(user.Role),
+		Permissions:  permissions,
+		SessionID:    sessionID,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expires in 24 hours
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)), // refreshed with the session's refresh token
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		tokenString, err := token.SignedString([]byte(secret))
-		if err != nil {
-			return "", fmt.Errorf("failed to sign token: %w", err)
-		}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
 	return tokenString, nil
 }
 
 // ValidateJWT validates the JWT and returns the claims if valid
 func ValidateJWT(tokenString, secret string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		 // verify signing method
-		 if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// verify signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		 }
-		 return []byte(secret), nil
+		}
+		return []byte(secret), nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
-	
+
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
 		return claims, nil
 	}
 	return nil, fmt.Errorf("invalid token")
 
-}
\ No newline at end of file
+}
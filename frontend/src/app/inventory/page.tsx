@@ -177,7 +177,7 @@ export default function InventoryPage() {
         <div className="mb-8 rounded-lg border border-blue-500/30 bg-blue-900/10 p-4">
           <div className="mb-3 flex items-center gap-2 text-blue-300">
             <Sparkles className="h-5 w-5" />
-            <h2 className="text-lg font-semibold">AI Price Check (Mock)</h2>
+            <h2 className="text-lg font-semibold">AI Price Check</h2>
           </div>
           <div className="flex flex-col gap-2 sm:flex-row">
             <input
@@ -193,7 +193,7 @@ export default function InventoryPage() {
                   setPriceLoading(true);
                   const res = await aiAPI.priceCheck(priceInput.trim());
                   setPriceResult(
-                    `${res.skin_name}: $${res.suggested_usd.toFixed(2)} (${res.provider})`
+                    `${res.market_hash_name}: $${res.suggested_usd.toFixed(2)} (${res.provider}${res.quote.stale ? ', cached' : ''})`
                   );
                 } catch (err) {
                   setPriceResult(
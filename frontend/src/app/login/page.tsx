@@ -22,6 +22,10 @@ export default function LoginPage() {
     confirmPassword: '',
   });
 
+  // set when the account has 2FA and the password step passed
+  const [challengeToken, setChallengeToken] = useState<string | null>(null);
+  const [twoFactorCode, setTwoFactorCode] = useState('');
+
   // add loading state
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
@@ -44,10 +48,20 @@ export default function LoginPage() {
     setLoading(true);
 
     try {
-      await authAPI.login({
+      if (challengeToken) {
+        await authAPI.verifyTwoFactor(challengeToken, twoFactorCode);
+        router.push('/inventory');
+        return;
+      }
+
+      const response = await authAPI.login({
         email: formData.email,
         password: formData.password,
       });
+      if ('two_factor_required' in response && response.two_factor_required) {
+        setChallengeToken(response.challenge_token);
+        return;
+      }
       router.push('/inventory');
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Login failed');
@@ -371,6 +385,35 @@ export default function LoginPage() {
                 </div>
               )}
 
+              {/* 2FA code field - shown after the password step for 2FA accounts */}
+              {currentView === 'login' && challengeToken && (
+                <div className="space-y-2">
+                  <label
+                    htmlFor="twoFactorCode"
+                    className="text-sm font-medium text-white block"
+                  >
+                    Authenticator or recovery code
+                  </label>
+                  <input
+                    id="twoFactorCode"
+                    name="twoFactorCode"
+                    type="text"
+                    inputMode="numeric"
+                    autoComplete="one-time-code"
+                    placeholder="123456"
+                    className="w-full h-12 px-4 border border-zinc-800 focus:ring-0 focus:outline-none shadow-none rounded-lg bg-zinc-900 focus:border-[#ea580c] text-white placeholder:text-zinc-500"
+                    value={twoFactorCode}
+                    onChange={(e) => {
+                      setTwoFactorCode(e.target.value);
+                      if (error) setError(null);
+                    }}
+                    required
+                    autoFocus
+                    disabled={loading}
+                  />
+                </div>
+              )}
+
               {/* Forgot password link */}
               {currentView === 'login' && (
                 <div className="flex items-center justify-end">
@@ -6,7 +6,7 @@ import { Button } from '@/components/ui/button';
 import { Card } from '@/components/ui/card';
 import { Badge } from '@/components/ui/badge';
 import Link from 'next/link';
-import { casesAPI } from '@/lib/api';
+import { casesAPI, imageURL } from '@/lib/api';
 import { Case } from '@/lib/types';
 
 const FALLBACK_IMAGE_SRC = '/file.svg';
@@ -99,7 +99,7 @@ export default function HomePage() {
                   <div className="mb-4 relative">
                     <div className="w-32 h-32 mx-auto bg-muted/20 rounded-lg flex items-center justify-center group-hover:scale-110 transition-transform duration-300">
                       <img
-                        src={caseItem.image_url}
+                        src={imageURL('cases', caseItem.id, 256)}
                         alt={caseItem.name}
                         onError={(event) => {
                           const img = event.currentTarget;
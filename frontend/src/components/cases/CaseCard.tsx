@@ -2,6 +2,7 @@
 'use client';
 
 import { Case } from '@/lib/types';
+import { imageURL } from '@/lib/api';
 import { Card, CardContent, CardFooter } from '@/components/ui/card';
 import { Button } from '@/components/ui/button';
 import { DollarSign, Package } from 'lucide-react';
@@ -24,7 +25,7 @@ export const CaseCard = ({ caseItem, onBuy, userBalance }: CaseCardProps) => {
       {/* Case Image Section */}
       <div className="relative aspect-square bg-gradient-to-br from-gray-800 to-gray-900 overflow-hidden">
         <img
-          src={caseItem.image_url}
+          src={imageURL('cases', caseItem.id, 256)}
           alt={caseItem.name}
           onError={(event) => {
             const img = event.currentTarget;
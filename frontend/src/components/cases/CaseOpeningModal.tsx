@@ -12,7 +12,7 @@ import {
 import { Dialog, DialogContent } from '@/components/ui/dialog';
 import { Button } from '@/components/ui/button';
 import { Sparkles, Package, DollarSign } from 'lucide-react';
-import { casesAPI, inventoryAPI } from '@/lib/api';
+import { casesAPI, imageURL, inventoryAPI } from '@/lib/api';
 
 const FALLBACK_IMAGE_SRC = '/file.svg';
 
@@ -178,7 +178,7 @@ export const CaseOpeningModal = ({
             <div className="mb-6">
               {/* eslint-disable-next-line @next/next/no-img-element */}
               <img
-                src={caseItem.image_url}
+                src={imageURL('cases', caseItem.id, 512)}
                 alt={caseItem.name}
                 onError={(event) => {
                   const img = event.currentTarget;
@@ -265,7 +265,7 @@ export const CaseOpeningModal = ({
                           />
                           {/* eslint-disable-next-line @next/next/no-img-element */}
                           <img
-                            src={skin.image_url}
+                            src={imageURL('skins', skin.id, 128)}
                             alt={skin.name}
                             onError={(event) => {
                               const img = event.currentTarget;
@@ -300,7 +300,7 @@ export const CaseOpeningModal = ({
               <div className="relative inline-block">
                 {/* eslint-disable-next-line @next/next/no-img-element */}
                 <img
-                  src={result.skin.image_url}
+                  src={imageURL('skins', result.skin.id, 512)}
                   alt={result.skin.name}
                   onError={(event) => {
                     const img = event.currentTarget;
@@ -1,4 +1,5 @@
 import Image from 'next/image';
+import { imageURL } from '@/lib/api';
 
 interface Skin {
   id: string;
@@ -69,7 +70,7 @@ export default function SkinCard({ item }: SkinCardProps) {
       {/* Image Container */}
       {item.skin.image_url ? (
         <Image
-          src={item.skin.image_url || FALLBACK_IMAGE_SRC}
+          src={imageURL('skins', item.skin.id, 256)}
           alt={item.skin.name}
           fill
           unoptimized
@@ -5,7 +5,7 @@ import { Badge } from '@/components/ui/badge';
 import { Button } from '@/components/ui/button';
 import Image from 'next/image';
 import { useState } from 'react';
-import { inventoryAPI } from '@/lib/api';
+import { imageURL, inventoryAPI } from '@/lib/api';
 
 interface Skin {
   id: string;
@@ -85,7 +85,7 @@ export default function SkinDetailView({ item }: SkinDetailViewProps) {
             <div className="relative w-full bg-gradient-to-br from-card to-background p-8 aspect-square flex items-center justify-center">
               {item.skin.image_url ? (
                 <Image
-                  src={item.skin.image_url || FALLBACK_IMAGE_SRC}
+                  src={imageURL('skins', item.skin.id, 512)}
                   alt={item.skin.name}
                   width={500}
                   height={500}
@@ -2,6 +2,7 @@ import {
   InventoryResponse,
   TransactionsResponse,
   AuthResponse,
+  TwoFactorChallengeResponse,
   LoginRequest,
   RegisterRequest,
   User,
@@ -11,10 +12,23 @@ import {
   PurchasedCasesResponse,
   CaseBuyResult,
   PriceCheckResponse,
+  ChatResponse,
+  ChatThreadsResponse,
+  ChatThreadResponse,
+  PriceHistoryRange,
+  PriceHistoryResponse,
 } from './types';
 
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080';
 
+// Skin and case images go through the backend image proxy, which caches them,
+// serves resized variants (128, 256 or 512px wide) and falls back to a placeholder
+export const imageURL = (
+  kind: 'skins' | 'cases',
+  id: string,
+  width?: 128 | 256 | 512
+): string => `${API_BASE_URL}/images/${kind}/${id}${width ? `?w=${width}` : ''}`;
+
 const getConditionFromFloat = (float: number): string => {
   if (float < 0.07) return 'Factory New';
   if (float < 0.15) return 'Minimal Wear';
@@ -46,12 +60,46 @@ export const setAuthToken = (token: string): void => {
 export const removeAuthToken = (): void => {
   if (typeof window !== 'undefined') {
     localStorage.removeItem('auth_token');
+    localStorage.removeItem('refresh_token');
+  }
+};
+
+const getRefreshToken = (): string | null => {
+  if (typeof window === 'undefined') return null;
+  return localStorage.getItem('refresh_token');
+};
+
+const setSessionTokens = (response: AuthResponse): void => {
+  setAuthToken(response.token);
+  if (typeof window !== 'undefined' && response.refresh_token) {
+    localStorage.setItem('refresh_token', response.refresh_token);
   }
 };
 
+// access tokens are short-lived, so trade the refresh token for a new pair
+const refreshSession = async (): Promise<boolean> => {
+  const refreshToken = getRefreshToken();
+  if (!refreshToken) return false;
+
+  const response = await fetch(`${API_BASE_URL}/auth/refresh`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refresh_token: refreshToken }),
+  });
+
+  if (!response.ok) {
+    removeAuthToken();
+    return false;
+  }
+
+  setSessionTokens(await response.json());
+  return true;
+};
+
 const authenticatedFetch = async (
   endpoint: string,
-  options: RequestInit = {}
+  options: RequestInit = {},
+  retry = true
 ) => {
   const token = getAuthToken();
 
@@ -59,14 +107,21 @@ const authenticatedFetch = async (
     throw new Error('Not authenticated. Please login.');
   }
 
-  const response = await fetch(`${API_BASE_URL}${endpoint}`, {
-    ...options,
-    headers: {
-      'Content-Type': 'application/json',
-      Authorization: `Bearer ${token}`,
-      ...options.headers,
-    },
-  });
+  const send = (accessToken: string) =>
+    fetch(`${API_BASE_URL}${endpoint}`, {
+      ...options,
+      headers: {
+        'Content-Type': 'application/json',
+        Authorization: `Bearer ${accessToken}`,
+        ...options.headers,
+      },
+    });
+
+  let response = await send(token);
+
+  if (response.status === 401 && retry && (await refreshSession())) {
+    response = await send(getAuthToken() as string);
+  }
 
   if (!response.ok) {
     const error = await response
@@ -98,12 +153,29 @@ const publicFetch = async (endpoint: string, options: RequestInit = {}) => {
 };
 
 export const authAPI = {
-  login: async (credentials: LoginRequest): Promise<AuthResponse> => {
+  login: async (
+    credentials: LoginRequest
+  ): Promise<AuthResponse | TwoFactorChallengeResponse> => {
     const response = await publicFetch('/auth/login', {
       method: 'POST',
       body: JSON.stringify(credentials),
     });
-    setAuthToken(response.token);
+    // accounts with 2FA finish logging in through verifyTwoFactor
+    if (!response.two_factor_required) {
+      setSessionTokens(response);
+    }
+    return response;
+  },
+
+  verifyTwoFactor: async (
+    challengeToken: string,
+    code: string
+  ): Promise<AuthResponse> => {
+    const response = await publicFetch('/auth/2fa/verify', {
+      method: 'POST',
+      body: JSON.stringify({ challenge_token: challengeToken, code }),
+    });
+    setSessionTokens(response);
     return response;
   },
 
@@ -112,12 +184,18 @@ export const authAPI = {
       method: 'POST',
       body: JSON.stringify(data),
     });
-    setAuthToken(response.token);
+    setSessionTokens(response);
     return response;
   },
 
-  logout: () => {
-    removeAuthToken();
+  logout: async (): Promise<void> => {
+    try {
+      await authenticatedFetch('/auth/logout', { method: 'POST' }, false);
+    } catch {
+      // the session may already be gone; clear local tokens regardless
+    } finally {
+      removeAuthToken();
+    }
   },
 
   isAuthenticated: (): boolean => {
@@ -209,13 +287,31 @@ export const casesAPI = {
     });
   },
 
-  buyCase: async (caseId: string): Promise<CaseBuyResult> => {
+  buyCase: async (
+    caseId: string,
+    quantity = 1,
+    open = false
+  ): Promise<CaseBuyResult> => {
     return authenticatedFetch(`/cases/${caseId}/buy`, {
       method: 'POST',
+      body: JSON.stringify({ quantity, open }),
     });
   },
 };
 
+export const skinsAPI = {
+  // public endpoint: recorded market prices per wear condition, bucketed over the range
+  getPriceHistory: async (
+    skinId: string,
+    range: PriceHistoryRange = '30d',
+    condition?: string
+  ): Promise<PriceHistoryResponse> => {
+    const params = new URLSearchParams({ range });
+    if (condition) params.append('condition', condition);
+    return publicFetch(`/skins/${skinId}/price-history?${params}`);
+  },
+};
+
 export const aiAPI = {
   priceCheck: async (skinName: string): Promise<PriceCheckResponse> => {
     return authenticatedFetch('/ai/price-check', {
@@ -223,4 +319,24 @@ export const aiAPI = {
       body: JSON.stringify({ skin_name: skinName }),
     });
   },
+
+  // price-check assistant; omit threadId to start a new conversation
+  chat: async (message: string, threadId?: string): Promise<ChatResponse> => {
+    return authenticatedFetch('/ai/chat', {
+      method: 'POST',
+      body: JSON.stringify({ message, thread_id: threadId }),
+    });
+  },
+
+  getChatThreads: async (): Promise<ChatThreadsResponse> => {
+    return authenticatedFetch('/ai/chat/threads');
+  },
+
+  getChatThread: async (threadId: string): Promise<ChatThreadResponse> => {
+    return authenticatedFetch(`/ai/chat/threads/${threadId}`);
+  },
+
+  deleteChatThread: async (threadId: string): Promise<{ message: string }> => {
+    return authenticatedFetch(`/ai/chat/threads/${threadId}`, { method: 'DELETE' });
+  },
 };
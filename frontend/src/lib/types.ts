@@ -4,18 +4,24 @@ export interface User {
   email: string;
   username: string;
   casebucks: number;
+  email_verified?: boolean;
   created_at: string;
 }
 
 // Skin types
 export interface Skin {
   id: string;
+  slug: string;
   name: string;
   weapon_type: string;
   rarity: string;
   image_url: string;
   min_value: number;
   max_value: number;
+  min_float: number;
+  max_float: number;
+  prices: Record<string, number>;
+  conditions: string[];
 }
 
 // Inventory item
@@ -25,6 +31,10 @@ export interface InventoryItem {
   float: number;
   condition: string;
   value: number;
+  is_stattrak: boolean;
+  is_souvenir: boolean;
+  pattern_seed: number;
+  pattern_tier?: string;
   acquired_from: string;
   acquired_at: string;
   is_sold: boolean;
@@ -50,6 +60,7 @@ export interface Transaction {
   description: string;
   created_at: string;
   reference_id?: string;
+  pattern_tier?: string;
 }
 
 export interface TransactionsResponse {
@@ -71,18 +82,30 @@ export interface RegisterRequest {
 
 export interface AuthResponse {
   token: string;
+  refresh_token: string;
+  expires_in: number;
+  session_id: string;
   user: User;
   daily_reward_claimed?: boolean;
   daily_reward_amount?: number;
 }
 
+export interface TwoFactorChallengeResponse {
+  two_factor_required: true;
+  challenge_token: string;
+  expires_in: number;
+}
+
 // case types
 export interface Case {
   id: string;
+  slug: string;
   name: string;
   description: string;
   image_url: string;
   price: number;
+  stattrak_chance: number;
+  is_souvenir_package: boolean;
   created_at: string;
   updated_at: string;
 }
@@ -103,6 +126,10 @@ export interface CaseOpenResult {
   skin: Skin;
   float: number;
   condition: string;
+  stattrak: boolean;
+  souvenir: boolean;
+  pattern_seed: number;
+  pattern_tier: string;
   value: number;
   new_balance: number;
   inventory_id: string;
@@ -128,18 +155,105 @@ export interface PurchasedCasesResponse {
 export interface CaseBuyResult {
   message: string;
   purchased_case: PurchasedCase;
+  purchased_cases: PurchasedCase[];
+  quantity: number;
+  subtotal: number;
+  discount: number;
+  total_price: number;
   case: Case;
   new_balance: number;
   transaction_id: string;
 }
 
+export interface PriceQuote {
+  market_hash_name: string;
+  currency: string;
+  suggested: number;
+  min?: number;
+  max?: number;
+  median?: number;
+  quantity?: number;
+  provider: string;
+  fetched_at: string;
+  stale?: boolean;
+}
+
 export interface PriceCheckResponse {
   provider: string;
   skin_name: string;
+  market_hash_name: string;
   suggested_usd: number;
+  quote: PriceQuote;
   message: string;
 }
 
+export interface ChatThread {
+  id: string;
+  user_id: string;
+  title: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ChatMessage {
+  id: string;
+  thread_id: string;
+  role: 'user' | 'assistant' | 'tool';
+  content: string;
+  tool_calls?: { id: string; name: string; arguments: string }[];
+  tool_call_id?: string;
+  created_at: string;
+}
+
+export interface ChatUsage {
+  messages_today: number;
+  tokens_today: number;
+  daily_messages: number; // 0 means unlimited
+  daily_tokens: number;
+  resets_at: string;
+}
+
+export interface ChatResponse {
+  thread: ChatThread;
+  reply: string;
+  messages: ChatMessage[];
+  model: string;
+  usage: ChatUsage;
+}
+
+export interface ChatThreadsResponse {
+  threads: ChatThread[];
+  usage: ChatUsage;
+}
+
+export interface ChatThreadResponse {
+  thread: ChatThread;
+  messages: ChatMessage[];
+}
+
+export type PriceHistoryRange = '24h' | '7d' | '30d' | '90d' | '1y';
+
+export interface PriceHistoryPoint {
+  time: string;
+  average: number;
+  min: number;
+  max: number;
+  samples: number;
+}
+
+export interface PriceHistoryResponse {
+  skin_id: string;
+  range: PriceHistoryRange;
+  bucket: string;
+  from: string;
+  to: string;
+  currency: string;
+  series: {
+    condition: string;
+    points: PriceHistoryPoint[];
+  }[];
+}
+
 // Rarity colors for UI
 export const RARITY_COLORS: Record<string, string> = {
   'Consumer Grade': 'from-gray-400 to-gray-500',